@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"log"
+	"time"
 
 	"server/internal/adapter/controller/http"
 	"server/internal/adapter/repository/mongodb"
+	"server/internal/domain/entity"
+	"server/internal/domain/service"
 	"server/internal/infrastructure/config"
 	"server/internal/infrastructure/database"
+	"server/internal/infrastructure/mail"
+	"server/internal/infrastructure/oauth"
 	"server/internal/infrastructure/router"
+	"server/internal/infrastructure/security"
 	dashboardUseCase "server/internal/usecase/dashboard"
 	recommendationUseCase "server/internal/usecase/recommendation"
 	reviewUseCase "server/internal/usecase/review"
@@ -30,24 +38,120 @@ func main() {
 	userRepo := mongodb.NewUserRepository(db)
 	testRepo := mongodb.NewTestRepository(db)
 	userAnswerRepo := mongodb.NewUserAnswerRepository(db)
+	attemptSessionRepo := mongodb.NewAttemptSessionRepository(db)
 	reviewRepo := mongodb.NewReviewRepository(db)
 	recommendationRepo := mongodb.NewRecommendationRepository(db)
 	dashboardRepo := mongodb.NewDashboardRepository(db)
+	passwordResetRepo := mongodb.NewPasswordResetRepository(db)
+	anonymousAttemptRepo := mongodb.NewAnonymousAttemptRepository(db)
+	itemAnalysisRepo := mongodb.NewItemAnalysisRepository(db)
+	normRepo := mongodb.NewNormRepository(db)
 	log.Println("✓ Репозитории инициализированы")
 
-	// 4. Initialize use cases
+	indexCtx, cancelIndexes := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := testRepo.EnsureIndexes(indexCtx); err != nil {
+		log.Println("⚠ Не удалось создать индексы тестов: поиск тестов недоступен")
+	}
+	if err := attemptSessionRepo.EnsureIndexes(indexCtx); err != nil {
+		log.Println("⚠ Не удалось создать индексы попыток: истекшие попытки не удаляются")
+	}
+	if err := anonymousAttemptRepo.EnsureIndexes(indexCtx); err != nil {
+		log.Println("⚠ Не удалось создать индексы анонимных попыток: истекшие попытки не удаляются")
+	}
+	if err := userAnswerRepo.EnsureIndexes(indexCtx); err != nil {
+		log.Println("⚠ Не удалось создать индексы ответов: ограничение повторного прохождения не защищено от одновременных попыток")
+	}
+	if err := passwordResetRepo.EnsureIndexes(indexCtx); err != nil {
+		log.Println("⚠ Не удалось создать индексы сброса пароля: истекшие запросы не удаляются")
+	}
+	if err := normRepo.EnsureIndexes(indexCtx); err != nil {
+		log.Println("⚠ Не удалось создать индексы норм: версии норм могут повторяться")
+	}
+	cancelIndexes()
+
+	// 4. Initialize security services
+	tokenSecret := []byte(cfg.Auth.TokenSecret)
+	if len(tokenSecret) == 0 {
+		tokenSecret = make([]byte, 32)
+		if _, err := rand.Read(tokenSecret); err != nil {
+			log.Fatal("✗ Не удалось сгенерировать секрет токенов:", err)
+		}
+		log.Println("⚠ AUTH_TOKEN_SECRET не задан: используется случайный секрет, токены сбросятся при перезапуске")
+	}
+	tokenManager := security.NewHMACTokenManager(tokenSecret)
+	passwordHasher := security.NewBcryptPasswordHasher(cfg.Auth.BcryptCost)
+	log.Println("✓ Сервисы безопасности инициализированы")
+
+	var mailer service.Mailer
+	switch cfg.Mail.Driver {
+	case "smtp":
+		mailer = mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     cfg.Mail.SMTPHost,
+			Port:     cfg.Mail.SMTPPort,
+			Username: cfg.Mail.SMTPUsername,
+			Password: cfg.Mail.SMTPPassword,
+			From:     cfg.Mail.From,
+		})
+		log.Printf("✓ Почта: SMTP %s:%s", cfg.Mail.SMTPHost, cfg.Mail.SMTPPort)
+	default:
+		mailer = mail.NewLogMailer(cfg.Mail.From, cfg.Mail.OutputDir)
+		log.Printf("✓ Почта: письма пишутся в лог и %s", cfg.Mail.OutputDir)
+	}
+
+	oauthStateManager := security.NewHMACOAuthStateManager(tokenSecret)
+	oauthProviders := map[entity.OAuthProvider]service.OAuthClient{}
+	if google := oauthProviderConfig(cfg.OAuth.Google); google.IsConfigured() {
+		oauthProviders[entity.OAuthProviderGoogle] = oauth.NewGoogleClient(google)
+		log.Println("✓ OAuth: Google подключен")
+	}
+	if yandex := oauthProviderConfig(cfg.OAuth.Yandex); yandex.IsConfigured() {
+		oauthProviders[entity.OAuthProviderYandex] = oauth.NewYandexClient(yandex)
+		log.Println("✓ OAuth: Яндекс подключен")
+	}
+
+	// 5. Initialize use cases
 
 	// Auth use cases
-	loginUC := userUseCase.NewLoginUseCase(userRepo)
-	registerUC := userUseCase.NewRegisterUseCase(userRepo)
+	loginUC := userUseCase.NewLoginUseCase(userRepo, passwordHasher, tokenManager, cfg.Auth.AccessTokenTTL)
+	registerUC := userUseCase.NewRegisterUseCase(userRepo, passwordHasher)
+	logoutUC := userUseCase.NewLogoutUseCase(userRepo)
+	authenticateUC := userUseCase.NewAuthenticateUseCase(userRepo, tokenManager)
+	requestPasswordResetUC := userUseCase.NewRequestPasswordResetUseCase(
+		userRepo, passwordResetRepo, mailer, cfg.Auth.PasswordResetTTL, cfg.Auth.PasswordResetURL,
+	)
+	confirmPasswordResetUC := userUseCase.NewConfirmPasswordResetUseCase(userRepo, passwordResetRepo, passwordHasher)
+	startOAuthUC := userUseCase.NewStartOAuthUseCase(oauthProviders, oauthStateManager, cfg.OAuth.StateTTL)
+	oauthLoginUC := userUseCase.NewOAuthLoginUseCase(
+		userRepo, oauthProviders, oauthStateManager, tokenManager, cfg.Auth.AccessTokenTTL,
+	)
+	unlinkOAuthUC := userUseCase.NewUnlinkOAuthUseCase(userRepo)
 
 	// Test use cases
 	getTestsUC := testUseCase.NewGetTestsUseCase(testRepo, userAnswerRepo)
-	getQuestionsUC := testUseCase.NewGetQuestionsUseCase(testRepo)
-	attemptTestUC := testUseCase.NewAttemptTestUseCase(userAnswerRepo)
+	searchTestsUC := testUseCase.NewSearchTestsUseCase(testRepo, userAnswerRepo)
+	addCollaboratorUC := testUseCase.NewAddCollaboratorUseCase(testRepo, userRepo)
+	removeCollaboratorUC := testUseCase.NewRemoveCollaboratorUseCase(testRepo)
+	transferOwnershipUC := testUseCase.NewTransferOwnershipUseCase(testRepo, userRepo)
+	cloneTestUC := testUseCase.NewCloneTestUseCase(testRepo)
+	getQuestionsUC := testUseCase.NewGetQuestionsUseCase(testRepo, attemptSessionRepo)
+	attemptTestUC := testUseCase.NewAttemptTestUseCase(
+		testRepo, userAnswerRepo, userRepo, anonymousAttemptRepo, normRepo, cfg.Attempts.AnonymousTTL,
+	)
 	addTestUC := testUseCase.NewAddTestUseCase(testRepo)
 	changeTestUC := testUseCase.NewChangeTestUseCase(testRepo)
 	deleteTestUC := testUseCase.NewDeleteTestUseCase(testRepo)
+	recomputeResultsUC := testUseCase.NewRecomputeResultsUseCase(testRepo, userAnswerRepo)
+	changeTestStatusUC := testUseCase.NewChangeTestStatusUseCase(testRepo)
+	getManagedTestsUC := testUseCase.NewGetManagedTestsUseCase(testRepo)
+	exportTestUC := testUseCase.NewExportTestUseCase(testRepo)
+	importTestUC := testUseCase.NewImportTestUseCase(testRepo)
+	importCSVTestUC := testUseCase.NewImportCSVTestUseCase(testRepo)
+	startAttemptUC := testUseCase.NewStartAttemptUseCase(testRepo, attemptSessionRepo, userAnswerRepo, cfg.Attempts.SessionTTL)
+	openQuestionUC := testUseCase.NewOpenQuestionUseCase(testRepo, attemptSessionRepo, cfg.Attempts.SessionTTL)
+	saveAttemptAnswerUC := testUseCase.NewSaveAttemptAnswerUseCase(testRepo, attemptSessionRepo, cfg.Attempts.SessionTTL)
+	submitAttemptUC := testUseCase.NewSubmitAttemptUseCase(testRepo, attemptSessionRepo, attemptTestUC)
+	getActiveAttemptsUC := testUseCase.NewGetActiveAttemptsUseCase(attemptSessionRepo)
+	claimAttemptUC := testUseCase.NewClaimAttemptUseCase(anonymousAttemptRepo, attemptTestUC)
 
 	// Review use cases
 	getReviewsUC := reviewUseCase.NewGetReviewsUseCase(reviewRepo)
@@ -68,16 +172,32 @@ func main() {
 	getUsersUC := dashboardUseCase.NewGetUsersUseCase(dashboardRepo)
 	blockUserUC := dashboardUseCase.NewBlockUserUseCase(dashboardRepo)
 	deleteUserUC := dashboardUseCase.NewDeleteUserUseCase(dashboardRepo)
+	changeUserRoleUC := dashboardUseCase.NewChangeUserRoleUseCase(dashboardRepo)
 	deleteAccountUC := dashboardUseCase.NewDeleteAccountUseCase(dashboardRepo)
 	changeUserDataUC := dashboardUseCase.NewChangeUserDataUseCase(dashboardRepo)
-	getCompletedTestsUC := dashboardUseCase.NewGetCompletedTestsUseCase(dashboardRepo, testRepo)
+	getCompletedTestsUC := dashboardUseCase.NewGetCompletedTestsUseCase(dashboardRepo, testRepo, normRepo)
 	getUserAnswersUC := dashboardUseCase.NewGetUserAnswersUseCase(dashboardRepo, testRepo)
+	psychoTypeHistoryUC := dashboardUseCase.NewGetPsychoTypeHistoryUseCase(dashboardRepo, testRepo)
+	attemptHistoryUC := dashboardUseCase.NewGetAttemptHistoryUseCase(dashboardRepo, testRepo)
+	itemAnalysisUC := dashboardUseCase.NewGetItemAnalysisUseCase(testRepo, userAnswerRepo, itemAnalysisRepo)
+	buildNormsUC := dashboardUseCase.NewBuildNormsUseCase(testRepo, userAnswerRepo, normRepo)
+	freezeNormsUC := dashboardUseCase.NewFreezeNormsUseCase(normRepo)
+	getNormsUC := dashboardUseCase.NewGetNormsUseCase(testRepo, normRepo)
 	terminalCommandsUC := dashboardUseCase.NewTerminalCommandsUseCase()
 
 	log.Println("✓ Use Cases инициализированы")
 
-	// 5. Initialize controllers
-	authController := http.NewAuthController(loginUC, registerUC)
+	// 6. Initialize controllers
+	authController := http.NewAuthController(
+		loginUC,
+		registerUC,
+		logoutUC,
+		requestPasswordResetUC,
+		confirmPasswordResetUC,
+		startOAuthUC,
+		oauthLoginUC,
+		unlinkOAuthUC,
+	)
 	testController := http.NewTestController(
 		getTestsUC,
 		getQuestionsUC,
@@ -85,6 +205,23 @@ func main() {
 		addTestUC,
 		changeTestUC,
 		deleteTestUC,
+		recomputeResultsUC,
+		changeTestStatusUC,
+		getManagedTestsUC,
+		exportTestUC,
+		importTestUC,
+		importCSVTestUC,
+		startAttemptUC,
+		openQuestionUC,
+		saveAttemptAnswerUC,
+		submitAttemptUC,
+		getActiveAttemptsUC,
+		claimAttemptUC,
+		searchTestsUC,
+		addCollaboratorUC,
+		removeCollaboratorUC,
+		transferOwnershipUC,
+		cloneTestUC,
 	)
 	reviewController := http.NewReviewController(
 		getReviewsUC,
@@ -105,27 +242,34 @@ func main() {
 		getUsersUC,
 		blockUserUC,
 		deleteUserUC,
+		changeUserRoleUC,
 		deleteAccountUC,
 		changeUserDataUC,
 		getCompletedTestsUC,
 		getUserAnswersUC,
+		psychoTypeHistoryUC,
+		attemptHistoryUC,
+		itemAnalysisUC,
+		buildNormsUC,
+		freezeNormsUC,
+		getNormsUC,
 		terminalCommandsUC,
 	)
 	log.Println("✓ Контроллеры инициализированы")
 
-	// 6. Setup router
+	// 7. Setup router
 	r := router.NewRouter(router.Controllers{
 		Auth:           authController,
 		Test:           testController,
 		Review:         reviewController,
 		Recommendation: recommendationController,
 		Dashboard:      dashboardController,
-	})
+	}, authenticateUC)
 
 	log.Println("✓ Роутер настроен")
 	log.Println("")
 	log.Println("📦 Статус модулей:")
-	log.Println("  ✅ Auth (Login, Register) - РАБОТАЕТ")
+	log.Println("  ✅ Auth (Login, Register, Logout, Bearer-токены, роли, сброс пароля, OAuth) - РАБОТАЕТ")
 	log.Println("  ✅ Test - РАБОТАЕТ")
 	log.Println("  ✅ Review - РАБОТАЕТ")
 	log.Println("  ✅ Recommendation - РАБОТАЕТ")
@@ -138,3 +282,16 @@ func main() {
 		log.Fatal("✗ Ошибка запуска сервера:", err)
 	}
 }
+
+// oauthProviderConfig переводит настройки провайдера из конфигурации в параметры OAuth2-клиента
+func oauthProviderConfig(provider config.OAuthProviderConfig) oauth.ProviderConfig {
+	return oauth.ProviderConfig{
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		AuthURL:      provider.AuthURL,
+		TokenURL:     provider.TokenURL,
+		UserInfoURL:  provider.UserInfoURL,
+		RedirectURL:  provider.RedirectURL,
+		Scopes:       provider.Scopes,
+	}
+}
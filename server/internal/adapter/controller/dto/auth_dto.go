@@ -8,15 +8,20 @@ type LoginRequest struct {
 
 // LoginResponse - ответ на успешный логин
 type LoginResponse struct {
-	Success       string `json:"success"`
-	ID            string `json:"id"`
-	FirstName     string `json:"firstName"`
-	Email         string `json:"email"`
-	Status        string `json:"status"`
-	PsychoType    string `json:"psychoType"`
-	Date          string `json:"date"`
-	IsGoogleAdded bool   `json:"isGoogleAdded"`
-	IsYandexAdded bool   `json:"isYandexAdded"`
+	Success       string   `json:"success"`
+	ID            string   `json:"id"`
+	FirstName     string   `json:"firstName"`
+	Email         string   `json:"email"`
+	Status        string   `json:"status"`
+	Role          string   `json:"role"`
+	Permissions   []string `json:"permissions"`
+	PsychoType    string   `json:"psychoType"`
+	Date          string   `json:"date"`
+	IsGoogleAdded bool     `json:"isGoogleAdded"`
+	IsYandexAdded bool     `json:"isYandexAdded"`
+	AccessToken   string   `json:"accessToken"`
+	TokenType     string   `json:"tokenType"`
+	ExpiresAt     string   `json:"expiresAt"`
 }
 
 // RegisterRequest - входные данные для регистрации
@@ -32,9 +37,45 @@ type RegisterResponse struct {
 	Success string `json:"success"`
 }
 
+// LostPasswordRequest - запрос ссылки для сброса пароля
+type LostPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest - установка нового пароля по токену из письма
+type ResetPasswordRequest struct {
+	Token          string `json:"token"`
+	Password       string `json:"password"`
+	PasswordRepeat string `json:"passwordRepeat"`
+}
+
+// OAuthURLResponse - адрес страницы авторизации провайдера
+type OAuthURLResponse struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	State            string `json:"state"`
+}
+
+// OAuthLoginRequest - код авторизации и state, возвращенные провайдером на redirect URI
+type OAuthLoginRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+// UnlinkProviderRequest - запрос на отвязку внешнего провайдера
+type UnlinkProviderRequest struct {
+	Provider string `json:"provider"`
+}
+
 // ErrorResponse - стандартный ответ с ошибкой
 type ErrorResponse struct {
-	Error   string `json:"error,omitempty"`
-	Status  string `json:"status,omitempty"`
-	Message string `json:"message,omitempty"`
+	Error   string               `json:"error,omitempty"`
+	Status  string               `json:"status,omitempty"`
+	Message string               `json:"message,omitempty"`
+	Fields  []FieldErrorResponse `json:"fields,omitempty"`
+}
+
+// FieldErrorResponse - ошибка валидации конкретного поля запроса
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+// ExportTestRequest - запрос на выгрузку теста. Format: json (по умолчанию) или yaml;
+// Version - версия теста, по умолчанию текущая
+type ExportTestRequest struct {
+	TestID  string `json:"testId"`
+	Version int    `json:"version"`
+	Format  string `json:"format"`
+}
+
+// TestBundle - переносимый пакет теста в формате JSON или YAML. Вопросы и правила
+// подсчета описываются так же, как в запросе на создание теста
+type TestBundle struct {
+	Format        string               `json:"format"`
+	FormatVersion int                  `json:"formatVersion"`
+	ExportedAt    *time.Time           `json:"exportedAt,omitempty"`
+	Source        *TestBundleSource    `json:"source,omitempty"`
+	TestName      string               `json:"testName"`
+	AuthorsName   []string             `json:"authorsName"`
+	Description   string               `json:"description"`
+	Category      string               `json:"category,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	IsTyping      bool                 `json:"isTyping,omitempty"`
+	Questions     []QuestionInput      `json:"questions"`
+	ResultLogic   ResultsLogicRequest  `json:"resultLogic"`
+	TimeLimits    *TimeLimitsRequest   `json:"timeLimits,omitempty"`
+	Retake        *RetakePolicyRequest `json:"retake,omitempty"`
+	Shuffle       *ShuffleRequest      `json:"shuffle,omitempty"`
+}
+
+// TestBundleSource - тест и версия, из которых выгружен пакет; при импорте не используется
+type TestBundleSource struct {
+	TestID  string `json:"testId"`
+	Version int    `json:"version"`
+}
+
+// ImportTestResponse - отчет об импорте пакета. TestID заполнен, если тест создан
+type ImportTestResponse struct {
+	Valid         bool                 `json:"valid"`
+	DryRun        bool                 `json:"dryRun"`
+	TestID        string               `json:"testId,omitempty"`
+	QuestionCount int                  `json:"questionCount"`
+	ScaleCount    int                  `json:"scaleCount"`
+	RuleCount     int                  `json:"ruleCount"`
+	Problems      []FieldErrorResponse `json:"problems"`
+}
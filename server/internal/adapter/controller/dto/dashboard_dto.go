@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // UserResponse - пользователь в ответе
 type UserResponse struct {
 	ID            string `json:"id"`
@@ -7,18 +9,13 @@ type UserResponse struct {
 	LastName      string `json:"lastName"`
 	Email         string `json:"email"`
 	Status        string `json:"status"`
+	Role          string `json:"role"`
 	PsychoType    string `json:"psychoType"`
 	Date          string `json:"date"`
 	IsGoogleAdded bool   `json:"isGoogleAdded"`
 	IsYandexAdded bool   `json:"isYandexAdded"`
 }
 
-// GetUsersRequest - запрос на получение пользователей
-type GetUsersRequest struct {
-	UserID string `json:"userId"`
-	Status string `json:"status"`
-}
-
 // GetUsersResponse - ответ на получение пользователей
 type GetUsersResponse struct {
 	Users []UserResponse `json:"users"`
@@ -26,40 +23,38 @@ type GetUsersResponse struct {
 
 // BlockUserRequest - запрос на блокировку пользователя
 type BlockUserRequest struct {
-	AdminID  string `json:"adminId"`
 	TargetID string `json:"targetId"`
 }
 
-// DeleteUserRequest - запрос на удаление пользователя
-type DeleteUserRequest struct {
-	AdminID  string `json:"adminId"`
+// ChangeUserRoleRequest - запрос на изменение роли пользователя
+type ChangeUserRoleRequest struct {
 	TargetID string `json:"targetId"`
+	Role     string `json:"role"`
 }
 
-// DeleteAccountRequest - запрос на удаление аккаунта
-type DeleteAccountRequest struct {
-	UserID string `json:"userId"`
+// DeleteUserRequest - запрос на удаление пользователя
+type DeleteUserRequest struct {
+	TargetID string `json:"targetId"`
 }
 
 // ChangeUserDataRequest - запрос на изменение данных пользователя
 type ChangeUserDataRequest struct {
-	UserID    string `json:"userId"`
 	FirstName string `json:"firstName"`
 	LastName  string `json:"lastName"`
 }
 
-// GetCompletedTestsRequest - запрос на получение пройденных тестов
-type GetCompletedTestsRequest struct {
-	UserID string `json:"userId"`
-}
-
-// CompletedTestResponse - пройденный тест
+// CompletedTestResponse - пройденный тест. NormVersion - версия норм, по которой
+// посчитаны процентили баллов; отсутствует, если нормы не закреплены
 type CompletedTestResponse struct {
-	ID       string `json:"id"`
-	TestID   string `json:"testId"`
-	TestName string `json:"testName"`
-	Result   string `json:"result"`
-	Date     string `json:"date"`
+	ID          string               `json:"id"`
+	TestID      string               `json:"testId"`
+	TestName    string               `json:"testName"`
+	Version     int                  `json:"version"`
+	Result      string               `json:"result"`
+	Scores      []ScaleScoreResponse `json:"scores"`
+	Dominant    []string             `json:"dominant"`
+	Date        string               `json:"date"`
+	NormVersion int                  `json:"normVersion,omitempty"`
 }
 
 // GetCompletedTestsResponse - ответ на получение пройденных тестов
@@ -67,16 +62,226 @@ type GetCompletedTestsResponse struct {
 	Tests []CompletedTestResponse `json:"tests"`
 }
 
-// GetUserAnswersRequest - запрос на получение ответов
+// PsychoTypeHistoryEntryResponse - запись истории психотипа
+type PsychoTypeHistoryEntryResponse struct {
+	PsychoType string    `json:"psychoType"`
+	TestID     string    `json:"testId"`
+	TestName   string    `json:"testName"`
+	AttemptID  string    `json:"attemptId"`
+	AssignedAt time.Time `json:"assignedAt"`
+}
+
+// GetPsychoTypeHistoryResponse - ответ на получение истории психотипа (от новых записей к старым)
+type GetPsychoTypeHistoryResponse struct {
+	PsychoType string                           `json:"psychoType"`
+	History    []PsychoTypeHistoryEntryResponse `json:"history"`
+}
+
+// GetUserAnswersRequest - запрос на получение ответов. TestID необязателен
 type GetUserAnswersRequest struct {
 	CompletedTestID string `json:"completedTestId"`
 	TestID          string `json:"testId"`
 }
 
-// GetUserAnswersResponse - ответ на получение ответов
+// GetUserAnswersResponse - ответ на получение ответов. Answers содержит ответы с вариантами
+// в прежнем формате [ID вопроса, ID варианта...], Responses - ответы на вопросы всех типов
 type GetUserAnswersResponse struct {
-	Answers   [][]int            `json:"answers"`
-	Questions []QuestionResponse `json:"questions"`
+	TestID    string                   `json:"testId"`
+	Version   int                      `json:"version"`
+	Answers   [][]int                  `json:"answers"`
+	Responses []QuestionAnswerResponse `json:"responses"`
+	Questions []QuestionResponse       `json:"questions"`
+}
+
+// QuestionAnswerResponse - ответ на вопрос. TimeSpentMs - время ответа в миллисекундах,
+// если оно измерено сервером
+type QuestionAnswerResponse struct {
+	QuestionID  int                  `json:"questionId"`
+	OptionIDs   []int                `json:"optionIds,omitempty"`
+	Value       *int                 `json:"value,omitempty"`
+	Text        string               `json:"text,omitempty"`
+	Cells       []MatrixCellResponse `json:"cells,omitempty"`
+	TimeSpentMs int64                `json:"timeSpentMs,omitempty"`
+}
+
+// MatrixCellResponse - выбранный столбец в строке матричного вопроса
+type MatrixCellResponse struct {
+	RowID    int `json:"rowId"`
+	OptionID int `json:"optionId"`
+}
+
+// GetAttemptHistoryRequest - запрос на сравнение попыток прохождения теста.
+// UserID необязателен: по умолчанию - вызывающий пользователь
+type GetAttemptHistoryRequest struct {
+	TestID string `json:"testId"`
+	UserID string `json:"userId"`
+}
+
+// ScoreChangeResponse - баллы шкалы и их изменение по сравнению с предыдущей попыткой.
+// Previous отсутствует, если в предыдущей попытке баллов по шкале не было
+type ScoreChangeResponse struct {
+	ScaleID      string   `json:"scaleId"`
+	Name         string   `json:"name"`
+	Score        float64  `json:"score"`
+	Band         string   `json:"band,omitempty"`
+	Previous     *float64 `json:"previous,omitempty"`
+	PreviousBand string   `json:"previousBand,omitempty"`
+	Delta        float64  `json:"delta"`
+}
+
+// AttemptHistoryEntryResponse - попытка прохождения теста с изменением баллов
+type AttemptHistoryEntryResponse struct {
+	ID          string                `json:"id"`
+	Version     int                   `json:"version"`
+	Result      string                `json:"result"`
+	Dominant    []string              `json:"dominant"`
+	Date        string                `json:"date"`
+	CompletedAt time.Time             `json:"completedAt"`
+	Changes     []ScoreChangeResponse `json:"changes"`
+}
+
+// GetAttemptHistoryResponse - ответ на сравнение попыток: попытки от ранних к поздним
+// и изменение баллов от первой попытки к последней
+type GetAttemptHistoryResponse struct {
+	TestID   string                        `json:"testId"`
+	TestName string                        `json:"testName"`
+	UserID   string                        `json:"userId"`
+	Attempts []AttemptHistoryEntryResponse `json:"attempts"`
+	Overall  []ScoreChangeResponse         `json:"overall"`
+}
+
+// GetItemAnalysisRequest - запрос анализа вопросов теста. Version необязателен:
+// по умолчанию - текущая версия; Refresh пересчитывает сохраненный анализ
+type GetItemAnalysisRequest struct {
+	TestID  string `json:"testId"`
+	Version int    `json:"version"`
+	Refresh bool   `json:"refresh"`
+}
+
+// ResponseFrequencyResponse - частота выбора варианта или значения шкалы
+type ResponseFrequencyResponse struct {
+	Key   int     `json:"key"`
+	Label string  `json:"label,omitempty"`
+	Count int     `json:"count"`
+	Share float64 `json:"share"`
+}
+
+// QuestionStatsResponse - распределение ответов на вопрос. Options - для вопросов
+// с вариантами, values - для шкалы Лайкерта
+type QuestionStatsResponse struct {
+	QuestionID   int                         `json:"questionId"`
+	QuestionBody string                      `json:"questionBody"`
+	SelectType   string                      `json:"selectType"`
+	Responses    int                         `json:"responses"`
+	Options      []ResponseFrequencyResponse `json:"options,omitempty"`
+	Values       []ResponseFrequencyResponse `json:"values,omitempty"`
+}
+
+// ItemStatsResponse - показатели вопроса по шкале. Показатели, которые нельзя
+// вычислить, отсутствуют
+type ItemStatsResponse struct {
+	QuestionID     int      `json:"questionId"`
+	Mean           float64  `json:"mean"`
+	Difficulty     *float64 `json:"difficulty,omitempty"`
+	Discrimination *float64 `json:"discrimination,omitempty"`
+	ItemTotal      *float64 `json:"itemTotal,omitempty"`
+	AlphaIfDeleted *float64 `json:"alphaIfDeleted,omitempty"`
+}
+
+// ScaleReliabilityResponse - надежность шкалы и показатели ее вопросов
+type ScaleReliabilityResponse struct {
+	ScaleID     string              `json:"scaleId"`
+	Name        string              `json:"name"`
+	Respondents int                 `json:"respondents"`
+	Mean        float64             `json:"mean"`
+	StdDev      float64             `json:"stdDev"`
+	Alpha       *float64            `json:"alpha,omitempty"`
+	Items       []ItemStatsResponse `json:"items"`
+}
+
+// GetItemAnalysisResponse - ответ с анализом вопросов версии теста
+type GetItemAnalysisResponse struct {
+	TestID      string                     `json:"testId"`
+	TestName    string                     `json:"testName"`
+	Version     int                        `json:"version"`
+	Respondents int                        `json:"respondents"`
+	Questions   []QuestionStatsResponse    `json:"questions"`
+	Scales      []ScaleReliabilityResponse `json:"scales"`
+	ComputedAt  time.Time                  `json:"computedAt"`
+	Cached      bool                       `json:"cached"`
+}
+
+// BuildNormsRequest - запрос на построение таблицы норм. TestVersion - версия теста
+// (0 - текущая), From и To ограничивают дату прохождения, UserIDs - участники выборки
+// Cohort; пустые поля не ограничивают отбор
+type BuildNormsRequest struct {
+	TestID      string     `json:"testId"`
+	TestVersion int        `json:"testVersion"`
+	Name        string     `json:"name"`
+	From        *time.Time `json:"from"`
+	To          *time.Time `json:"to"`
+	Cohort      string     `json:"cohort"`
+	UserIDs     []string   `json:"userIds"`
+}
+
+// FreezeNormsRequest - запрос на закрепление таблицы норм
+type FreezeNormsRequest struct {
+	NormID string `json:"normId"`
+}
+
+// GetNormsRequest - запрос таблиц норм теста
+type GetNormsRequest struct {
+	TestID string `json:"testId"`
+}
+
+// NormFilterResponse - условия отбора попыток для таблицы норм
+type NormFilterResponse struct {
+	From    *time.Time `json:"from,omitempty"`
+	To      *time.Time `json:"to,omitempty"`
+	Cohort  string     `json:"cohort,omitempty"`
+	UserIDs []string   `json:"userIds,omitempty"`
+}
+
+// NormPointResponse - балл шкалы и число попыток выборки с этим баллом
+type NormPointResponse struct {
+	Score float64 `json:"score"`
+	Count int     `json:"count"`
+}
+
+// ScaleNormResponse - распределение баллов шкалы в выборке
+type ScaleNormResponse struct {
+	ScaleID string              `json:"scaleId"`
+	Name    string              `json:"name"`
+	Sample  int                 `json:"sample"`
+	Mean    float64             `json:"mean"`
+	StdDev  float64             `json:"stdDev"`
+	Points  []NormPointResponse `json:"points"`
+}
+
+// NormTableResponse - таблица норм теста. Version равен 0 у черновика
+type NormTableResponse struct {
+	ID          string              `json:"id"`
+	TestID      string              `json:"testId"`
+	TestVersion int                 `json:"testVersion"`
+	Version     int                 `json:"version"`
+	Name        string              `json:"name"`
+	Status      string              `json:"status"`
+	Filter      NormFilterResponse  `json:"filter"`
+	Sample      int                 `json:"sample"`
+	Scales      []ScaleNormResponse `json:"scales"`
+	CreatedBy   string              `json:"createdBy"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	FrozenAt    *time.Time          `json:"frozenAt,omitempty"`
+}
+
+// GetNormsResponse - таблицы норм теста. Active - версия норм, применяемая к результатам
+// текущей версии теста TestVersion
+type GetNormsResponse struct {
+	TestID      string              `json:"testId"`
+	TestName    string              `json:"testName"`
+	TestVersion int                 `json:"testVersion"`
+	Active      int                 `json:"active"`
+	Tables      []NormTableResponse `json:"tables"`
 }
 
 // TerminalCommandRequest - запрос терминальной команды
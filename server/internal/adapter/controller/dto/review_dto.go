@@ -17,27 +17,22 @@ type GetReviewsResponse struct {
 
 // CreateReviewRequest - запрос на создание отзыва
 type CreateReviewRequest struct {
-	UserID     string `json:"userId"`
 	ReviewBody string `json:"reviewBody"`
 }
 
 // UpdateReviewRequest - запрос на обновление отзыва
 type UpdateReviewRequest struct {
 	ReviewID   string `json:"reviewId"`
-	UserID     string `json:"userId"`
 	ReviewBody string `json:"reviewBody"`
 }
 
 // DeleteReviewRequest - запрос на удаление отзыва
 type DeleteReviewRequest struct {
 	ReviewID string `json:"reviewId"`
-	UserID   string `json:"userId"`
-	IsAdmin  bool   `json:"isAdmin"`
 }
 
 // ApproveOrDenyRequest - запрос на модерацию отзыва
 type ApproveOrDenyRequest struct {
 	ReviewID string `json:"reviewId"`
-	AdminID  string `json:"adminId"`
 	Decision string `json:"decision"`
 }
@@ -1,20 +1,48 @@
 package dto
 
-// GetTestsRequest - запрос на получение тестов
-type GetTestsRequest struct {
-	UserID string `json:"userId"`
-}
+import "time"
 
-// TestResponse - информация о тесте в ответе
+// TestResponse - информация о тесте в ответе. PublishAt и UnpublishAt - период показа
+// опубликованного теста, ReviewComment - причина отклонения при проверке.
+// CanAttempt и NextAttemptAt заполняются в списке тестов для прохождения: можно ли
+// пройти тест сейчас по правилу повторного прохождения и с какого момента станет можно.
+// OwnerID, Access и Collaborators заполняются в списках управляемых тестов
 type TestResponse struct {
-	ID            string   `json:"id"`
-	TestName      string   `json:"testName"`
-	AuthorsName   []string `json:"authorsName"`
-	QuestionCount int      `json:"questionCount"`
-	Description   string   `json:"description"`
-	Date          string   `json:"date"`
-	Status        string   `json:"status"`
-	IsCompleted   bool     `json:"isCompleted"`
+	ID            string                 `json:"id"`
+	TestName      string                 `json:"testName"`
+	AuthorsName   []string               `json:"authorsName"`
+	QuestionCount int                    `json:"questionCount"`
+	Description   string                 `json:"description"`
+	Category      string                 `json:"category,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	Date          string                 `json:"date"`
+	Status        string                 `json:"status"`
+	IsCompleted   bool                   `json:"isCompleted"`
+	IsTyping      bool                   `json:"isTyping"`
+	Version       int                    `json:"version"`
+	PublishAt     *time.Time             `json:"publishAt,omitempty"`
+	UnpublishAt   *time.Time             `json:"unpublishAt,omitempty"`
+	ReviewComment string                 `json:"reviewComment,omitempty"`
+	Retake        *RetakePolicyRequest   `json:"retake,omitempty"`
+	CanAttempt    *bool                  `json:"canAttempt,omitempty"`
+	NextAttemptAt *time.Time             `json:"nextAttemptAt,omitempty"`
+	OwnerID       string                 `json:"ownerId,omitempty"`
+	Access        string                 `json:"access,omitempty"`
+	Collaborators []CollaboratorResponse `json:"collaborators,omitempty"`
+	ClonedFrom    *TestOriginResponse    `json:"clonedFrom,omitempty"`
+}
+
+// TestOriginResponse - исходный тест копии и версия его вопросов
+type TestOriginResponse struct {
+	TestID  string `json:"testId"`
+	Version int    `json:"version"`
+}
+
+// CollaboratorResponse - соавтор теста; Role: editor или viewer
+type CollaboratorResponse struct {
+	UserID  string    `json:"userId"`
+	Role    string    `json:"role"`
+	AddedAt time.Time `json:"addedAt"`
 }
 
 // GetTestsResponse - ответ на получение тестов
@@ -22,9 +50,31 @@ type GetTestsResponse struct {
 	Tests []TestResponse `json:"tests"`
 }
 
+// SearchTestsRequest - запрос на поиск тестов в каталоге. Все поля необязательны.
+// Completion: "all" (по умолчанию), "completed" или "not-completed";
+// Sort: "relevance" (по умолчанию при заданном query), "newest" или "name".
+// Cursor - nextCursor предыдущей страницы
+type SearchTestsRequest struct {
+	Query      string   `json:"query"`
+	Category   string   `json:"category"`
+	Tags       []string `json:"tags"`
+	Completion string   `json:"completion"`
+	Sort       string   `json:"sort"`
+	Limit      int      `json:"limit"`
+	Cursor     string   `json:"cursor"`
+}
+
+// SearchTestsResponse - страница результатов поиска; nextCursor отсутствует на последней странице
+type SearchTestsResponse struct {
+	Tests      []TestResponse `json:"tests"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
 // GetQuestionsRequest - запрос на получение вопросов
 type GetQuestionsRequest struct {
-	TestID string `json:"testId"`
+	TestID    string `json:"testId"`
+	Version   int    `json:"version"`   // необязательно; по умолчанию текущая версия
+	AttemptID string `json:"attemptId"` // необязательно; вопросы попытки в ее порядке показа
 }
 
 // AnswerOptionResponse - вариант ответа
@@ -33,64 +83,312 @@ type AnswerOptionResponse struct {
 	Body string `json:"body"`
 }
 
-// QuestionResponse - вопрос теста
+// QuestionResponse - вопрос теста. SelectType - тип вопроса: one, multiple, likert, text,
+// ranking или matrix; остальные поля заполняются в зависимости от типа
 type QuestionResponse struct {
 	ID            int                    `json:"id"`
 	QuestionBody  string                 `json:"questionBody"`
 	AnswerOptions []AnswerOptionResponse `json:"answerOptions"`
 	SelectType    string                 `json:"selectType"`
+	Likert        *LikertScaleResponse   `json:"likert,omitempty"`
+	Rows          []MatrixRowResponse    `json:"rows,omitempty"`
+	MaxLength     int                    `json:"maxLength,omitempty"`
+	Branches      []BranchRuleRequest    `json:"branches,omitempty"`
+}
+
+// BranchRuleRequest - правило перехода после ответа на вопрос one или multiple: если выбран
+// один из вариантов optionIds (пустой список - при любом ответе), следующим показывается
+// вопрос goTo, а при finish тест завершается. Правила проверяются по порядку
+type BranchRuleRequest struct {
+	OptionIDs []int `json:"optionIds,omitempty"`
+	GoTo      int   `json:"goTo,omitempty"`
+	Finish    bool  `json:"finish,omitempty"`
+}
+
+// LikertScaleResponse - настройки шкалы Лайкерта
+type LikertScaleResponse struct {
+	Min      int    `json:"min"`
+	Max      int    `json:"max"`
+	MinLabel string `json:"minLabel"`
+	MaxLabel string `json:"maxLabel"`
+}
+
+// MatrixRowResponse - строка матричного вопроса (столбцы передаются в answerOptions)
+type MatrixRowResponse struct {
+	ID   int    `json:"id"`
+	Body string `json:"body"`
+}
+
+// ScaleResponse - шкала теста (веса вариантов участникам не показываются)
+type ScaleResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 // GetQuestionsResponse - ответ на получение вопросов
 type GetQuestionsResponse struct {
-	Questions    []QuestionResponse `json:"questions"`
-	ResultsLogic string             `json:"resultsLogic"`
+	Version    int                `json:"version"`
+	Questions  []QuestionResponse `json:"questions"`
+	Scales     []ScaleResponse    `json:"scales"`
+	TimeLimits *TimeLimitsRequest `json:"timeLimits,omitempty"`
+	Shuffle    *ShuffleRequest    `json:"shuffle,omitempty"`
+}
+
+// ShuffleRequest - перемешивание для каждой попытки: questions - порядок вопросов,
+// options - порядок вариантов ответа в вопросах с выбором и ранжированием
+type ShuffleRequest struct {
+	Questions bool `json:"questions,omitempty"`
+	Options   bool `json:"options,omitempty"`
 }
 
-// AttemptTestRequest - запрос на прохождение теста
+// RetakePolicyRequest - правило повторного прохождения: mode - unlimited (по умолчанию),
+// once или cooldown; cooldownDays - срок в днях для cooldown
+type RetakePolicyRequest struct {
+	Mode         string `json:"mode,omitempty"`
+	CooldownDays int    `json:"cooldownDays,omitempty"`
+}
+
+// TimeLimitsRequest - ограничения времени в секундах; 0 или отсутствие поля - без ограничения
+type TimeLimitsRequest struct {
+	TotalSeconds    int `json:"totalSeconds,omitempty"`
+	QuestionSeconds int `json:"questionSeconds,omitempty"`
+}
+
+// AttemptTestRequest - запрос на прохождение теста. Результат вычисляется сервером.
+// Responses принимает ответы на вопросы любого типа; Answers - прежний формат
+// [ID вопроса, ID варианта...] для вопросов с вариантами, используется без Responses.
+// Version - версия теста из ответа на получение вопросов
 type AttemptTestRequest struct {
-	UserID  string  `json:"userId"`
-	TestID  string  `json:"testId"`
-	Result  string  `json:"result"`
-	Answers [][]int `json:"answers"`
+	TestID    string                  `json:"testId"`
+	Version   int                     `json:"version"`
+	Answers   [][]int                 `json:"answers"`
+	Responses []QuestionAnswerRequest `json:"responses"`
+}
+
+// QuestionAnswerRequest - ответ на вопрос. OptionIDs - для one, multiple и ranking
+// (в порядке ранжирования), Value - для likert, Text - для text, Cells - для matrix
+type QuestionAnswerRequest struct {
+	QuestionID int                 `json:"questionId"`
+	OptionIDs  []int               `json:"optionIds"`
+	Value      *int                `json:"value"`
+	Text       string              `json:"text"`
+	Cells      []MatrixCellRequest `json:"cells"`
 }
 
-// AttemptTestResponse - ответ на прохождение теста
+// MatrixCellRequest - выбранный столбец в строке матричного вопроса
+type MatrixCellRequest struct {
+	RowID    int `json:"rowId"`
+	OptionID int `json:"optionId"`
+}
+
+// ScaleScoreResponse - баллы по шкале и диапазон, в который они попали. Percentile
+// и Stanine - положение балла относительно действующих норм теста, если они закреплены
+type ScaleScoreResponse struct {
+	ScaleID    string   `json:"scaleId"`
+	Name       string   `json:"name"`
+	Score      float64  `json:"score"`
+	Band       string   `json:"band,omitempty"`
+	BandText   string   `json:"bandText,omitempty"`
+	Percentile *float64 `json:"percentile,omitempty"`
+	Stanine    int      `json:"stanine,omitempty"`
+}
+
+// AttemptTestResponse - ответ на прохождение теста. Result содержит текст интерпретации.
+// Для попытки без входа в аккаунт ID пуст, а ClaimToken - токен для ее привязки к аккаунту
+// до ClaimExpiresAt. NormVersion - версия норм, по которой посчитаны процентили
 type AttemptTestResponse struct {
-	Success string `json:"success"`
-	ID      string `json:"id"`
+	Success        string               `json:"success"`
+	ID             string               `json:"id"`
+	TestVersion    int                  `json:"testVersion"`
+	Result         string               `json:"result"`
+	Scores         []ScaleScoreResponse `json:"scores"`
+	Dominant       []string             `json:"dominant"`
+	MatchedRules   []string             `json:"matchedRules"`
+	PsychoType     string               `json:"psychoType,omitempty"`
+	ClaimToken     string               `json:"claimToken,omitempty"`
+	ClaimExpiresAt *time.Time           `json:"claimExpiresAt,omitempty"`
+	NormVersion    int                  `json:"normVersion,omitempty"`
+}
+
+// ClaimAttemptRequest - запрос на привязку попытки, пройденной без входа в аккаунт
+type ClaimAttemptRequest struct {
+	ClaimToken string `json:"claimToken"`
+}
+
+// ClaimAttemptResponse - ответ на привязку попытки: ID - результат в истории пользователя
+type ClaimAttemptResponse struct {
+	Success     string `json:"success"`
+	ID          string `json:"id"`
+	TestID      string `json:"testId"`
+	TestVersion int    `json:"testVersion"`
+	PsychoType  string `json:"psychoType,omitempty"`
+}
+
+// StartAttemptRequest - запрос на начало или продолжение попытки.
+// Version - версия теста из ответа на получение вопросов
+type StartAttemptRequest struct {
+	TestID  string `json:"testId"`
+	Version int    `json:"version"`
+}
+
+// SaveAttemptAnswerRequest - запрос на сохранение ответа в незавершенной попытке
+type SaveAttemptAnswerRequest struct {
+	AttemptID string                `json:"attemptId"`
+	Answer    QuestionAnswerRequest `json:"answer"`
+}
+
+// OpenQuestionRequest - запрос на отметку о показе вопроса в незавершенной попытке
+type OpenQuestionRequest struct {
+	AttemptID  string `json:"attemptId"`
+	QuestionID int    `json:"questionId"`
+}
+
+// SubmitAttemptRequest - запрос на отправку попытки
+type SubmitAttemptRequest struct {
+	AttemptID string `json:"attemptId"`
 }
 
-// AddTestRequest - запрос на создание теста
+// AttemptSessionResponse - незавершенная попытка с сохраненными ответами.
+// Resumed отмечает попытку, начатую ранее. Deadline - срок ответа на все вопросы,
+// QuestionTimeLimitSec - время на вопрос, QuestionDeadline - срок ответа на открытый вопрос
+type AttemptSessionResponse struct {
+	ID                   string                   `json:"id"`
+	TestID               string                   `json:"testId"`
+	TestVersion          int                      `json:"testVersion"`
+	Answers              []QuestionAnswerResponse `json:"answers"`
+	StartedAt            time.Time                `json:"startedAt"`
+	UpdatedAt            time.Time                `json:"updatedAt"`
+	ExpiresAt            time.Time                `json:"expiresAt"`
+	Resumed              bool                     `json:"resumed,omitempty"`
+	Deadline             *time.Time               `json:"deadline,omitempty"`
+	QuestionTimeLimitSec int                      `json:"questionTimeLimitSec,omitempty"`
+	QuestionDeadline     *time.Time               `json:"questionDeadline,omitempty"`
+}
+
+// GetActiveAttemptsResponse - незавершенные попытки пользователя
+type GetActiveAttemptsResponse struct {
+	Attempts []AttemptSessionResponse `json:"attempts"`
+}
+
+// AddTestRequest - запрос на создание теста. IsTyping отмечает тест определения психотипа
 type AddTestRequest struct {
-	TestName    string             `json:"testName"`
-	AuthorsName []string           `json:"authorsName"`
-	Description string             `json:"description"`
-	UserID      string             `json:"userId"`
-	Questions   []QuestionInput    `json:"questions"`
-	ResultLogic string             `json:"resultLogic"`
+	TestName    string               `json:"testName"`
+	AuthorsName []string             `json:"authorsName"`
+	Description string               `json:"description"`
+	Category    string               `json:"category"`
+	Tags        []string             `json:"tags"`
+	Questions   []QuestionInput      `json:"questions"`
+	ResultLogic ResultsLogicRequest  `json:"resultLogic"`
+	IsTyping    bool                 `json:"isTyping"`
+	TimeLimits  *TimeLimitsRequest   `json:"timeLimits"`
+	Retake      *RetakePolicyRequest `json:"retake"`
+	Shuffle     *ShuffleRequest      `json:"shuffle"`
+}
+
+// ResultsLogicRequest - правила подсчета и интерпретации результатов теста.
+// Этот и вложенные в него типы также описывают тест в пакете экспорта, поэтому
+// необязательные поля помечены omitempty.
+// RuleMode: "first" (по умолчанию) или "all"; TieBreak: "order" (по умолчанию), "all" или "none"
+type ResultsLogicRequest struct {
+	Scales   []ScaleRequest      `json:"scales"`
+	Rules    []ResultRuleRequest `json:"rules,omitempty"`
+	RuleMode string              `json:"ruleMode,omitempty"`
+	TieBreak string              `json:"tieBreak,omitempty"`
+}
+
+// ScaleRequest - шкала теста
+type ScaleRequest struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Bands       []ScoreBandRequest `json:"bands,omitempty"`
+}
+
+// ScoreBandRequest - диапазон баллов шкалы; границы включительные
+type ScoreBandRequest struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Label string  `json:"label"`
+	Text  string  `json:"text"`
+}
+
+// ResultRuleRequest - правило интерпретации сочетания шкал
+type ResultRuleRequest struct {
+	ID         string                 `json:"id"`
+	Conditions []RuleConditionRequest `json:"conditions"`
+	Text       string                 `json:"text"`
+	Priority   int                    `json:"priority,omitempty"`
 }
 
-// QuestionInput - входные данные вопроса
+// RuleConditionRequest - условие правила интерпретации
+type RuleConditionRequest struct {
+	Scale    string   `json:"scale"`
+	Band     string   `json:"band,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Above    string   `json:"above,omitempty"`
+	Dominant bool     `json:"dominant,omitempty"`
+}
+
+// QuestionInput - входные данные вопроса. SelectType - тип вопроса (по умолчанию one).
+// Weights задает баллы шкал за единицу значения для вопросов likert; Branches - переходы
+// между вопросами
 type QuestionInput struct {
 	ID            int                 `json:"id"`
 	QuestionBody  string              `json:"questionBody"`
-	AnswerOptions []AnswerOptionInput `json:"answerOptions"`
+	AnswerOptions []AnswerOptionInput `json:"answerOptions,omitempty"`
 	SelectType    string              `json:"selectType"`
+	Likert        *LikertScaleRequest `json:"likert,omitempty"`
+	Rows          []MatrixRowRequest  `json:"rows,omitempty"`
+	MaxLength     int                 `json:"maxLength,omitempty"`
+	Weights       map[string]float64  `json:"weights,omitempty"`
+	Branches      []BranchRuleRequest `json:"branches,omitempty"`
 }
 
-// AnswerOptionInput - входные данные варианта ответа
-type AnswerOptionInput struct {
+// LikertScaleRequest - настройки шкалы Лайкерта
+type LikertScaleRequest struct {
+	Min      int    `json:"min"`
+	Max      int    `json:"max"`
+	MinLabel string `json:"minLabel,omitempty"`
+	MaxLabel string `json:"maxLabel,omitempty"`
+}
+
+// MatrixRowRequest - строка матричного вопроса
+type MatrixRowRequest struct {
 	ID   int    `json:"id"`
 	Body string `json:"body"`
 }
 
+// AnswerOptionInput - входные данные варианта ответа.
+// Weights - баллы, добавляемые к шкалам при выборе варианта
+type AnswerOptionInput struct {
+	ID      int                `json:"id"`
+	Body    string             `json:"body"`
+	Weights map[string]float64 `json:"weights,omitempty"`
+}
+
 // AddTestResponse - ответ на создание теста
 type AddTestResponse struct {
 	Success string `json:"success"`
 	TestID  string `json:"testId"`
 }
 
+// CloneTestRequest - запрос на копирование теста. Version - версия исходного теста
+// (по умолчанию текущая), TestName - название копии
+type CloneTestRequest struct {
+	TestID   string `json:"testId"`
+	Version  int    `json:"version"`
+	TestName string `json:"testName"`
+}
+
+// CloneTestResponse - ответ на копирование теста
+type CloneTestResponse struct {
+	Success    string             `json:"success"`
+	TestID     string             `json:"testId"`
+	ClonedFrom TestOriginResponse `json:"clonedFrom"`
+}
+
 // ChangeTestLoadRequest - запрос на загрузку теста для редактирования
 type ChangeTestLoadRequest struct {
 	TestID string `json:"testId"`
@@ -98,12 +396,18 @@ type ChangeTestLoadRequest struct {
 
 // ChangeTestUpdateRequest - запрос на обновление теста
 type ChangeTestUpdateRequest struct {
-	TestID      string          `json:"testId"`
-	TestName    string          `json:"testName"`
-	AuthorsName []string        `json:"authorsName"`
-	Description string          `json:"description"`
-	Questions   []QuestionInput `json:"questions"`
-	ResultLogic string          `json:"resultLogic"`
+	TestID      string               `json:"testId"`
+	TestName    string               `json:"testName"`
+	AuthorsName []string             `json:"authorsName"`
+	Description string               `json:"description"`
+	Category    string               `json:"category"`
+	Tags        []string             `json:"tags"`
+	Questions   []QuestionInput      `json:"questions"`
+	ResultLogic ResultsLogicRequest  `json:"resultLogic"`
+	IsTyping    bool                 `json:"isTyping"`
+	TimeLimits  *TimeLimitsRequest   `json:"timeLimits"`
+	Retake      *RetakePolicyRequest `json:"retake"`
+	Shuffle     *ShuffleRequest      `json:"shuffle"`
 }
 
 // DeleteTestRequest - запрос на удаление теста
@@ -115,3 +419,59 @@ type DeleteTestRequest struct {
 type DeleteTestResponse struct {
 	Success string `json:"success"`
 }
+
+// AddCollaboratorRequest - запрос на приглашение соавтора или смену его роли.
+// Role: editor (по умолчанию) или viewer
+type AddCollaboratorRequest struct {
+	TestID string `json:"testId"`
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+// RemoveCollaboratorRequest - запрос на исключение соавтора; без userId соавтор
+// отказывается от соавторства сам
+type RemoveCollaboratorRequest struct {
+	TestID string `json:"testId"`
+	UserID string `json:"userId"`
+}
+
+// TransferOwnershipRequest - запрос на передачу владения тестом
+type TransferOwnershipRequest struct {
+	TestID string `json:"testId"`
+	UserID string `json:"userId"`
+}
+
+// TestAccessResponse - владелец и соавторы теста после изменения доступа
+type TestAccessResponse struct {
+	TestID        string                 `json:"testId"`
+	OwnerID       string                 `json:"ownerId"`
+	Collaborators []CollaboratorResponse `json:"collaborators"`
+}
+
+// ChangeTestStatusRequest - запрос на перевод теста в другой статус.
+// Action: submit, withdraw, approve, reject, schedule, archive или restore
+type ChangeTestStatusRequest struct {
+	TestID      string     `json:"testId"`
+	Action      string     `json:"action"`
+	PublishAt   *time.Time `json:"publishAt"`
+	UnpublishAt *time.Time `json:"unpublishAt"`
+	Comment     string     `json:"comment"`
+}
+
+// ChangeTestStatusResponse - ответ на перевод теста в другой статус
+type ChangeTestStatusResponse struct {
+	Success string       `json:"success"`
+	Test    TestResponse `json:"test"`
+}
+
+// RecomputeResultsRequest - запрос на пересчет результатов теста
+type RecomputeResultsRequest struct {
+	TestID string `json:"testId"`
+}
+
+// RecomputeResultsResponse - ответ на пересчет результатов теста
+type RecomputeResultsResponse struct {
+	Success    string `json:"success"`
+	Recomputed int    `json:"recomputed"`
+	Skipped    int    `json:"skipped"`
+}
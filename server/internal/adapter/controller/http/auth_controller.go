@@ -4,26 +4,50 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"server/internal/adapter/controller/dto"
+	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
 	userUseCase "server/internal/usecase/user"
 )
 
+// oauthNonceCookie - cookie, связывающая state входа через провайдера с браузером,
+// который начал вход
+const oauthNonceCookie = "oauth_nonce"
+
 type AuthController struct {
 	loginUseCase    *userUseCase.LoginUseCase
 	registerUseCase *userUseCase.RegisterUseCase
+	logoutUseCase   *userUseCase.LogoutUseCase
+	lostPasswordUC  *userUseCase.RequestPasswordResetUseCase
+	resetPasswordUC *userUseCase.ConfirmPasswordResetUseCase
+	startOAuthUC    *userUseCase.StartOAuthUseCase
+	oauthLoginUC    *userUseCase.OAuthLoginUseCase
+	unlinkOAuthUC   *userUseCase.UnlinkOAuthUseCase
 }
 
 func NewAuthController(
 	loginUC *userUseCase.LoginUseCase,
 	registerUC *userUseCase.RegisterUseCase,
+	logoutUC *userUseCase.LogoutUseCase,
+	lostPasswordUC *userUseCase.RequestPasswordResetUseCase,
+	resetPasswordUC *userUseCase.ConfirmPasswordResetUseCase,
+	startOAuthUC *userUseCase.StartOAuthUseCase,
+	oauthLoginUC *userUseCase.OAuthLoginUseCase,
+	unlinkOAuthUC *userUseCase.UnlinkOAuthUseCase,
 ) *AuthController {
 	return &AuthController{
 		loginUseCase:    loginUC,
 		registerUseCase: registerUC,
+		logoutUseCase:   logoutUC,
+		lostPasswordUC:  lostPasswordUC,
+		resetPasswordUC: resetPasswordUC,
+		startOAuthUC:    startOAuthUC,
+		oauthLoginUC:    oauthLoginUC,
+		unlinkOAuthUC:   unlinkOAuthUC,
 	}
 }
 
@@ -58,18 +82,33 @@ func (c *AuthController) LoginWithPassword(ctx *gin.Context) {
 		return
 	}
 
-	// Формирование ответа
-	ctx.JSON(http.StatusOK, dto.LoginResponse{
+	ctx.JSON(http.StatusOK, c.loginResponse(output))
+}
+
+// loginResponse формирует ответ на успешный вход
+func (c *AuthController) loginResponse(output userUseCase.LoginOutput) dto.LoginResponse {
+	role := output.User.EffectiveRole()
+	permissions := make([]string, 0, len(role.Permissions()))
+	for _, permission := range role.Permissions() {
+		permissions = append(permissions, string(permission))
+	}
+
+	return dto.LoginResponse{
 		Success:       "Авторизация успешна",
 		ID:            output.User.ID.String(),
 		FirstName:     output.User.FirstName,
 		Email:         output.User.Email,
 		Status:        string(output.User.Status),
+		Role:          string(role),
+		Permissions:   permissions,
 		PsychoType:    output.User.PsychoType,
 		Date:          output.User.Date,
 		IsGoogleAdded: output.User.IsGoogleAdded,
 		IsYandexAdded: output.User.IsYandexAdded,
-	})
+		AccessToken:   output.AccessToken.Token,
+		TokenType:     "Bearer",
+		ExpiresAt:     output.AccessToken.ExpiresAt.Format(time.RFC3339),
+	}
 }
 
 func (c *AuthController) Register(ctx *gin.Context) {
@@ -97,23 +136,141 @@ func (c *AuthController) Register(ctx *gin.Context) {
 	})
 }
 
-// Заглушки для других методов (Google, Yandex, LostPassword)
+func (c *AuthController) Logout(ctx *gin.Context) {
+	if err := c.logoutUseCase.Execute(ctx.Request.Context()); err != nil {
+		c.handleLoginError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": "Выход выполнен"})
+}
+
+func (c *AuthController) LostPassword(ctx *gin.Context) {
+	var req dto.LostPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	err := c.lostPasswordUC.Execute(ctx.Request.Context(), userUseCase.RequestPasswordResetInput{
+		Email: req.Email,
+	})
+	if err != nil {
+		c.handlePasswordResetError(ctx, err)
+		return
+	}
+
+	// Ответ одинаков для зарегистрированных и неизвестных адресов
+	ctx.JSON(http.StatusOK, gin.H{"success": "Если адрес зарегистрирован, на него отправлено письмо со ссылкой для сброса пароля"})
+}
+
+func (c *AuthController) ResetPassword(ctx *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	err := c.resetPasswordUC.Execute(ctx.Request.Context(), userUseCase.ConfirmPasswordResetInput{
+		Token:          req.Token,
+		Password:       req.Password,
+		PasswordRepeat: req.PasswordRepeat,
+	})
+	if err != nil {
+		c.handlePasswordResetError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": "Пароль изменен, войдите с новым паролем"})
+}
+
+// Вход через внешних провайдеров (OAuth2 authorization code flow)
+
+func (c *AuthController) GoogleAuthURL(ctx *gin.Context) {
+	c.authURL(ctx, entity.OAuthProviderGoogle)
+}
+
+func (c *AuthController) YandexAuthURL(ctx *gin.Context) {
+	c.authURL(ctx, entity.OAuthProviderYandex)
+}
+
 func (c *AuthController) LoginWithGoogle(ctx *gin.Context) {
-	ctx.JSON(http.StatusNotImplemented, dto.ErrorResponse{Error: "В разработке"})
+	c.loginWithProvider(ctx, entity.OAuthProviderGoogle)
 }
 
 func (c *AuthController) LoginWithYandex(ctx *gin.Context) {
-	ctx.JSON(http.StatusNotImplemented, dto.ErrorResponse{Error: "В разработке"})
+	c.loginWithProvider(ctx, entity.OAuthProviderYandex)
 }
 
-func (c *AuthController) LostPassword(ctx *gin.Context) {
-	ctx.JSON(http.StatusNotImplemented, dto.ErrorResponse{Error: "В разработке"})
+func (c *AuthController) UnlinkProvider(ctx *gin.Context) {
+	var req dto.UnlinkProviderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	err := c.unlinkOAuthUC.Execute(ctx.Request.Context(), userUseCase.UnlinkOAuthInput{
+		Provider: req.Provider,
+	})
+	if err != nil {
+		c.handleOAuthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": "Провайдер отвязан"})
+}
+
+func (c *AuthController) authURL(ctx *gin.Context, provider entity.OAuthProvider) {
+	output, err := c.startOAuthUC.Execute(ctx.Request.Context(), userUseCase.StartOAuthInput{
+		Provider: string(provider),
+	})
+	if err != nil {
+		c.handleOAuthError(ctx, err)
+		return
+	}
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oauthNonceCookie, output.Nonce, int(time.Until(output.ExpiresAt).Seconds()),
+		"/", "", ctx.Request.TLS != nil, true)
+
+	ctx.JSON(http.StatusOK, dto.OAuthURLResponse{
+		AuthorizationURL: output.AuthorizationURL,
+		State:            output.State,
+	})
+}
+
+func (c *AuthController) loginWithProvider(ctx *gin.Context, provider entity.OAuthProvider) {
+	var req dto.OAuthLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	// Nonce одноразовый: cookie удаляется при любой попытке завершить вход
+	nonce, _ := ctx.Cookie(oauthNonceCookie)
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oauthNonceCookie, "", -1, "/", "", ctx.Request.TLS != nil, true)
+
+	output, err := c.oauthLoginUC.Execute(ctx.Request.Context(), userUseCase.OAuthLoginInput{
+		Provider: string(provider),
+		Code:     req.Code,
+		State:    req.State,
+		Nonce:    nonce,
+	})
+	if err != nil {
+		c.handleOAuthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.loginResponse(output))
 }
 
 // Обработчики ошибок
 
 func (c *AuthController) handleLoginError(ctx *gin.Context, err error) {
 	switch {
+	case errors.Is(err, domainErrors.ErrUnauthorized):
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Требуется авторизация"})
 	case errors.Is(err, domainErrors.ErrInvalidInput), errors.Is(err, domainErrors.ErrInvalidEmail):
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Введите корректные данные"})
 	case errors.Is(err, domainErrors.ErrUserNotFound):
@@ -131,6 +288,60 @@ func (c *AuthController) handleLoginError(ctx *gin.Context, err error) {
 	}
 }
 
+func (c *AuthController) handleOAuthError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domainErrors.ErrUnauthorized):
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Требуется авторизация"})
+	case errors.Is(err, domainErrors.ErrInvalidInput):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+	case errors.Is(err, domainErrors.ErrInvalidToken):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Сеанс входа устарел, начните вход заново"})
+	case errors.Is(err, domainErrors.ErrProviderNotConfigured):
+		ctx.JSON(http.StatusNotImplemented, dto.ErrorResponse{Error: "Вход через этот сервис не настроен"})
+	case errors.Is(err, domainErrors.ErrProviderExchange):
+		ctx.JSON(http.StatusBadGateway, dto.ErrorResponse{Error: "Не удалось получить данные от сервиса авторизации"})
+	case errors.Is(err, domainErrors.ErrEmailNotVerified):
+		ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "Почтовый адрес не подтвержден у провайдера"})
+	case errors.Is(err, domainErrors.ErrAccountLinked):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{Error: "Этот аккаунт уже привязан к другому пользователю"})
+	case errors.Is(err, domainErrors.ErrLastLoginMethod):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{Error: "Нельзя отвязать единственный способ входа: сначала задайте пароль"})
+	case errors.Is(err, domainErrors.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Провайдер не привязан"})
+	case errors.Is(err, domainErrors.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Пользователь не найден"})
+	case errors.Is(err, domainErrors.ErrUserDeleted):
+		ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "Пользователь удален. Обратитесь к администратору."})
+	case errors.Is(err, domainErrors.ErrUserBlocked):
+		ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "Пользователь заблокирован"})
+	case errors.Is(err, domainErrors.ErrDatabase):
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Ошибка обращения к базе данных"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Не удалось выполнить авторизацию"})
+	}
+}
+
+func (c *AuthController) handlePasswordResetError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domainErrors.ErrInvalidInput):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Не оставляйте поля пустыми"})
+	case errors.Is(err, domainErrors.ErrInvalidEmail):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Введите корректный почтовый адрес"})
+	case errors.Is(err, domainErrors.ErrPasswordsMatch):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Пароли не совпадают"})
+	case errors.Is(err, domainErrors.ErrInvalidToken), errors.Is(err, domainErrors.ErrInvalidID), errors.Is(err, domainErrors.ErrUserNotFound):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Ссылка для сброса пароля недействительна или устарела"})
+	case errors.Is(err, domainErrors.ErrUserDeleted):
+		ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "Пользователь удален. Обратитесь к администратору."})
+	case errors.Is(err, domainErrors.ErrUserBlocked):
+		ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "Пользователь заблокирован"})
+	case errors.Is(err, domainErrors.ErrDatabase):
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Ошибка обращения к базе данных"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Не удалось сбросить пароль"})
+	}
+}
+
 func (c *AuthController) handleRegisterError(ctx *gin.Context, err error) {
 	switch {
 	case errors.Is(err, domainErrors.ErrInvalidInput):
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"server/internal/adapter/controller/dto"
+	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
 	dashboardUseCase "server/internal/usecase/dashboard"
 )
@@ -15,10 +16,17 @@ type DashboardController struct {
 	getUsersUC          *dashboardUseCase.GetUsersUseCase
 	blockUserUC         *dashboardUseCase.BlockUserUseCase
 	deleteUserUC        *dashboardUseCase.DeleteUserUseCase
+	changeUserRoleUC    *dashboardUseCase.ChangeUserRoleUseCase
 	deleteAccountUC     *dashboardUseCase.DeleteAccountUseCase
 	changeUserDataUC    *dashboardUseCase.ChangeUserDataUseCase
 	getCompletedTestsUC *dashboardUseCase.GetCompletedTestsUseCase
 	getUserAnswersUC    *dashboardUseCase.GetUserAnswersUseCase
+	psychoTypeHistoryUC *dashboardUseCase.GetPsychoTypeHistoryUseCase
+	attemptHistoryUC    *dashboardUseCase.GetAttemptHistoryUseCase
+	itemAnalysisUC      *dashboardUseCase.GetItemAnalysisUseCase
+	buildNormsUC        *dashboardUseCase.BuildNormsUseCase
+	freezeNormsUC       *dashboardUseCase.FreezeNormsUseCase
+	getNormsUC          *dashboardUseCase.GetNormsUseCase
 	terminalCommandsUC  *dashboardUseCase.TerminalCommandsUseCase
 }
 
@@ -26,35 +34,40 @@ func NewDashboardController(
 	getUsersUC *dashboardUseCase.GetUsersUseCase,
 	blockUserUC *dashboardUseCase.BlockUserUseCase,
 	deleteUserUC *dashboardUseCase.DeleteUserUseCase,
+	changeUserRoleUC *dashboardUseCase.ChangeUserRoleUseCase,
 	deleteAccountUC *dashboardUseCase.DeleteAccountUseCase,
 	changeUserDataUC *dashboardUseCase.ChangeUserDataUseCase,
 	getCompletedTestsUC *dashboardUseCase.GetCompletedTestsUseCase,
 	getUserAnswersUC *dashboardUseCase.GetUserAnswersUseCase,
+	psychoTypeHistoryUC *dashboardUseCase.GetPsychoTypeHistoryUseCase,
+	attemptHistoryUC *dashboardUseCase.GetAttemptHistoryUseCase,
+	itemAnalysisUC *dashboardUseCase.GetItemAnalysisUseCase,
+	buildNormsUC *dashboardUseCase.BuildNormsUseCase,
+	freezeNormsUC *dashboardUseCase.FreezeNormsUseCase,
+	getNormsUC *dashboardUseCase.GetNormsUseCase,
 	terminalCommandsUC *dashboardUseCase.TerminalCommandsUseCase,
 ) *DashboardController {
 	return &DashboardController{
 		getUsersUC:          getUsersUC,
 		blockUserUC:         blockUserUC,
 		deleteUserUC:        deleteUserUC,
+		changeUserRoleUC:    changeUserRoleUC,
 		deleteAccountUC:     deleteAccountUC,
 		changeUserDataUC:    changeUserDataUC,
 		getCompletedTestsUC: getCompletedTestsUC,
 		getUserAnswersUC:    getUserAnswersUC,
+		psychoTypeHistoryUC: psychoTypeHistoryUC,
+		attemptHistoryUC:    attemptHistoryUC,
+		itemAnalysisUC:      itemAnalysisUC,
+		buildNormsUC:        buildNormsUC,
+		freezeNormsUC:       freezeNormsUC,
+		getNormsUC:          getNormsUC,
 		terminalCommandsUC:  terminalCommandsUC,
 	}
 }
 
 func (c *DashboardController) GetUsersData(ctx *gin.Context) {
-	var req dto.GetUsersRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
-		return
-	}
-
-	output, err := c.getUsersUC.Execute(ctx.Request.Context(), dashboardUseCase.GetUsersInput{
-		AdminID: req.UserID,
-		Status:  req.Status,
-	})
+	output, err := c.getUsersUC.Execute(ctx.Request.Context())
 	if err != nil {
 		c.handleError(ctx, err)
 		return
@@ -68,6 +81,7 @@ func (c *DashboardController) GetUsersData(ctx *gin.Context) {
 			LastName:      user.LastName,
 			Email:         user.Email,
 			Status:        string(user.Status),
+			Role:          string(user.EffectiveRole()),
 			PsychoType:    user.PsychoType,
 			Date:          user.Date,
 			IsGoogleAdded: user.IsGoogleAdded,
@@ -86,7 +100,6 @@ func (c *DashboardController) BlockUser(ctx *gin.Context) {
 	}
 
 	output, err := c.blockUserUC.Execute(ctx.Request.Context(), dashboardUseCase.BlockUserInput{
-		AdminID:  req.AdminID,
 		TargetID: req.TargetID,
 	})
 	if err != nil {
@@ -100,6 +113,7 @@ func (c *DashboardController) BlockUser(ctx *gin.Context) {
 		LastName:      output.User.LastName,
 		Email:         output.User.Email,
 		Status:        string(output.User.Status),
+		Role:          string(output.User.EffectiveRole()),
 		PsychoType:    output.User.PsychoType,
 		Date:          output.User.Date,
 		IsGoogleAdded: output.User.IsGoogleAdded,
@@ -115,7 +129,6 @@ func (c *DashboardController) DeleteUser(ctx *gin.Context) {
 	}
 
 	output, err := c.deleteUserUC.Execute(ctx.Request.Context(), dashboardUseCase.DeleteUserInput{
-		AdminID:  req.AdminID,
 		TargetID: req.TargetID,
 	})
 	if err != nil {
@@ -129,6 +142,7 @@ func (c *DashboardController) DeleteUser(ctx *gin.Context) {
 		LastName:      output.User.LastName,
 		Email:         output.User.Email,
 		Status:        string(output.User.Status),
+		Role:          string(output.User.EffectiveRole()),
 		PsychoType:    output.User.PsychoType,
 		Date:          output.User.Date,
 		IsGoogleAdded: output.User.IsGoogleAdded,
@@ -136,21 +150,43 @@ func (c *DashboardController) DeleteUser(ctx *gin.Context) {
 	})
 }
 
-func (c *DashboardController) DeleteAccount(ctx *gin.Context) {
-	var req dto.DeleteAccountRequest
+func (c *DashboardController) ChangeUserRole(ctx *gin.Context) {
+	var req dto.ChangeUserRoleRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
 		return
 	}
 
-	err := c.deleteAccountUC.Execute(ctx.Request.Context(), dashboardUseCase.DeleteAccountInput{
-		UserID: req.UserID,
+	output, err := c.changeUserRoleUC.Execute(ctx.Request.Context(), dashboardUseCase.ChangeUserRoleInput{
+		TargetID: req.TargetID,
+		Role:     req.Role,
 	})
 	if err != nil {
 		c.handleError(ctx, err)
 		return
 	}
 
+	ctx.JSON(http.StatusOK, dto.UserResponse{
+		ID:            output.User.ID.String(),
+		FirstName:     output.User.FirstName,
+		LastName:      output.User.LastName,
+		Email:         output.User.Email,
+		Status:        string(output.User.Status),
+		Role:          string(output.User.EffectiveRole()),
+		PsychoType:    output.User.PsychoType,
+		Date:          output.User.Date,
+		IsGoogleAdded: output.User.IsGoogleAdded,
+		IsYandexAdded: output.User.IsYandexAdded,
+	})
+}
+
+func (c *DashboardController) DeleteAccount(ctx *gin.Context) {
+	err := c.deleteAccountUC.Execute(ctx.Request.Context())
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{"success": "Аккаунт удален"})
 }
 
@@ -162,7 +198,6 @@ func (c *DashboardController) ChangeUserData(ctx *gin.Context) {
 	}
 
 	output, err := c.changeUserDataUC.Execute(ctx.Request.Context(), dashboardUseCase.ChangeUserDataInput{
-		UserID:    req.UserID,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 	})
@@ -177,6 +212,7 @@ func (c *DashboardController) ChangeUserData(ctx *gin.Context) {
 		LastName:      output.User.LastName,
 		Email:         output.User.Email,
 		Status:        string(output.User.Status),
+		Role:          string(output.User.EffectiveRole()),
 		PsychoType:    output.User.PsychoType,
 		Date:          output.User.Date,
 		IsGoogleAdded: output.User.IsGoogleAdded,
@@ -185,13 +221,63 @@ func (c *DashboardController) ChangeUserData(ctx *gin.Context) {
 }
 
 func (c *DashboardController) GetCompletedTests(ctx *gin.Context) {
-	var req dto.GetCompletedTestsRequest
+	output, err := c.getCompletedTestsUC.Execute(ctx.Request.Context())
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	tests := make([]dto.CompletedTestResponse, 0, len(output.Tests))
+	for _, test := range output.Tests {
+		tests = append(tests, dto.CompletedTestResponse{
+			ID:          test.ID,
+			TestID:      test.TestID,
+			TestName:    test.TestName,
+			Version:     test.Version,
+			Result:      test.Result,
+			Scores:      normedScoresResponse(test.Scores, test.Norms),
+			Dominant:    scaleIDsResponse(test.Dominant),
+			Date:        test.Date,
+			NormVersion: test.NormVersion,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, dto.GetCompletedTestsResponse{Tests: tests})
+}
+
+func (c *DashboardController) GetPsychoTypeHistory(ctx *gin.Context) {
+	output, err := c.psychoTypeHistoryUC.Execute(ctx.Request.Context())
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	history := make([]dto.PsychoTypeHistoryEntryResponse, 0, len(output.History))
+	for _, entry := range output.History {
+		history = append(history, dto.PsychoTypeHistoryEntryResponse{
+			PsychoType: entry.Assignment.Value,
+			TestID:     entry.Assignment.TestID.String(),
+			TestName:   entry.TestName,
+			AttemptID:  entry.Assignment.AttemptID.String(),
+			AssignedAt: entry.Assignment.AssignedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, dto.GetPsychoTypeHistoryResponse{
+		PsychoType: output.PsychoType,
+		History:    history,
+	})
+}
+
+func (c *DashboardController) GetAttemptHistory(ctx *gin.Context) {
+	var req dto.GetAttemptHistoryRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
 		return
 	}
 
-	output, err := c.getCompletedTestsUC.Execute(ctx.Request.Context(), dashboardUseCase.GetCompletedTestsInput{
+	output, err := c.attemptHistoryUC.Execute(ctx.Request.Context(), dashboardUseCase.GetAttemptHistoryInput{
+		TestID: req.TestID,
 		UserID: req.UserID,
 	})
 	if err != nil {
@@ -199,18 +285,169 @@ func (c *DashboardController) GetCompletedTests(ctx *gin.Context) {
 		return
 	}
 
-	tests := make([]dto.CompletedTestResponse, 0, len(output.Tests))
-	for _, test := range output.Tests {
-		tests = append(tests, dto.CompletedTestResponse{
-			ID:       test.ID,
-			TestID:   test.TestID,
-			TestName: test.TestName,
-			Result:   test.Result,
-			Date:     test.Date,
+	attempts := make([]dto.AttemptHistoryEntryResponse, 0, len(output.Attempts))
+	for _, entry := range output.Attempts {
+		attempts = append(attempts, dto.AttemptHistoryEntryResponse{
+			ID:          entry.Answer.ID.String(),
+			Version:     entry.Answer.TestVersion,
+			Result:      entry.Answer.Result,
+			Dominant:    scaleIDsResponse(entry.Answer.Dominant),
+			Date:        entry.Answer.Date,
+			CompletedAt: entry.Answer.CompletedAt,
+			Changes:     scoreChangesResponse(entry.Changes),
 		})
 	}
 
-	ctx.JSON(http.StatusOK, dto.GetCompletedTestsResponse{Tests: tests})
+	ctx.JSON(http.StatusOK, dto.GetAttemptHistoryResponse{
+		TestID:   output.TestID.String(),
+		TestName: output.TestName,
+		UserID:   output.UserID.String(),
+		Attempts: attempts,
+		Overall:  scoreChangesResponse(output.Overall),
+	})
+}
+
+func (c *DashboardController) GetItemAnalysis(ctx *gin.Context) {
+	var req dto.GetItemAnalysisRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.itemAnalysisUC.Execute(ctx.Request.Context(), dashboardUseCase.GetItemAnalysisInput{
+		TestID:  req.TestID,
+		Version: req.Version,
+		Refresh: req.Refresh,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	questions := make(map[int]entity.Question, len(output.Questions))
+	for _, question := range output.Questions {
+		questions[question.ID] = question
+	}
+
+	analysis := output.Analysis
+	questionStats := make([]dto.QuestionStatsResponse, 0, len(analysis.Questions))
+	for _, stats := range analysis.Questions {
+		question := questions[stats.QuestionID]
+		questionStats = append(questionStats, dto.QuestionStatsResponse{
+			QuestionID:   stats.QuestionID,
+			QuestionBody: question.QuestionBody,
+			SelectType:   string(question.SelectType),
+			Responses:    stats.Responses,
+			Options:      frequenciesResponse(stats.Options),
+			Values:       frequenciesResponse(stats.Values),
+		})
+	}
+
+	scales := make([]dto.ScaleReliabilityResponse, 0, len(analysis.Scales))
+	for _, scale := range analysis.Scales {
+		items := make([]dto.ItemStatsResponse, 0, len(scale.Items))
+		for _, item := range scale.Items {
+			items = append(items, dto.ItemStatsResponse{
+				QuestionID:     item.QuestionID,
+				Mean:           item.Mean,
+				Difficulty:     item.Difficulty,
+				Discrimination: item.Discrimination,
+				ItemTotal:      item.ItemTotal,
+				AlphaIfDeleted: item.AlphaIfDeleted,
+			})
+		}
+		scales = append(scales, dto.ScaleReliabilityResponse{
+			ScaleID:     string(scale.ScaleID),
+			Name:        scale.Name,
+			Respondents: scale.Respondents,
+			Mean:        scale.Mean,
+			StdDev:      scale.StdDev,
+			Alpha:       scale.Alpha,
+			Items:       items,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, dto.GetItemAnalysisResponse{
+		TestID:      analysis.TestID.String(),
+		TestName:    output.TestName,
+		Version:     analysis.Version,
+		Respondents: analysis.Respondents,
+		Questions:   questionStats,
+		Scales:      scales,
+		ComputedAt:  analysis.ComputedAt,
+		Cached:      output.Cached,
+	})
+}
+
+func (c *DashboardController) BuildNorms(ctx *gin.Context) {
+	var req dto.BuildNormsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	table, err := c.buildNormsUC.Execute(ctx.Request.Context(), dashboardUseCase.BuildNormsInput{
+		TestID:      req.TestID,
+		TestVersion: req.TestVersion,
+		Name:        req.Name,
+		From:        req.From,
+		To:          req.To,
+		Cohort:      req.Cohort,
+		UserIDs:     req.UserIDs,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, normTableResponse(table))
+}
+
+func (c *DashboardController) FreezeNorms(ctx *gin.Context) {
+	var req dto.FreezeNormsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	table, err := c.freezeNormsUC.Execute(ctx.Request.Context(), dashboardUseCase.FreezeNormsInput{
+		NormID: req.NormID,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, normTableResponse(table))
+}
+
+func (c *DashboardController) GetNorms(ctx *gin.Context) {
+	var req dto.GetNormsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.getNormsUC.Execute(ctx.Request.Context(), dashboardUseCase.GetNormsInput{
+		TestID: req.TestID,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	tables := make([]dto.NormTableResponse, 0, len(output.Tables))
+	for _, table := range output.Tables {
+		tables = append(tables, normTableResponse(table))
+	}
+
+	ctx.JSON(http.StatusOK, dto.GetNormsResponse{
+		TestID:      req.TestID,
+		TestName:    output.TestName,
+		TestVersion: output.TestVersion,
+		Active:      output.Active,
+		Tables:      tables,
+	})
 }
 
 func (c *DashboardController) GetUserAnswers(ctx *gin.Context) {
@@ -229,26 +466,21 @@ func (c *DashboardController) GetUserAnswers(ctx *gin.Context) {
 		return
 	}
 
-	questions := make([]dto.QuestionResponse, 0, len(output.Questions))
-	for _, q := range output.Questions {
-		options := make([]dto.AnswerOptionResponse, 0, len(q.AnswerOptions))
-		for _, opt := range q.AnswerOptions {
-			options = append(options, dto.AnswerOptionResponse{
-				ID:   opt.ID,
-				Body: opt.Body,
-			})
+	rows := [][]int{}
+	responses := make([]dto.QuestionAnswerResponse, 0, len(output.Answers))
+	for _, answer := range output.Answers {
+		if row, ok := answer.Row(); ok {
+			rows = append(rows, row)
 		}
-		questions = append(questions, dto.QuestionResponse{
-			ID:            q.ID,
-			QuestionBody:  q.QuestionBody,
-			AnswerOptions: options,
-			SelectType:    q.SelectType,
-		})
+		responses = append(responses, questionAnswerResponse(answer))
 	}
 
 	ctx.JSON(http.StatusOK, dto.GetUserAnswersResponse{
-		Answers:   output.Answers,
-		Questions: questions,
+		TestID:    output.TestID.String(),
+		Version:   output.Version,
+		Answers:   rows,
+		Responses: responses,
+		Questions: questionsResponse(output.Questions),
 	})
 }
 
@@ -279,13 +511,104 @@ func (c *DashboardController) TerminalCommands(ctx *gin.Context) {
 	})
 }
 
+// scoreChangesResponse переводит изменения баллов в формат ответа
+func scoreChangesResponse(changes []dashboardUseCase.ScoreChange) []dto.ScoreChangeResponse {
+	response := make([]dto.ScoreChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		response = append(response, dto.ScoreChangeResponse{
+			ScaleID:      string(change.ScaleID),
+			Name:         change.Name,
+			Score:        change.Score,
+			Band:         change.Band,
+			Previous:     change.Previous,
+			PreviousBand: change.PreviousBand,
+			Delta:        change.Delta,
+		})
+	}
+	return response
+}
+
+// normTableResponse переводит таблицу норм в формат ответа
+func normTableResponse(table entity.NormTable) dto.NormTableResponse {
+	userIDs := make([]string, 0, len(table.Filter.UserIDs))
+	for _, userID := range table.Filter.UserIDs {
+		userIDs = append(userIDs, userID.String())
+	}
+
+	scales := make([]dto.ScaleNormResponse, 0, len(table.Scales))
+	for _, scale := range table.Scales {
+		points := make([]dto.NormPointResponse, 0, len(scale.Points))
+		for _, point := range scale.Points {
+			points = append(points, dto.NormPointResponse{Score: point.Score, Count: point.Count})
+		}
+		scales = append(scales, dto.ScaleNormResponse{
+			ScaleID: string(scale.ScaleID),
+			Name:    scale.Name,
+			Sample:  scale.Sample,
+			Mean:    scale.Mean,
+			StdDev:  scale.StdDev,
+			Points:  points,
+		})
+	}
+
+	return dto.NormTableResponse{
+		ID:          table.ID.String(),
+		TestID:      table.TestID.String(),
+		TestVersion: table.TestVersion,
+		Version:     table.Version,
+		Name:        table.Name,
+		Status:      string(table.Status),
+		Filter: dto.NormFilterResponse{
+			From:    table.Filter.From,
+			To:      table.Filter.To,
+			Cohort:  table.Filter.Cohort,
+			UserIDs: userIDs,
+		},
+		Sample:    table.Sample,
+		Scales:    scales,
+		CreatedBy: table.CreatedBy.String(),
+		CreatedAt: table.CreatedAt,
+		FrozenAt:  table.FrozenAt,
+	}
+}
+
+// frequenciesResponse переводит частоты ответов в формат ответа
+func frequenciesResponse(frequencies []entity.ResponseFrequency) []dto.ResponseFrequencyResponse {
+	if len(frequencies) == 0 {
+		return nil
+	}
+	response := make([]dto.ResponseFrequencyResponse, 0, len(frequencies))
+	for _, frequency := range frequencies {
+		response = append(response, dto.ResponseFrequencyResponse{
+			Key:   frequency.Key,
+			Label: frequency.Label,
+			Count: frequency.Count,
+			Share: frequency.Share,
+		})
+	}
+	return response
+}
+
 func (c *DashboardController) handleError(ctx *gin.Context, err error) {
 	switch {
+	case errors.Is(err, domainErrors.ErrUnauthorized):
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Требуется авторизация"})
 	case errors.Is(err, domainErrors.ErrInvalidInput):
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Некорректные данные"})
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Некорректные данные",
+			Message: validationMessage(err),
+			Fields:  validationFields(err),
+		})
+	case errors.Is(err, domainErrors.ErrEmptyNormSample):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Недостаточно данных для норм",
+			Message: validationMessage(err),
+		})
+	case errors.Is(err, domainErrors.ErrNormFrozen):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{Error: "Таблица норм уже закреплена"})
 	case errors.Is(err, domainErrors.ErrInvalidID):
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Некорректный ID"})
-	case errors.Is(err, domainErrors.ErrNotFound):
+	case errors.Is(err, domainErrors.ErrNotFound), errors.Is(err, domainErrors.ErrUserNotFound):
 		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Не найдено"})
 	case errors.Is(err, domainErrors.ErrForbidden):
 		ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "Доступ запрещен"})
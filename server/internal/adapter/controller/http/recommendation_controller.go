@@ -215,6 +215,8 @@ func (c *RecommendationController) DeleteSection(ctx *gin.Context) {
 
 func (c *RecommendationController) handleError(ctx *gin.Context, err error) {
 	switch {
+	case errors.Is(err, domainErrors.ErrUnauthorized):
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Требуется авторизация"})
 	case errors.Is(err, domainErrors.ErrInvalidInput):
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Некорректные данные"})
 	case errors.Is(err, domainErrors.ErrInvalidID):
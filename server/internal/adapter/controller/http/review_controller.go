@@ -65,7 +65,6 @@ func (c *ReviewController) CreateReview(ctx *gin.Context) {
 	}
 
 	output, err := c.createReviewUC.Execute(ctx.Request.Context(), reviewUseCase.CreateReviewInput{
-		UserID:     req.UserID,
 		ReviewBody: req.ReviewBody,
 	})
 	if err != nil {
@@ -92,7 +91,6 @@ func (c *ReviewController) UpdateReview(ctx *gin.Context) {
 
 	output, err := c.updateReviewUC.Execute(ctx.Request.Context(), reviewUseCase.UpdateReviewInput{
 		ReviewID:   req.ReviewID,
-		UserID:     req.UserID,
 		ReviewBody: req.ReviewBody,
 	})
 	if err != nil {
@@ -119,8 +117,6 @@ func (c *ReviewController) DeleteReview(ctx *gin.Context) {
 
 	err := c.deleteReviewUC.Execute(ctx.Request.Context(), reviewUseCase.DeleteReviewInput{
 		ReviewID: req.ReviewID,
-		UserID:   req.UserID,
-		IsAdmin:  req.IsAdmin,
 	})
 	if err != nil {
 		c.handleError(ctx, err)
@@ -139,7 +135,6 @@ func (c *ReviewController) ApproveOrDeny(ctx *gin.Context) {
 
 	output, err := c.moderateReviewUC.Execute(ctx.Request.Context(), reviewUseCase.ModerateReviewInput{
 		ReviewID: req.ReviewID,
-		AdminID:  req.AdminID,
 		Decision: req.Decision,
 	})
 	if err != nil {
@@ -159,6 +154,8 @@ func (c *ReviewController) ApproveOrDeny(ctx *gin.Context) {
 
 func (c *ReviewController) handleError(ctx *gin.Context, err error) {
 	switch {
+	case errors.Is(err, domainErrors.ErrUnauthorized):
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Требуется авторизация"})
 	case errors.Is(err, domainErrors.ErrInvalidInput):
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Некорректные данные"})
 	case errors.Is(err, domainErrors.ErrNotFound):
@@ -0,0 +1,224 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+
+	"server/internal/adapter/controller/dto"
+	"server/internal/domain/entity"
+	testUseCase "server/internal/usecase/test"
+)
+
+// Форматы файла пакета теста
+const (
+	bundleFormatJSON = "json"
+	bundleFormatYAML = "yaml"
+)
+
+// maxBundleSize - максимальный размер загружаемого пакета теста
+const maxBundleSize = 2 << 20
+
+// bundleFormat определяет формат пакета по явному значению или по Content-Type;
+// по умолчанию JSON
+func bundleFormat(format, contentType string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "":
+		if strings.Contains(strings.ToLower(contentType), "yaml") {
+			return bundleFormatYAML, true
+		}
+		return bundleFormatJSON, true
+	case "json":
+		return bundleFormatJSON, true
+	case "yaml", "yml":
+		return bundleFormatYAML, true
+	default:
+		return "", false
+	}
+}
+
+// encodeBundle сериализует пакет теста и возвращает данные и Content-Type
+func encodeBundle(bundle dto.TestBundle, format string) ([]byte, string, error) {
+	if format == bundleFormatYAML {
+		data, err := yaml.Marshal(bundle)
+		return data, "application/yaml; charset=utf-8", err
+	}
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	return data, "application/json; charset=utf-8", err
+}
+
+// decodeBundle разбирает пакет теста. Неизвестные поля считаются ошибкой,
+// чтобы опечатки в пакете не терялись молча
+func decodeBundle(data []byte, format string) (dto.TestBundle, error) {
+	var bundle dto.TestBundle
+	if format == bundleFormatYAML {
+		if err := yaml.UnmarshalWithOptions(data, &bundle, yaml.DisallowUnknownField()); err != nil {
+			return dto.TestBundle{}, fmt.Errorf("некорректный YAML: %s", yaml.FormatError(err, false, true))
+		}
+		return bundle, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&bundle); err != nil {
+		return dto.TestBundle{}, fmt.Errorf("некорректный JSON: %w", err)
+	}
+	return bundle, nil
+}
+
+// bundleFileName возвращает имя файла выгрузки теста
+func bundleFileName(testID entity.TestID, version int, format string) string {
+	return fmt.Sprintf("test-%s-v%d.%s", testID, version, format)
+}
+
+// testBundle собирает пакет теста для выгрузки
+func testBundle(test entity.Test, questionsDoc entity.QuestionsDocument, exportedAt time.Time) dto.TestBundle {
+	return dto.TestBundle{
+		Format:        testUseCase.BundleFormat,
+		FormatVersion: testUseCase.BundleFormatVersion,
+		ExportedAt:    &exportedAt,
+		Source: &dto.TestBundleSource{
+			TestID:  test.ID.String(),
+			Version: questionsDoc.Version,
+		},
+		TestName:    test.TestName,
+		AuthorsName: test.AuthorsName,
+		Description: test.Description,
+		Category:    test.Category,
+		Tags:        test.Tags,
+		IsTyping:    test.IsTyping,
+		Questions:   bundleQuestions(questionsDoc.Questions),
+		ResultLogic: bundleResultsLogic(questionsDoc.ResultsLogic),
+		TimeLimits:  timeLimitsResponse(questionsDoc.TimeLimits),
+		Retake:      retakePolicyResponse(test.Retake),
+		Shuffle:     shuffleResponse(questionsDoc.Shuffle),
+	}
+}
+
+// bundleInput переводит пакет теста во входной формат use case
+func bundleInput(bundle dto.TestBundle) testUseCase.TestBundleInput {
+	return testUseCase.TestBundleInput{
+		Format:        bundle.Format,
+		FormatVersion: bundle.FormatVersion,
+		TestName:      bundle.TestName,
+		AuthorsName:   bundle.AuthorsName,
+		Description:   bundle.Description,
+		Category:      bundle.Category,
+		Tags:          bundle.Tags,
+		IsTyping:      bundle.IsTyping,
+		Questions:     questionInputs(bundle.Questions),
+		ResultsLogic:  resultsLogicInput(bundle.ResultLogic),
+		TimeLimits:    timeLimitsInput(bundle.TimeLimits),
+		Retake:        retakePolicyInput(bundle.Retake),
+		Shuffle:       shuffleInput(bundle.Shuffle),
+	}
+}
+
+// bundleQuestions переводит вопросы в формат пакета вместе с весами шкал
+func bundleQuestions(questions []entity.Question) []dto.QuestionInput {
+	result := make([]dto.QuestionInput, 0, len(questions))
+	for _, q := range questions {
+		var options []dto.AnswerOptionInput
+		for _, opt := range q.AnswerOptions {
+			options = append(options, dto.AnswerOptionInput{
+				ID:      opt.ID,
+				Body:    opt.Body,
+				Weights: bundleWeights(opt.Weights),
+			})
+		}
+
+		var likert *dto.LikertScaleRequest
+		if q.Likert != nil {
+			likert = &dto.LikertScaleRequest{
+				Min:      q.Likert.Min,
+				Max:      q.Likert.Max,
+				MinLabel: q.Likert.MinLabel,
+				MaxLabel: q.Likert.MaxLabel,
+			}
+		}
+
+		var rows []dto.MatrixRowRequest
+		for _, row := range q.Rows {
+			rows = append(rows, dto.MatrixRowRequest{ID: row.ID, Body: row.Body})
+		}
+
+		result = append(result, dto.QuestionInput{
+			ID:            q.ID,
+			QuestionBody:  q.QuestionBody,
+			AnswerOptions: options,
+			SelectType:    string(q.SelectType),
+			Likert:        likert,
+			Rows:          rows,
+			MaxLength:     q.MaxLength,
+			Weights:       bundleWeights(q.Weights),
+			Branches:      branchRulesResponse(q.Branches),
+		})
+	}
+	return result
+}
+
+// bundleResultsLogic переводит правила подсчета в формат пакета
+func bundleResultsLogic(logic entity.ResultsLogic) dto.ResultsLogicRequest {
+	scales := make([]dto.ScaleRequest, 0, len(logic.Scales))
+	for _, scale := range logic.Scales {
+		var bands []dto.ScoreBandRequest
+		for _, band := range scale.Bands {
+			bands = append(bands, dto.ScoreBandRequest{
+				Min:   band.Min,
+				Max:   band.Max,
+				Label: band.Label,
+				Text:  band.Text,
+			})
+		}
+		scales = append(scales, dto.ScaleRequest{
+			ID:          string(scale.ID),
+			Name:        scale.Name,
+			Description: scale.Description,
+			Bands:       bands,
+		})
+	}
+
+	var rules []dto.ResultRuleRequest
+	for _, rule := range logic.Rules {
+		conditions := make([]dto.RuleConditionRequest, 0, len(rule.Conditions))
+		for _, condition := range rule.Conditions {
+			conditions = append(conditions, dto.RuleConditionRequest{
+				Scale:    string(condition.ScaleID),
+				Band:     condition.Band,
+				Min:      condition.Min,
+				Max:      condition.Max,
+				Above:    string(condition.Above),
+				Dominant: condition.Dominant,
+			})
+		}
+		rules = append(rules, dto.ResultRuleRequest{
+			ID:         rule.ID,
+			Conditions: conditions,
+			Text:       rule.Text,
+			Priority:   rule.Priority,
+		})
+	}
+
+	return dto.ResultsLogicRequest{
+		Scales:   scales,
+		Rules:    rules,
+		RuleMode: string(logic.RuleMode),
+		TieBreak: string(logic.TieBreak),
+	}
+}
+
+// bundleWeights переводит веса шкал в формат пакета; пустые веса не выгружаются
+func bundleWeights(weights map[entity.ScaleID]float64) map[string]float64 {
+	if len(weights) == 0 {
+		return nil
+	}
+	result := make(map[string]float64, len(weights))
+	for scaleID, weight := range weights {
+		result[string(scaleID)] = weight
+	}
+	return result
+}
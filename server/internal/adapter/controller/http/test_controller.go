@@ -3,21 +3,42 @@ package http
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"server/internal/adapter/controller/dto"
+	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
 	testUseCase "server/internal/usecase/test"
 )
 
 type TestController struct {
-	getTestsUC      *testUseCase.GetTestsUseCase
-	getQuestionsUC  *testUseCase.GetQuestionsUseCase
-	attemptTestUC   *testUseCase.AttemptTestUseCase
-	addTestUC       *testUseCase.AddTestUseCase
-	changeTestUC    *testUseCase.ChangeTestUseCase
-	deleteTestUC    *testUseCase.DeleteTestUseCase
+	getTestsUC     *testUseCase.GetTestsUseCase
+	getQuestionsUC *testUseCase.GetQuestionsUseCase
+	attemptTestUC  *testUseCase.AttemptTestUseCase
+	addTestUC      *testUseCase.AddTestUseCase
+	changeTestUC   *testUseCase.ChangeTestUseCase
+	deleteTestUC   *testUseCase.DeleteTestUseCase
+	recomputeUC    *testUseCase.RecomputeResultsUseCase
+	statusUC       *testUseCase.ChangeTestStatusUseCase
+	managedUC      *testUseCase.GetManagedTestsUseCase
+	exportUC       *testUseCase.ExportTestUseCase
+	importUC       *testUseCase.ImportTestUseCase
+	importCSVUC    *testUseCase.ImportCSVTestUseCase
+	startUC        *testUseCase.StartAttemptUseCase
+	openQuestionUC *testUseCase.OpenQuestionUseCase
+	saveAnswerUC   *testUseCase.SaveAttemptAnswerUseCase
+	submitUC       *testUseCase.SubmitAttemptUseCase
+	activeUC       *testUseCase.GetActiveAttemptsUseCase
+	claimUC        *testUseCase.ClaimAttemptUseCase
+	searchUC       *testUseCase.SearchTestsUseCase
+	addCollabUC    *testUseCase.AddCollaboratorUseCase
+	removeCollabUC *testUseCase.RemoveCollaboratorUseCase
+	transferUC     *testUseCase.TransferOwnershipUseCase
+	cloneUC        *testUseCase.CloneTestUseCase
 }
 
 func NewTestController(
@@ -27,6 +48,23 @@ func NewTestController(
 	addTestUC *testUseCase.AddTestUseCase,
 	changeTestUC *testUseCase.ChangeTestUseCase,
 	deleteTestUC *testUseCase.DeleteTestUseCase,
+	recomputeUC *testUseCase.RecomputeResultsUseCase,
+	statusUC *testUseCase.ChangeTestStatusUseCase,
+	managedUC *testUseCase.GetManagedTestsUseCase,
+	exportUC *testUseCase.ExportTestUseCase,
+	importUC *testUseCase.ImportTestUseCase,
+	importCSVUC *testUseCase.ImportCSVTestUseCase,
+	startUC *testUseCase.StartAttemptUseCase,
+	openQuestionUC *testUseCase.OpenQuestionUseCase,
+	saveAnswerUC *testUseCase.SaveAttemptAnswerUseCase,
+	submitUC *testUseCase.SubmitAttemptUseCase,
+	activeUC *testUseCase.GetActiveAttemptsUseCase,
+	claimUC *testUseCase.ClaimAttemptUseCase,
+	searchUC *testUseCase.SearchTestsUseCase,
+	addCollabUC *testUseCase.AddCollaboratorUseCase,
+	removeCollabUC *testUseCase.RemoveCollaboratorUseCase,
+	transferUC *testUseCase.TransferOwnershipUseCase,
+	cloneUC *testUseCase.CloneTestUseCase,
 ) *TestController {
 	return &TestController{
 		getTestsUC:     getTestsUC,
@@ -35,18 +73,60 @@ func NewTestController(
 		addTestUC:      addTestUC,
 		changeTestUC:   changeTestUC,
 		deleteTestUC:   deleteTestUC,
+		recomputeUC:    recomputeUC,
+		statusUC:       statusUC,
+		managedUC:      managedUC,
+		exportUC:       exportUC,
+		importUC:       importUC,
+		importCSVUC:    importCSVUC,
+		startUC:        startUC,
+		openQuestionUC: openQuestionUC,
+		saveAnswerUC:   saveAnswerUC,
+		submitUC:       submitUC,
+		activeUC:       activeUC,
+		claimUC:        claimUC,
+		searchUC:       searchUC,
+		addCollabUC:    addCollabUC,
+		removeCollabUC: removeCollabUC,
+		transferUC:     transferUC,
+		cloneUC:        cloneUC,
 	}
 }
 
 func (c *TestController) GetTests(ctx *gin.Context) {
-	var req dto.GetTestsRequest
+	output, err := c.getTestsUC.Execute(ctx.Request.Context())
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	tests := make([]dto.TestResponse, 0, len(output.Tests))
+	for _, t := range output.Tests {
+		test := testResponse(t.Test, t.IsCompleted)
+		canAttempt := t.CanAttempt
+		test.CanAttempt = &canAttempt
+		test.NextAttemptAt = t.NextAttemptAt
+		tests = append(tests, test)
+	}
+
+	ctx.JSON(http.StatusOK, dto.GetTestsResponse{Tests: tests})
+}
+
+func (c *TestController) SearchTests(ctx *gin.Context) {
+	var req dto.SearchTestsRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
 		return
 	}
 
-	output, err := c.getTestsUC.Execute(ctx.Request.Context(), testUseCase.GetTestsInput{
-		UserID: req.UserID,
+	output, err := c.searchUC.Execute(ctx.Request.Context(), testUseCase.SearchTestsInput{
+		Query:      req.Query,
+		Category:   req.Category,
+		Tags:       req.Tags,
+		Completion: req.Completion,
+		Sort:       req.Sort,
+		Limit:      req.Limit,
+		Cursor:     req.Cursor,
 	})
 	if err != nil {
 		c.handleError(ctx, err)
@@ -55,21 +135,101 @@ func (c *TestController) GetTests(ctx *gin.Context) {
 
 	tests := make([]dto.TestResponse, 0, len(output.Tests))
 	for _, t := range output.Tests {
-		tests = append(tests, dto.TestResponse{
-			ID:            t.Test.ID.String(),
-			TestName:      t.Test.TestName,
-			AuthorsName:   t.Test.AuthorsName,
-			QuestionCount: t.Test.QuestionCount,
-			Description:   t.Test.Description,
-			Date:          t.Test.Date,
-			Status:        string(t.Test.Status),
-			IsCompleted:   t.IsCompleted,
-		})
+		test := testResponse(t.Test, t.IsCompleted)
+		canAttempt := t.CanAttempt
+		test.CanAttempt = &canAttempt
+		test.NextAttemptAt = t.NextAttemptAt
+		tests = append(tests, test)
+	}
+
+	ctx.JSON(http.StatusOK, dto.SearchTestsResponse{Tests: tests, NextCursor: output.NextCursor})
+}
+
+func (c *TestController) GetMyTests(ctx *gin.Context) {
+	c.getManagedTests(ctx, testUseCase.GetManagedTestsInput{})
+}
+
+func (c *TestController) GetReviewQueue(ctx *gin.Context) {
+	c.getManagedTests(ctx, testUseCase.GetManagedTestsInput{ReviewQueue: true})
+}
+
+func (c *TestController) getManagedTests(ctx *gin.Context, input testUseCase.GetManagedTestsInput) {
+	output, err := c.managedUC.Execute(ctx.Request.Context(), input)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	tests := make([]dto.TestResponse, 0, len(output.Tests))
+	for _, t := range output.Tests {
+		test := testResponse(t.Test, false)
+		test.OwnerID = t.Test.UserID.String()
+		test.Access = t.Access.String()
+		test.Collaborators = collaboratorsResponse(t.Test.Collaborators)
+		tests = append(tests, test)
 	}
 
 	ctx.JSON(http.StatusOK, dto.GetTestsResponse{Tests: tests})
 }
 
+func (c *TestController) AddCollaborator(ctx *gin.Context) {
+	var req dto.AddCollaboratorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.addCollabUC.Execute(ctx.Request.Context(), testUseCase.AddCollaboratorInput{
+		TestID: req.TestID,
+		UserID: req.UserID,
+		Role:   req.Role,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, testAccessResponse(output.Test))
+}
+
+func (c *TestController) RemoveCollaborator(ctx *gin.Context) {
+	var req dto.RemoveCollaboratorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.removeCollabUC.Execute(ctx.Request.Context(), testUseCase.RemoveCollaboratorInput{
+		TestID: req.TestID,
+		UserID: req.UserID,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, testAccessResponse(output.Test))
+}
+
+func (c *TestController) TransferOwnership(ctx *gin.Context) {
+	var req dto.TransferOwnershipRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.transferUC.Execute(ctx.Request.Context(), testUseCase.TransferOwnershipInput{
+		TestID: req.TestID,
+		UserID: req.UserID,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, testAccessResponse(output.Test))
+}
+
 func (c *TestController) GetQuestions(ctx *gin.Context) {
 	var req dto.GetQuestionsRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -78,33 +238,32 @@ func (c *TestController) GetQuestions(ctx *gin.Context) {
 	}
 
 	output, err := c.getQuestionsUC.Execute(ctx.Request.Context(), testUseCase.GetQuestionsInput{
-		TestID: req.TestID,
+		TestID:    req.TestID,
+		Version:   req.Version,
+		AttemptID: req.AttemptID,
 	})
 	if err != nil {
 		c.handleError(ctx, err)
 		return
 	}
 
-	questions := make([]dto.QuestionResponse, 0, len(output.Questions))
-	for _, q := range output.Questions {
-		options := make([]dto.AnswerOptionResponse, 0, len(q.AnswerOptions))
-		for _, opt := range q.AnswerOptions {
-			options = append(options, dto.AnswerOptionResponse{
-				ID:   opt.ID,
-				Body: opt.Body,
-			})
-		}
-		questions = append(questions, dto.QuestionResponse{
-			ID:            q.ID,
-			QuestionBody:  q.QuestionBody,
-			AnswerOptions: options,
-			SelectType:    q.SelectType,
+	questions := questionsResponse(output.Questions)
+
+	scales := make([]dto.ScaleResponse, 0, len(output.ResultsLogic.Scales))
+	for _, scale := range output.ResultsLogic.Scales {
+		scales = append(scales, dto.ScaleResponse{
+			ID:          string(scale.ID),
+			Name:        scale.Name,
+			Description: scale.Description,
 		})
 	}
 
 	ctx.JSON(http.StatusOK, dto.GetQuestionsResponse{
-		Questions:    questions,
-		ResultsLogic: output.ResultsLogic,
+		Version:    output.Version,
+		Questions:  questions,
+		Scales:     scales,
+		TimeLimits: timeLimitsResponse(output.TimeLimits),
+		Shuffle:    shuffleResponse(output.Shuffle),
 	})
 }
 
@@ -116,52 +275,155 @@ func (c *TestController) AttemptTest(ctx *gin.Context) {
 	}
 
 	output, err := c.attemptTestUC.Execute(ctx.Request.Context(), testUseCase.AttemptTestInput{
-		UserID:  req.UserID,
 		TestID:  req.TestID,
-		Result:  req.Result,
-		Answers: req.Answers,
+		Version: req.Version,
+		Answers: attemptAnswers(req),
 	})
 	if err != nil {
 		c.handleError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, dto.AttemptTestResponse{
-		Success: "Тест пройден",
-		ID:      output.TestingAnswerID.String(),
+	ctx.JSON(http.StatusOK, attemptResponse(output))
+}
+
+func (c *TestController) StartAttempt(ctx *gin.Context) {
+	var req dto.StartAttemptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.startUC.Execute(ctx.Request.Context(), testUseCase.StartAttemptInput{
+		TestID:  req.TestID,
+		Version: req.Version,
 	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	response := attemptSessionResponse(output.Session)
+	response.Resumed = output.Resumed
+	ctx.JSON(http.StatusOK, response)
 }
 
-func (c *TestController) AddTest(ctx *gin.Context) {
-	var req dto.AddTestRequest
+func (c *TestController) SaveAttemptAnswer(ctx *gin.Context) {
+	var req dto.SaveAttemptAnswerRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
 		return
 	}
 
-	questions := make([]testUseCase.QuestionInput, 0, len(req.Questions))
-	for _, q := range req.Questions {
-		options := make([]testUseCase.AnswerOptionInput, 0, len(q.AnswerOptions))
-		for _, opt := range q.AnswerOptions {
-			options = append(options, testUseCase.AnswerOptionInput{
-				ID:   opt.ID,
-				Body: opt.Body,
-			})
-		}
-		questions = append(questions, testUseCase.QuestionInput{
-			ID:         q.ID,
-			Body:       q.QuestionBody,
-			Options:    options,
-			SelectType: q.SelectType,
-		})
+	output, err := c.saveAnswerUC.Execute(ctx.Request.Context(), testUseCase.SaveAttemptAnswerInput{
+		AttemptID: req.AttemptID,
+		Answer:    questionAnswer(req.Answer),
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attemptSessionResponse(output.Session))
+}
+
+func (c *TestController) OpenQuestion(ctx *gin.Context) {
+	var req dto.OpenQuestionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.openQuestionUC.Execute(ctx.Request.Context(), testUseCase.OpenQuestionInput{
+		AttemptID:  req.AttemptID,
+		QuestionID: req.QuestionID,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	response := attemptSessionResponse(output.Session)
+	response.QuestionDeadline = output.QuestionDeadline
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (c *TestController) SubmitAttempt(ctx *gin.Context) {
+	var req dto.SubmitAttemptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.submitUC.Execute(ctx.Request.Context(), testUseCase.SubmitAttemptInput{
+		AttemptID: req.AttemptID,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attemptResponse(output))
+}
+
+func (c *TestController) GetActiveAttempts(ctx *gin.Context) {
+	output, err := c.activeUC.Execute(ctx.Request.Context())
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	attempts := make([]dto.AttemptSessionResponse, 0, len(output.Sessions))
+	for _, session := range output.Sessions {
+		attempts = append(attempts, attemptSessionResponse(session))
+	}
+
+	ctx.JSON(http.StatusOK, dto.GetActiveAttemptsResponse{Attempts: attempts})
+}
+
+func (c *TestController) ClaimAttempt(ctx *gin.Context) {
+	var req dto.ClaimAttemptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.claimUC.Execute(ctx.Request.Context(), testUseCase.ClaimAttemptInput{
+		ClaimToken: req.ClaimToken,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ClaimAttemptResponse{
+		Success:     "Попытка добавлена в историю",
+		ID:          output.TestingAnswerID.String(),
+		TestID:      output.TestID.String(),
+		TestVersion: output.TestVersion,
+		PsychoType:  output.PsychoType,
+	})
+}
+
+func (c *TestController) AddTest(ctx *gin.Context) {
+	var req dto.AddTestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
 	}
 
 	output, err := c.addTestUC.Execute(ctx.Request.Context(), testUseCase.AddTestInput{
-		TestName:    req.TestName,
-		AuthorsName: req.AuthorsName,
-		Description: req.Description,
-		UserID:      req.UserID,
-		Questions:   questions,
+		TestName:     req.TestName,
+		AuthorsName:  req.AuthorsName,
+		Description:  req.Description,
+		Category:     req.Category,
+		Tags:         req.Tags,
+		Questions:    questionInputs(req.Questions),
+		ResultsLogic: resultsLogicInput(req.ResultLogic),
+		IsTyping:     req.IsTyping,
+		TimeLimits:   timeLimitsInput(req.TimeLimits),
+		Retake:       retakePolicyInput(req.Retake),
+		Shuffle:      shuffleInput(req.Shuffle),
 	})
 	if err != nil {
 		c.handleError(ctx, err)
@@ -174,43 +436,57 @@ func (c *TestController) AddTest(ctx *gin.Context) {
 	})
 }
 
-func (c *TestController) ChangeTest(ctx *gin.Context) {
-	var req dto.ChangeTestUpdateRequest
+func (c *TestController) CloneTest(ctx *gin.Context) {
+	var req dto.CloneTestRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
 		return
 	}
 
-	questions := make([]testUseCase.QuestionInput, 0, len(req.Questions))
-	for _, q := range req.Questions {
-		options := make([]testUseCase.AnswerOptionInput, 0, len(q.AnswerOptions))
-		for _, opt := range q.AnswerOptions {
-			options = append(options, testUseCase.AnswerOptionInput{
-				ID:   opt.ID,
-				Body: opt.Body,
-			})
-		}
-		questions = append(questions, testUseCase.QuestionInput{
-			ID:         q.ID,
-			Body:       q.QuestionBody,
-			Options:    options,
-			SelectType: q.SelectType,
-		})
+	output, err := c.cloneUC.Execute(ctx.Request.Context(), testUseCase.CloneTestInput{
+		TestID:   req.TestID,
+		Version:  req.Version,
+		TestName: req.TestName,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
 	}
 
-	_, err := c.changeTestUC.Update(ctx.Request.Context(), testUseCase.ChangeTestUpdateInput{
-		TestID:      req.TestID,
-		TestName:    req.TestName,
-		AuthorsName: req.AuthorsName,
-		Description: req.Description,
-		Questions:   questions,
+	ctx.JSON(http.StatusOK, dto.CloneTestResponse{
+		Success:    "Копия теста создана",
+		TestID:     output.Test.ID.String(),
+		ClonedFrom: *originResponse(output.Test.ClonedFrom),
+	})
+}
+
+func (c *TestController) ChangeTest(ctx *gin.Context) {
+	var req dto.ChangeTestUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.changeTestUC.Update(ctx.Request.Context(), testUseCase.ChangeTestUpdateInput{
+		TestID:       req.TestID,
+		TestName:     req.TestName,
+		AuthorsName:  req.AuthorsName,
+		Description:  req.Description,
+		Category:     req.Category,
+		Tags:         req.Tags,
+		Questions:    questionInputs(req.Questions),
+		ResultsLogic: resultsLogicInput(req.ResultLogic),
+		IsTyping:     req.IsTyping,
+		TimeLimits:   timeLimitsInput(req.TimeLimits),
+		Retake:       retakePolicyInput(req.Retake),
+		Shuffle:      shuffleInput(req.Shuffle),
 	})
 	if err != nil {
 		c.handleError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"success": "Тест обновлен"})
+	ctx.JSON(http.StatusOK, gin.H{"success": "Тест обновлен", "version": output.Test.Version})
 }
 
 func (c *TestController) DeleteTest(ctx *gin.Context) {
@@ -231,19 +507,706 @@ func (c *TestController) DeleteTest(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, dto.DeleteTestResponse{Success: "Тест удален"})
 }
 
+func (c *TestController) ChangeStatus(ctx *gin.Context) {
+	var req dto.ChangeTestStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.statusUC.Execute(ctx.Request.Context(), testUseCase.ChangeTestStatusInput{
+		TestID:      req.TestID,
+		Action:      req.Action,
+		PublishAt:   req.PublishAt,
+		UnpublishAt: req.UnpublishAt,
+		Comment:     req.Comment,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ChangeTestStatusResponse{
+		Success: "Статус теста изменен",
+		Test:    testResponse(output.Test, false),
+	})
+}
+
+func (c *TestController) ExportTest(ctx *gin.Context) {
+	var req dto.ExportTestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	format, ok := bundleFormat(req.Format, "")
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Неизвестный формат", Message: "Поддерживаются json и yaml"})
+		return
+	}
+
+	output, err := c.exportUC.Execute(ctx.Request.Context(), testUseCase.ExportTestInput{
+		TestID:  req.TestID,
+		Version: req.Version,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	data, contentType, err := encodeBundle(testBundle(output.Test, output.Questions, time.Now().UTC()), format)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Внутренняя ошибка"})
+		return
+	}
+
+	fileName := bundleFileName(output.Test.ID, output.Questions.Version, format)
+	ctx.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	ctx.Data(http.StatusOK, contentType, data)
+}
+
+func (c *TestController) ImportTest(ctx *gin.Context) {
+	format, ok := bundleFormat(ctx.Query("format"), ctx.ContentType())
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Неизвестный формат", Message: "Поддерживаются json и yaml"})
+		return
+	}
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные", Message: "dryRun должен быть true или false"})
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBundleSize)
+	data, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные", Message: "Пакет слишком большой или поврежден"})
+		return
+	}
+
+	bundle, err := decodeBundle(data, format)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные", Message: err.Error()})
+		return
+	}
+
+	output, err := c.importUC.Execute(ctx.Request.Context(), testUseCase.ImportTestInput{
+		Bundle: bundleInput(bundle),
+		DryRun: dryRun,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ImportTestResponse{
+		Valid:         output.Valid,
+		DryRun:        output.DryRun,
+		TestID:        output.Test.ID.String(),
+		QuestionCount: output.QuestionCount,
+		ScaleCount:    output.ScaleCount,
+		RuleCount:     output.RuleCount,
+		Problems:      fieldErrorsResponse(output.Problems),
+	})
+}
+
+func (c *TestController) ImportCSV(ctx *gin.Context) {
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные", Message: "dryRun должен быть true или false"})
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBundleSize)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные", Message: "Передайте CSV-файл в поле file"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные", Message: "Файл поврежден"})
+		return
+	}
+	defer file.Close()
+
+	input, err := csvImportForm(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные", Message: err.Error()})
+		return
+	}
+	input.CSV = file
+	input.DryRun = dryRun
+
+	output, err := c.importCSVUC.Execute(ctx.Request.Context(), input)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ImportTestResponse{
+		Valid:         output.Valid,
+		DryRun:        output.DryRun,
+		TestID:        output.Test.ID.String(),
+		QuestionCount: output.QuestionCount,
+		ScaleCount:    output.ScaleCount,
+		RuleCount:     output.RuleCount,
+		Problems:      fieldErrorsResponse(output.Problems),
+	})
+}
+
+// csvImportForm читает поля формы импорта CSV: данные теста и его настройки.
+// Списки можно передавать несколькими значениями или через запятую
+func csvImportForm(ctx *gin.Context) (testUseCase.ImportCSVTestInput, error) {
+	list := func(name string) []string {
+		var values []string
+		for _, value := range ctx.PostFormArray(name) {
+			values = append(values, strings.Split(value, ",")...)
+		}
+		return values
+	}
+
+	var problems []string
+	number := func(name string) int {
+		raw := strings.TrimSpace(ctx.PostForm(name))
+		if raw == "" {
+			return 0
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			problems = append(problems, name+" должен быть целым числом")
+		}
+		return value
+	}
+	flag := func(name string) bool {
+		raw := strings.TrimSpace(ctx.PostForm(name))
+		if raw == "" {
+			return false
+		}
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			problems = append(problems, name+" должен быть true или false")
+		}
+		return value
+	}
+
+	input := testUseCase.ImportCSVTestInput{
+		TestName:    ctx.PostForm("testName"),
+		AuthorsName: list("authorsName"),
+		Description: ctx.PostForm("description"),
+		Category:    ctx.PostForm("category"),
+		Tags:        list("tags"),
+		IsTyping:    flag("isTyping"),
+		TimeLimits: testUseCase.TimeLimitsInput{
+			TotalSeconds:    number("totalSeconds"),
+			QuestionSeconds: number("questionSeconds"),
+		},
+		Retake: testUseCase.RetakePolicyInput{
+			Mode:         ctx.PostForm("retakeMode"),
+			CooldownDays: number("retakeCooldownDays"),
+		},
+		Shuffle: testUseCase.ShuffleInput{
+			Questions: flag("shuffleQuestions"),
+			Options:   flag("shuffleOptions"),
+		},
+	}
+	if len(problems) > 0 {
+		return testUseCase.ImportCSVTestInput{}, errors.New(strings.Join(problems, "; "))
+	}
+	return input, nil
+}
+
+func (c *TestController) RecomputeResults(ctx *gin.Context) {
+	var req dto.RecomputeResultsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "некорректные данные"})
+		return
+	}
+
+	output, err := c.recomputeUC.Execute(ctx.Request.Context(), testUseCase.RecomputeResultsInput{
+		TestID: req.TestID,
+	})
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.RecomputeResultsResponse{
+		Success:    "Результаты пересчитаны",
+		Recomputed: output.Recomputed,
+		Skipped:    output.Skipped,
+	})
+}
+
 func (c *TestController) handleError(ctx *gin.Context, err error) {
 	switch {
+	case errors.Is(err, domainErrors.ErrUnauthorized):
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Требуется авторизация",
+			Message: validationMessage(err),
+		})
+	case errors.Is(err, domainErrors.ErrInvalidToken):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Попытка не найдена или срок ее хранения истек"})
 	case errors.Is(err, domainErrors.ErrInvalidInput):
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Некорректные данные"})
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Некорректные данные",
+			Message: validationMessage(err),
+			Fields:  validationFields(err),
+		})
 	case errors.Is(err, domainErrors.ErrInvalidID):
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Некорректный ID"})
+	case errors.Is(err, domainErrors.ErrForbidden):
+		ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "Доступ запрещен"})
 	case errors.Is(err, domainErrors.ErrNotFound):
 		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Не найдено"})
 	case errors.Is(err, domainErrors.ErrNoQuestions):
 		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Нет вопросов"})
+	case errors.Is(err, domainErrors.ErrInvalidScoring):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Некорректные правила подсчета результатов",
+			Message: validationMessage(err),
+		})
+	case errors.Is(err, domainErrors.ErrInvalidAnswers):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Некорректные ответы",
+			Message: validationMessage(err),
+			Fields:  validationFields(err),
+		})
+	case errors.Is(err, domainErrors.ErrTestUnavailable):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{Error: "Тест недоступен для прохождения"})
+	case errors.Is(err, domainErrors.ErrAttemptClosed):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{Error: "Попытка уже отправлена или истекла"})
+	case errors.Is(err, domainErrors.ErrTimeLimit):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Время истекло",
+			Message: validationMessage(err),
+		})
+	case errors.Is(err, domainErrors.ErrRetakeDenied):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Повторное прохождение недоступно",
+			Message: validationMessage(err),
+		})
+	case errors.Is(err, domainErrors.ErrTestTransition):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Недопустимая смена статуса теста",
+			Message: validationMessage(err),
+		})
+	case errors.Is(err, domainErrors.ErrTestNotEditable):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Тест нельзя изменить",
+			Message: validationMessage(err),
+		})
+	case errors.Is(err, domainErrors.ErrOwnerChanged):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Владелец теста изменился",
+			Message: validationMessage(err),
+		})
 	case errors.Is(err, domainErrors.ErrDatabase):
 		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Ошибка базы данных"})
 	default:
 		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Внутренняя ошибка"})
 	}
 }
+
+// testResponse переводит тест в формат ответа
+func testResponse(test entity.Test, isCompleted bool) dto.TestResponse {
+	return dto.TestResponse{
+		ID:            test.ID.String(),
+		TestName:      test.TestName,
+		AuthorsName:   test.AuthorsName,
+		QuestionCount: test.QuestionCount,
+		Description:   test.Description,
+		Category:      test.Category,
+		Tags:          test.Tags,
+		Date:          test.Date,
+		Status:        string(test.Status),
+		IsCompleted:   isCompleted,
+		IsTyping:      test.IsTyping,
+		Version:       test.Version,
+		PublishAt:     test.PublishAt,
+		UnpublishAt:   test.UnpublishAt,
+		ReviewComment: test.ReviewComment,
+		Retake:        retakePolicyResponse(test.Retake),
+		ClonedFrom:    originResponse(test.ClonedFrom),
+	}
+}
+
+// originResponse переводит исходный тест копии в формат ответа
+func originResponse(origin *entity.TestOrigin) *dto.TestOriginResponse {
+	if origin == nil {
+		return nil
+	}
+	return &dto.TestOriginResponse{TestID: origin.TestID.String(), Version: origin.Version}
+}
+
+// collaboratorsResponse переводит соавторов теста в формат ответа
+func collaboratorsResponse(collaborators []entity.Collaborator) []dto.CollaboratorResponse {
+	result := make([]dto.CollaboratorResponse, 0, len(collaborators))
+	for _, collaborator := range collaborators {
+		result = append(result, dto.CollaboratorResponse{
+			UserID:  collaborator.UserID.String(),
+			Role:    string(collaborator.Role),
+			AddedAt: collaborator.AddedAt,
+		})
+	}
+	return result
+}
+
+// testAccessResponse переводит владельца и соавторов теста в формат ответа
+func testAccessResponse(test entity.Test) dto.TestAccessResponse {
+	return dto.TestAccessResponse{
+		TestID:        test.ID.String(),
+		OwnerID:       test.UserID.String(),
+		Collaborators: collaboratorsResponse(test.Collaborators),
+	}
+}
+
+// questionInputs переводит вопросы из запроса во входной формат use case
+func questionInputs(req []dto.QuestionInput) []testUseCase.QuestionInput {
+	questions := make([]testUseCase.QuestionInput, 0, len(req))
+	for _, q := range req {
+		options := make([]testUseCase.AnswerOptionInput, 0, len(q.AnswerOptions))
+		for _, opt := range q.AnswerOptions {
+			options = append(options, testUseCase.AnswerOptionInput{
+				ID:      opt.ID,
+				Body:    opt.Body,
+				Weights: opt.Weights,
+			})
+		}
+		rows := make([]testUseCase.MatrixRowInput, 0, len(q.Rows))
+		for _, row := range q.Rows {
+			rows = append(rows, testUseCase.MatrixRowInput{ID: row.ID, Body: row.Body})
+		}
+
+		var likert *testUseCase.LikertInput
+		if q.Likert != nil {
+			likert = &testUseCase.LikertInput{
+				Min:      q.Likert.Min,
+				Max:      q.Likert.Max,
+				MinLabel: q.Likert.MinLabel,
+				MaxLabel: q.Likert.MaxLabel,
+			}
+		}
+
+		questions = append(questions, testUseCase.QuestionInput{
+			ID:         q.ID,
+			Body:       q.QuestionBody,
+			Options:    options,
+			SelectType: q.SelectType,
+			Likert:     likert,
+			Rows:       rows,
+			MaxLength:  q.MaxLength,
+			Weights:    q.Weights,
+			Branches:   branchRuleInputs(q.Branches),
+		})
+	}
+	return questions
+}
+
+// branchRuleInputs переводит правила перехода из запроса во входной формат use case
+func branchRuleInputs(req []dto.BranchRuleRequest) []testUseCase.BranchRuleInput {
+	rules := make([]testUseCase.BranchRuleInput, 0, len(req))
+	for _, rule := range req {
+		rules = append(rules, testUseCase.BranchRuleInput{
+			OptionIDs: rule.OptionIDs,
+			GoTo:      rule.GoTo,
+			Finish:    rule.Finish,
+		})
+	}
+	return rules
+}
+
+// branchRulesResponse переводит правила перехода в формат ответа
+func branchRulesResponse(rules []entity.BranchRule) []dto.BranchRuleRequest {
+	var response []dto.BranchRuleRequest
+	for _, rule := range rules {
+		response = append(response, dto.BranchRuleRequest{
+			OptionIDs: rule.OptionIDs,
+			GoTo:      rule.GoTo,
+			Finish:    rule.Finish,
+		})
+	}
+	return response
+}
+
+// questionsResponse переводит вопросы в формат ответа; веса шкал участникам не показываются
+func questionsResponse(questions []entity.Question) []dto.QuestionResponse {
+	response := make([]dto.QuestionResponse, 0, len(questions))
+	for _, q := range questions {
+		options := make([]dto.AnswerOptionResponse, 0, len(q.AnswerOptions))
+		for _, opt := range q.AnswerOptions {
+			options = append(options, dto.AnswerOptionResponse{
+				ID:   opt.ID,
+				Body: opt.Body,
+			})
+		}
+
+		var rows []dto.MatrixRowResponse
+		for _, row := range q.Rows {
+			rows = append(rows, dto.MatrixRowResponse{ID: row.ID, Body: row.Body})
+		}
+
+		var likert *dto.LikertScaleResponse
+		if q.Likert != nil {
+			likert = &dto.LikertScaleResponse{
+				Min:      q.Likert.Min,
+				Max:      q.Likert.Max,
+				MinLabel: q.Likert.MinLabel,
+				MaxLabel: q.Likert.MaxLabel,
+			}
+		}
+
+		response = append(response, dto.QuestionResponse{
+			ID:            q.ID,
+			QuestionBody:  q.QuestionBody,
+			AnswerOptions: options,
+			SelectType:    string(q.SelectType),
+			Likert:        likert,
+			Rows:          rows,
+			MaxLength:     q.MaxLength,
+			Branches:      branchRulesResponse(q.Branches),
+		})
+	}
+	return response
+}
+
+// attemptAnswers переводит ответы из запроса в доменный формат. Ответы в прежнем
+// формате [ID вопроса, ID варианта...] используются, только если responses не переданы
+func attemptAnswers(req dto.AttemptTestRequest) []entity.QuestionAnswer {
+	if len(req.Responses) == 0 {
+		answers := make([]entity.QuestionAnswer, 0, len(req.Answers))
+		for _, row := range req.Answers {
+			answers = append(answers, entity.QuestionAnswerFromRow(row))
+		}
+		return answers
+	}
+
+	answers := make([]entity.QuestionAnswer, 0, len(req.Responses))
+	for _, response := range req.Responses {
+		answers = append(answers, questionAnswer(response))
+	}
+	return answers
+}
+
+// questionAnswer переводит ответ на вопрос из запроса в доменный формат
+func questionAnswer(req dto.QuestionAnswerRequest) entity.QuestionAnswer {
+	cells := make([]entity.MatrixCell, 0, len(req.Cells))
+	for _, cell := range req.Cells {
+		cells = append(cells, entity.MatrixCell{RowID: cell.RowID, OptionID: cell.OptionID})
+	}
+	return entity.QuestionAnswer{
+		QuestionID: req.QuestionID,
+		OptionIDs:  req.OptionIDs,
+		Value:      req.Value,
+		Text:       req.Text,
+		Cells:      cells,
+	}
+}
+
+// questionAnswerResponse переводит ответ на вопрос в формат ответа
+func questionAnswerResponse(answer entity.QuestionAnswer) dto.QuestionAnswerResponse {
+	var cells []dto.MatrixCellResponse
+	for _, cell := range answer.Cells {
+		cells = append(cells, dto.MatrixCellResponse{RowID: cell.RowID, OptionID: cell.OptionID})
+	}
+	return dto.QuestionAnswerResponse{
+		QuestionID:  answer.QuestionID,
+		OptionIDs:   answer.OptionIDs,
+		Value:       answer.Value,
+		Text:        answer.Text,
+		Cells:       cells,
+		TimeSpentMs: answer.TimeSpent.Milliseconds(),
+	}
+}
+
+// attemptSessionResponse переводит незавершенную попытку в формат ответа
+func attemptSessionResponse(session entity.AttemptSession) dto.AttemptSessionResponse {
+	answers := make([]dto.QuestionAnswerResponse, 0, len(session.Answers))
+	for _, answer := range session.Answers {
+		answers = append(answers, questionAnswerResponse(session.Layout.Present(answer)))
+	}
+	return dto.AttemptSessionResponse{
+		ID:                   session.ID.String(),
+		TestID:               session.TestID.String(),
+		TestVersion:          session.TestVersion,
+		Answers:              answers,
+		StartedAt:            session.StartedAt,
+		UpdatedAt:            session.UpdatedAt,
+		ExpiresAt:            session.ExpiresAt,
+		Deadline:             session.Deadline,
+		QuestionTimeLimitSec: int(session.QuestionTimeLimit / time.Second),
+	}
+}
+
+// timeLimitsInput переводит ограничения времени из запроса во входной формат use case
+func timeLimitsInput(req *dto.TimeLimitsRequest) testUseCase.TimeLimitsInput {
+	if req == nil {
+		return testUseCase.TimeLimitsInput{}
+	}
+	return testUseCase.TimeLimitsInput{
+		TotalSeconds:    req.TotalSeconds,
+		QuestionSeconds: req.QuestionSeconds,
+	}
+}
+
+// timeLimitsResponse переводит ограничения времени в формат ответа; nil - без ограничений
+func timeLimitsResponse(limits entity.TimeLimits) *dto.TimeLimitsRequest {
+	if !limits.IsSet() {
+		return nil
+	}
+	return &dto.TimeLimitsRequest{
+		TotalSeconds:    int(limits.Total / time.Second),
+		QuestionSeconds: int(limits.PerQuestion / time.Second),
+	}
+}
+
+// shuffleInput переводит настройки перемешивания из запроса во входной формат use case
+func shuffleInput(req *dto.ShuffleRequest) testUseCase.ShuffleInput {
+	if req == nil {
+		return testUseCase.ShuffleInput{}
+	}
+	return testUseCase.ShuffleInput{
+		Questions: req.Questions,
+		Options:   req.Options,
+	}
+}
+
+// shuffleResponse переводит настройки перемешивания в формат ответа; nil - без перемешивания
+func shuffleResponse(shuffle entity.ShuffleSettings) *dto.ShuffleRequest {
+	if !shuffle.IsSet() {
+		return nil
+	}
+	return &dto.ShuffleRequest{
+		Questions: shuffle.Questions,
+		Options:   shuffle.Options,
+	}
+}
+
+// retakePolicyInput переводит правило повторного прохождения из запроса во входной формат use case
+func retakePolicyInput(req *dto.RetakePolicyRequest) testUseCase.RetakePolicyInput {
+	if req == nil {
+		return testUseCase.RetakePolicyInput{}
+	}
+	return testUseCase.RetakePolicyInput{
+		Mode:         req.Mode,
+		CooldownDays: req.CooldownDays,
+	}
+}
+
+// retakePolicyResponse переводит правило повторного прохождения в формат ответа; nil - без ограничений
+func retakePolicyResponse(policy entity.RetakePolicy) *dto.RetakePolicyRequest {
+	if policy.IsUnlimited() {
+		return nil
+	}
+	return &dto.RetakePolicyRequest{
+		Mode:         string(policy.Mode),
+		CooldownDays: policy.CooldownDays,
+	}
+}
+
+// attemptResponse переводит результат попытки в формат ответа
+func attemptResponse(output testUseCase.AttemptTestOutput) dto.AttemptTestResponse {
+	return dto.AttemptTestResponse{
+		Success:        "Тест пройден",
+		ID:             output.TestingAnswerID.String(),
+		TestVersion:    output.TestVersion,
+		Result:         output.Result,
+		Scores:         normedScoresResponse(output.Scores, output.Norms),
+		Dominant:       scaleIDsResponse(output.Dominant),
+		MatchedRules:   output.MatchedRules,
+		PsychoType:     output.PsychoType,
+		ClaimToken:     output.ClaimToken,
+		ClaimExpiresAt: output.ClaimExpiresAt,
+		NormVersion:    output.NormVersion,
+	}
+}
+
+// resultsLogicInput переводит правила подсчета из запроса во входной формат use case
+func resultsLogicInput(req dto.ResultsLogicRequest) testUseCase.ResultsLogicInput {
+	scales := make([]testUseCase.ScaleInput, 0, len(req.Scales))
+	for _, scale := range req.Scales {
+		bands := make([]testUseCase.ScoreBandInput, 0, len(scale.Bands))
+		for _, band := range scale.Bands {
+			bands = append(bands, testUseCase.ScoreBandInput{
+				Min:   band.Min,
+				Max:   band.Max,
+				Label: band.Label,
+				Text:  band.Text,
+			})
+		}
+		scales = append(scales, testUseCase.ScaleInput{
+			ID:          scale.ID,
+			Name:        scale.Name,
+			Description: scale.Description,
+			Bands:       bands,
+		})
+	}
+
+	rules := make([]testUseCase.ResultRuleInput, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		conditions := make([]testUseCase.RuleConditionInput, 0, len(rule.Conditions))
+		for _, condition := range rule.Conditions {
+			conditions = append(conditions, testUseCase.RuleConditionInput{
+				Scale:    condition.Scale,
+				Band:     condition.Band,
+				Min:      condition.Min,
+				Max:      condition.Max,
+				Above:    condition.Above,
+				Dominant: condition.Dominant,
+			})
+		}
+		rules = append(rules, testUseCase.ResultRuleInput{
+			ID:         rule.ID,
+			Conditions: conditions,
+			Text:       rule.Text,
+			Priority:   rule.Priority,
+		})
+	}
+
+	return testUseCase.ResultsLogicInput{
+		Scales:   scales,
+		Rules:    rules,
+		RuleMode: req.RuleMode,
+		TieBreak: req.TieBreak,
+	}
+}
+
+// scaleScoresResponse переводит баллы по шкалам в формат ответа
+func scaleScoresResponse(scores []entity.ScaleScore) []dto.ScaleScoreResponse {
+	response := make([]dto.ScaleScoreResponse, 0, len(scores))
+	for _, score := range scores {
+		response = append(response, dto.ScaleScoreResponse{
+			ScaleID:  string(score.ScaleID),
+			Name:     score.Name,
+			Score:    score.Score,
+			Band:     score.Band,
+			BandText: score.BandText,
+		})
+	}
+	return response
+}
+
+// normedScoresResponse переводит баллы по шкалам в формат ответа вместе с их
+// положением относительно норм
+func normedScoresResponse(scores []entity.ScaleScore, normScores []entity.NormScore) []dto.ScaleScoreResponse {
+	response := scaleScoresResponse(scores)
+	for _, normScore := range normScores {
+		for i := range response {
+			if response[i].ScaleID == string(normScore.ScaleID) {
+				percentile := normScore.Percentile
+				response[i].Percentile = &percentile
+				response[i].Stanine = normScore.Stanine
+			}
+		}
+	}
+	return response
+}
+
+// scaleIDsResponse переводит идентификаторы шкал в формат ответа
+func scaleIDsResponse(ids []entity.ScaleID) []string {
+	response := make([]string, 0, len(ids))
+	for _, id := range ids {
+		response = append(response, string(id))
+	}
+	return response
+}
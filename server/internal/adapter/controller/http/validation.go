@@ -0,0 +1,39 @@
+package http
+
+import (
+	"errors"
+
+	"server/internal/adapter/controller/dto"
+	domainErrors "server/internal/domain/errors"
+)
+
+// validationMessage возвращает пояснение из ошибки валидации, если оно есть
+func validationMessage(err error) string {
+	var validationErr *domainErrors.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Message
+	}
+	return ""
+}
+
+// validationFields возвращает ошибки отдельных полей из ошибки валидации
+func validationFields(err error) []dto.FieldErrorResponse {
+	var validationErr *domainErrors.ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Fields) == 0 {
+		return nil
+	}
+
+	return fieldErrorsResponse(validationErr.Fields)
+}
+
+// fieldErrorsResponse переводит ошибки полей в формат ответа
+func fieldErrorsResponse(fieldErrors []domainErrors.FieldError) []dto.FieldErrorResponse {
+	fields := make([]dto.FieldErrorResponse, 0, len(fieldErrors))
+	for _, field := range fieldErrors {
+		fields = append(fields, dto.FieldErrorResponse{
+			Field:   field.Field,
+			Message: field.Message,
+		})
+	}
+	return fields
+}
@@ -0,0 +1,142 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"server/internal/adapter/repository/mongodb/model"
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+const anonymousAttemptsCollectionName = "AnonymousAttempt"
+
+type AnonymousAttemptRepository struct {
+	db *mongo.Database
+}
+
+func NewAnonymousAttemptRepository(db *mongo.Database) *AnonymousAttemptRepository {
+	return &AnonymousAttemptRepository{db: db}
+}
+
+func (r *AnonymousAttemptRepository) collection() *mongo.Collection {
+	return r.db.Collection(anonymousAttemptsCollectionName)
+}
+
+// EnsureIndexes создает индексы анонимных попыток: TTL-индекс, по которому MongoDB
+// удаляет попытки с истекшим сроком хранения, и уникальный индекс по хешу токена привязки
+func (r *AnonymousAttemptRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expires").SetExpireAfterSeconds(0),
+		},
+		{
+			Keys:    bson.D{{Key: "claimTokenHash", Value: 1}},
+			Options: options.Index().SetName("claim_token").SetUnique(true),
+		},
+	})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+	return nil
+}
+
+func (r *AnonymousAttemptRepository) Insert(ctx context.Context, attempt entity.AnonymousAttempt) (entity.AnonymousAttemptID, error) {
+	testID, err := primitive.ObjectIDFromHex(attempt.Answer.TestID.String())
+	if err != nil {
+		return "", domainErrors.ErrInvalidID
+	}
+
+	responses := make([]model.QuestionAnswerDocument, 0, len(attempt.Answers))
+	for _, answer := range attempt.Answers {
+		responses = append(responses, questionAnswerToDocument(answer))
+	}
+
+	// Возвращенная после неудачной привязки попытка сохраняется под прежним ID
+	var objectID primitive.ObjectID
+	if !attempt.ID.IsEmpty() {
+		objectID, err = primitive.ObjectIDFromHex(attempt.ID.String())
+		if err != nil {
+			return "", domainErrors.ErrInvalidID
+		}
+	}
+
+	doc := model.AnonymousAttemptDocument{
+		ID:             objectID,
+		ClaimTokenHash: attempt.ClaimTokenHash,
+		TestID:         testID,
+		TestVersion:    attempt.Answer.TestVersion,
+		Result:         attempt.Answer.Result,
+		Scores:         scaleScoresToDocument(attempt.Answer.Scores),
+		Dominant:       scaleIDsToDocument(attempt.Answer.Dominant),
+		MatchedRules:   attempt.Answer.MatchedRules,
+		Date:           attempt.Answer.Date,
+		CompletedAt:    attempt.Answer.CompletedAt,
+		Responses:      responses,
+		PsychoType:     attempt.PsychoType,
+		CreatedAt:      attempt.CreatedAt,
+		ExpiresAt:      attempt.ExpiresAt,
+	}
+
+	result, err := r.collection().InsertOne(ctx, doc)
+	if err != nil {
+		return "", domainErrors.ErrDatabase
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", domainErrors.ErrDatabase
+	}
+	return entity.AnonymousAttemptID(insertedID.Hex()), nil
+}
+
+func (r *AnonymousAttemptRepository) TakeByTokenHash(ctx context.Context, tokenHash string) (entity.AnonymousAttempt, error) {
+	var doc model.AnonymousAttemptDocument
+	err := r.collection().FindOneAndDelete(ctx, bson.M{"claimTokenHash": tokenHash}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entity.AnonymousAttempt{}, domainErrors.ErrInvalidToken
+		}
+		return entity.AnonymousAttempt{}, domainErrors.ErrDatabase
+	}
+
+	return r.toEntity(doc), nil
+}
+
+// Конвертеры
+
+func (r *AnonymousAttemptRepository) toEntity(doc model.AnonymousAttemptDocument) entity.AnonymousAttempt {
+	answers := make([]entity.QuestionAnswer, 0, len(doc.Responses))
+	for _, response := range doc.Responses {
+		answers = append(answers, questionAnswerToEntity(response))
+	}
+
+	matchedRules := doc.MatchedRules
+	if matchedRules == nil {
+		matchedRules = []string{}
+	}
+
+	return entity.AnonymousAttempt{
+		ID:             entity.AnonymousAttemptID(doc.ID.Hex()),
+		ClaimTokenHash: doc.ClaimTokenHash,
+		Answer: entity.UserAnswer{
+			TestID:       entity.TestID(doc.TestID.Hex()),
+			TestVersion:  doc.TestVersion,
+			Result:       doc.Result,
+			Scores:       scaleScoresToEntity(doc.Scores),
+			Dominant:     scaleIDsToEntity(doc.Dominant),
+			MatchedRules: matchedRules,
+			Date:         doc.Date,
+			CompletedAt:  doc.CompletedAt,
+		},
+		Answers:    answers,
+		PsychoType: doc.PsychoType,
+		CreatedAt:  doc.CreatedAt,
+		ExpiresAt:  doc.ExpiresAt,
+	}
+}
@@ -0,0 +1,312 @@
+package mongodb
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"server/internal/adapter/repository/mongodb/model"
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+const attemptSessionsCollectionName = "AttemptSession"
+
+type AttemptSessionRepository struct {
+	db *mongo.Database
+}
+
+func NewAttemptSessionRepository(db *mongo.Database) *AttemptSessionRepository {
+	return &AttemptSessionRepository{db: db}
+}
+
+func (r *AttemptSessionRepository) collection() *mongo.Collection {
+	return r.db.Collection(attemptSessionsCollectionName)
+}
+
+// EnsureIndexes создает TTL-индекс, по которому MongoDB удаляет истекшие попытки
+func (r *AttemptSessionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetName("expires").SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+	return nil
+}
+
+func (r *AttemptSessionRepository) Insert(ctx context.Context, session entity.AttemptSession) (entity.AttemptSessionID, error) {
+	userID, err := primitive.ObjectIDFromHex(session.UserID.String())
+	if err != nil {
+		return "", domainErrors.ErrInvalidID
+	}
+	testID, err := primitive.ObjectIDFromHex(session.TestID.String())
+	if err != nil {
+		return "", domainErrors.ErrInvalidID
+	}
+
+	doc := model.AttemptSessionDocument{
+		UserID:               userID,
+		TestID:               testID,
+		TestVersion:          session.TestVersion,
+		Answers:              make(map[string]model.QuestionAnswerDocument, len(session.Answers)),
+		StartedAt:            session.StartedAt,
+		UpdatedAt:            session.UpdatedAt,
+		ExpiresAt:            session.ExpiresAt,
+		SubmittedAt:          session.SubmittedAt,
+		Deadline:             session.Deadline,
+		QuestionTimeLimitSec: int(session.QuestionTimeLimit / time.Second),
+		QuestionOrder:        session.Layout.QuestionOrder,
+	}
+	for _, answer := range session.Answers {
+		doc.Answers[strconv.Itoa(answer.QuestionID)] = questionAnswerToDocument(answer)
+	}
+	if len(session.Layout.OptionOrder) > 0 {
+		doc.OptionOrder = make(map[string][]int, len(session.Layout.OptionOrder))
+		for questionID, order := range session.Layout.OptionOrder {
+			doc.OptionOrder[strconv.Itoa(questionID)] = order
+		}
+	}
+	if len(session.QuestionOpenedAt) > 0 {
+		doc.QuestionOpenedAt = make(map[string]time.Time, len(session.QuestionOpenedAt))
+		for questionID, openedAt := range session.QuestionOpenedAt {
+			doc.QuestionOpenedAt[strconv.Itoa(questionID)] = openedAt
+		}
+	}
+
+	result, err := r.collection().InsertOne(ctx, doc)
+	if err != nil {
+		return "", domainErrors.ErrDatabase
+	}
+
+	insertedID := result.InsertedID.(primitive.ObjectID)
+	return entity.AttemptSessionID(insertedID.Hex()), nil
+}
+
+func (r *AttemptSessionRepository) FindByID(ctx context.Context, id entity.AttemptSessionID) (entity.AttemptSession, error) {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return entity.AttemptSession{}, domainErrors.ErrInvalidID
+	}
+
+	var doc model.AttemptSessionDocument
+	err = r.collection().FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entity.AttemptSession{}, domainErrors.ErrNotFound
+		}
+		return entity.AttemptSession{}, domainErrors.ErrDatabase
+	}
+
+	return r.toEntity(doc), nil
+}
+
+func (r *AttemptSessionRepository) FindActiveByUser(ctx context.Context, userID entity.UserID, now time.Time) ([]entity.AttemptSession, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID.String())
+	if err != nil {
+		return nil, domainErrors.ErrInvalidID
+	}
+
+	filter := bson.M{
+		"userId":      objectID,
+		"submittedAt": bson.M{"$exists": false},
+		"expiresAt":   bson.M{"$gt": now},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "updatedAt", Value: -1}})
+
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+	defer cursor.Close(ctx)
+
+	var docs []model.AttemptSessionDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+
+	sessions := make([]entity.AttemptSession, 0, len(docs))
+	for _, doc := range docs {
+		sessions = append(sessions, r.toEntity(doc))
+	}
+
+	return sessions, nil
+}
+
+func (r *AttemptSessionRepository) SaveAnswer(
+	ctx context.Context,
+	id entity.AttemptSessionID,
+	answer entity.QuestionAnswer,
+	updatedAt, expiresAt time.Time,
+) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	// Условия на submittedAt и expiresAt не дают изменить отправленную или истекшую попытку
+	result, err := r.collection().UpdateOne(
+		ctx,
+		bson.M{
+			"_id":         objectID,
+			"submittedAt": bson.M{"$exists": false},
+			"expiresAt":   bson.M{"$gt": updatedAt},
+		},
+		bson.M{
+			"$set": bson.M{
+				"answers." + strconv.Itoa(answer.QuestionID): questionAnswerToDocument(answer),
+				"updatedAt": updatedAt,
+				"expiresAt": expiresAt,
+			},
+			"$unset": bson.M{"currentQuestionId": "", "currentQuestionAt": ""},
+		},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *AttemptSessionRepository) OpenQuestion(
+	ctx context.Context,
+	id entity.AttemptSessionID,
+	questionID int,
+	openedAt, expiresAt time.Time,
+) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	result, err := r.collection().UpdateOne(
+		ctx,
+		bson.M{
+			"_id":         objectID,
+			"submittedAt": bson.M{"$exists": false},
+			"expiresAt":   bson.M{"$gt": openedAt},
+		},
+		bson.M{
+			"$set": bson.M{
+				"currentQuestionId": questionID,
+				"currentQuestionAt": openedAt,
+				"updatedAt":         openedAt,
+				"expiresAt":         expiresAt,
+			},
+			// $min сохраняет момент первого открытия вопроса
+			"$min": bson.M{"questionOpenedAt." + strconv.Itoa(questionID): openedAt},
+		},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *AttemptSessionRepository) MarkSubmitted(ctx context.Context, id entity.AttemptSessionID, submittedAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	// Условие на submittedAt гарантирует, что попытка будет отправлена только один раз
+	result, err := r.collection().UpdateOne(
+		ctx,
+		bson.M{
+			"_id":         objectID,
+			"submittedAt": bson.M{"$exists": false},
+			"expiresAt":   bson.M{"$gt": submittedAt},
+		},
+		bson.M{"$set": bson.M{"submittedAt": submittedAt}},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.ModifiedCount == 0 {
+		return domainErrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *AttemptSessionRepository) Reopen(ctx context.Context, id entity.AttemptSessionID) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	_, err = r.collection().UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$unset": bson.M{"submittedAt": ""}},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	return nil
+}
+
+// Конвертеры
+
+func (r *AttemptSessionRepository) toEntity(doc model.AttemptSessionDocument) entity.AttemptSession {
+	answers := make([]entity.QuestionAnswer, 0, len(doc.Answers))
+	for _, answer := range doc.Answers {
+		answers = append(answers, questionAnswerToEntity(answer))
+	}
+	sort.Slice(answers, func(i, j int) bool { return answers[i].QuestionID < answers[j].QuestionID })
+
+	layout := entity.AttemptLayout{QuestionOrder: doc.QuestionOrder}
+	if len(doc.OptionOrder) > 0 {
+		layout.OptionOrder = make(map[int][]int, len(doc.OptionOrder))
+		for key, order := range doc.OptionOrder {
+			if questionID, err := strconv.Atoi(key); err == nil {
+				layout.OptionOrder[questionID] = order
+			}
+		}
+	}
+	var openedAt map[int]time.Time
+	if len(doc.QuestionOpenedAt) > 0 {
+		openedAt = make(map[int]time.Time, len(doc.QuestionOpenedAt))
+		for key, at := range doc.QuestionOpenedAt {
+			if questionID, err := strconv.Atoi(key); err == nil {
+				openedAt[questionID] = at
+			}
+		}
+	}
+
+	return entity.AttemptSession{
+		ID:                entity.AttemptSessionID(doc.ID.Hex()),
+		UserID:            entity.UserID(doc.UserID.Hex()),
+		TestID:            entity.TestID(doc.TestID.Hex()),
+		TestVersion:       versionOrFirst(doc.TestVersion),
+		Answers:           answers,
+		StartedAt:         doc.StartedAt,
+		UpdatedAt:         doc.UpdatedAt,
+		ExpiresAt:         doc.ExpiresAt,
+		SubmittedAt:       doc.SubmittedAt,
+		Deadline:          doc.Deadline,
+		QuestionTimeLimit: time.Duration(doc.QuestionTimeLimitSec) * time.Second,
+		CurrentQuestionID: doc.CurrentQuestionID,
+		CurrentQuestionAt: doc.CurrentQuestionAt,
+		QuestionOpenedAt:  openedAt,
+		Layout:            layout,
+	}
+}
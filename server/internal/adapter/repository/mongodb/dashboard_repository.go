@@ -6,6 +6,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"server/internal/adapter/repository/mongodb/model"
 	"server/internal/domain/entity"
@@ -102,13 +103,7 @@ func (r *DashboardRepository) FindCompletedTests(ctx context.Context, userID ent
 
 	answers := make([]entity.UserAnswer, 0, len(docs))
 	for _, doc := range docs {
-		answers = append(answers, entity.UserAnswer{
-			ID:     entity.UserAnswerID(doc.ID.Hex()),
-			UserID: entity.UserID(doc.UserID.Hex()),
-			TestID: entity.TestID(doc.TestID.Hex()),
-			Result: doc.Result,
-			Date:   doc.Date,
-		})
+		answers = append(answers, userAnswerDocToEntity(doc))
 	}
 
 	return answers, nil
@@ -133,13 +128,40 @@ func (r *DashboardRepository) FindUserAnswersByTest(ctx context.Context, testID
 
 	answers := make([]entity.UserAnswer, 0, len(docs))
 	for _, doc := range docs {
-		answers = append(answers, entity.UserAnswer{
-			ID:     entity.UserAnswerID(doc.ID.Hex()),
-			UserID: entity.UserID(doc.UserID.Hex()),
-			TestID: entity.TestID(doc.TestID.Hex()),
-			Result: doc.Result,
-			Date:   doc.Date,
-		})
+		answers = append(answers, userAnswerDocToEntity(doc))
+	}
+
+	return answers, nil
+}
+
+func (r *DashboardRepository) FindAttemptsByUserAndTest(ctx context.Context, userID entity.UserID, testID entity.TestID) ([]entity.UserAnswer, error) {
+	userOID, err := primitive.ObjectIDFromHex(userID.String())
+	if err != nil {
+		return nil, domainErrors.ErrInvalidID
+	}
+
+	testOID, err := primitive.ObjectIDFromHex(testID.String())
+	if err != nil {
+		return nil, domainErrors.ErrInvalidID
+	}
+
+	// Идентификаторы документов возрастают со временем создания, в том числе
+	// у попыток, сохраненных до появления completedAt
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.userAnswersCollection().Find(ctx, bson.M{"userId": userOID, "testId": testOID}, opts)
+	if err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+	defer cursor.Close(ctx)
+
+	var docs []model.UserAnswerDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+
+	answers := make([]entity.UserAnswer, 0, len(docs))
+	for _, doc := range docs {
+		answers = append(answers, userAnswerDocToEntity(doc))
 	}
 
 	return answers, nil
@@ -160,21 +182,35 @@ func (r *DashboardRepository) FindAnswerDetailsByAnswerID(ctx context.Context, a
 		return entity.UserAnswerDetails{}, domainErrors.ErrDatabase
 	}
 
-	return entity.UserAnswerDetails{
-		ID:              entity.UserAnswerID(doc.ID.Hex()),
-		TestingAnswerID: entity.UserAnswerID(doc.TestingAnswerID.Hex()),
-		Answers:         doc.Answers,
-	}, nil
+	return answerDetailsDocToEntity(doc), nil
+}
+
+func (r *DashboardRepository) FindCompletedTestByID(ctx context.Context, answerID entity.UserAnswerID) (entity.UserAnswer, error) {
+	objectID, err := primitive.ObjectIDFromHex(answerID.String())
+	if err != nil {
+		return entity.UserAnswer{}, domainErrors.ErrInvalidID
+	}
+
+	var doc model.UserAnswerDocument
+	err = r.userAnswersCollection().FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entity.UserAnswer{}, domainErrors.ErrNotFound
+		}
+		return entity.UserAnswer{}, domainErrors.ErrDatabase
+	}
+
+	return userAnswerDocToEntity(doc), nil
 }
 
-func (r *DashboardRepository) FindQuestionsByTestID(ctx context.Context, testID entity.TestID) ([]entity.Question, error) {
+func (r *DashboardRepository) FindQuestionsVersion(ctx context.Context, testID entity.TestID, version int) ([]entity.Question, error) {
 	objectID, err := primitive.ObjectIDFromHex(testID.String())
 	if err != nil {
 		return nil, domainErrors.ErrInvalidID
 	}
 
 	var doc model.QuestionsDocument
-	err = r.questionsCollection().FindOne(ctx, bson.M{"testingId": objectID}).Decode(&doc)
+	err = r.questionsCollection().FindOne(ctx, questionsVersionFilter(objectID, version)).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domainErrors.ErrNotFound
@@ -182,24 +218,7 @@ func (r *DashboardRepository) FindQuestionsByTestID(ctx context.Context, testID
 		return nil, domainErrors.ErrDatabase
 	}
 
-	questions := make([]entity.Question, 0, len(doc.Questions))
-	for _, q := range doc.Questions {
-		options := make([]entity.AnswerOption, 0, len(q.AnswerOptions))
-		for _, opt := range q.AnswerOptions {
-			options = append(options, entity.AnswerOption{
-				ID:   opt.ID,
-				Body: opt.Body,
-			})
-		}
-		questions = append(questions, entity.Question{
-			ID:            q.ID,
-			QuestionBody:  q.QuestionBody,
-			AnswerOptions: options,
-			SelectType:    q.SelectType,
-		})
-	}
-
-	return questions, nil
+	return questionsToEntity(doc.Questions), nil
 }
 
 func (r *DashboardRepository) UpdateUserStatus(ctx context.Context, userID entity.UserID, status entity.UserStatus) error {
@@ -222,6 +241,29 @@ func (r *DashboardRepository) UpdateUserStatus(ctx context.Context, userID entit
 	return nil
 }
 
+func (r *DashboardRepository) UpdateUserRole(ctx context.Context, userID entity.UserID, role entity.Role, status entity.UserStatus) error {
+	objectID, err := primitive.ObjectIDFromHex(userID.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	result, err := r.usersCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{
+			"role":   string(role),
+			"status": string(status),
+		}},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrUserNotFound
+	}
+	return nil
+}
+
 func (r *DashboardRepository) UpdateUserData(ctx context.Context, userID entity.UserID, firstName, lastName string) error {
 	objectID, err := primitive.ObjectIDFromHex(userID.String())
 	if err != nil {
@@ -268,11 +310,15 @@ func userDocToEntity(doc model.UserDocument) entity.User {
 		LastName:      doc.LastName,
 		Email:         doc.Email,
 		Status:        entity.UserStatus(doc.Status),
+		Role:          entity.Role(doc.Role),
 		Password:      doc.Password,
 		PsychoType:    doc.PsychoType,
 		Date:          doc.Date,
 		IsGoogleAdded: doc.IsGoogleAdded,
 		IsYandexAdded: doc.IsYandexAdded,
-		Sessions:      doc.Sessions,
+		Sessions:      sessionsToEntity(doc.Sessions),
+
+		ExternalAccounts:  externalAccountsToEntity(doc.ExternalAccounts),
+		PsychoTypeHistory: psychoTypeHistoryToEntity(doc.PsychoTypeHistory),
 	}
 }
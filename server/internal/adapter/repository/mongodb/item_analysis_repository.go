@@ -0,0 +1,207 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"server/internal/adapter/repository/mongodb/model"
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+const itemAnalysesCollectionName = "ItemAnalysis"
+
+type ItemAnalysisRepository struct {
+	db *mongo.Database
+}
+
+func NewItemAnalysisRepository(db *mongo.Database) *ItemAnalysisRepository {
+	return &ItemAnalysisRepository{db: db}
+}
+
+func (r *ItemAnalysisRepository) collection() *mongo.Collection {
+	return r.db.Collection(itemAnalysesCollectionName)
+}
+
+func (r *ItemAnalysisRepository) FindByTestVersion(ctx context.Context, testID entity.TestID, version int) (entity.ItemAnalysis, error) {
+	objectID, err := primitive.ObjectIDFromHex(testID.String())
+	if err != nil {
+		return entity.ItemAnalysis{}, domainErrors.ErrInvalidID
+	}
+
+	var doc model.ItemAnalysisDocument
+	err = r.collection().FindOne(ctx, bson.M{"testId": objectID, "version": version}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entity.ItemAnalysis{}, domainErrors.ErrNotFound
+		}
+		return entity.ItemAnalysis{}, domainErrors.ErrDatabase
+	}
+
+	return r.toEntity(doc), nil
+}
+
+func (r *ItemAnalysisRepository) Save(ctx context.Context, analysis entity.ItemAnalysis) error {
+	doc, err := r.toDocument(analysis)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().ReplaceOne(
+		ctx,
+		bson.M{"testId": doc.TestID, "version": doc.Version},
+		doc,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+	return nil
+}
+
+// Конвертеры
+
+func (r *ItemAnalysisRepository) toEntity(doc model.ItemAnalysisDocument) entity.ItemAnalysis {
+	analysis := entity.ItemAnalysis{
+		TestID:      entity.TestID(doc.TestID.Hex()),
+		Version:     doc.Version,
+		Respondents: doc.Respondents,
+		Questions:   make([]entity.QuestionStats, 0, len(doc.Questions)),
+		Scales:      make([]entity.ScaleReliability, 0, len(doc.Scales)),
+		Source:      entity.AnswerSummary{Count: doc.SourceCount},
+		ComputedAt:  doc.ComputedAt,
+	}
+	if !doc.LastAnswerID.IsZero() {
+		analysis.Source.LastAnswerID = entity.UserAnswerID(doc.LastAnswerID.Hex())
+	}
+
+	for _, question := range doc.Questions {
+		analysis.Questions = append(analysis.Questions, entity.QuestionStats{
+			QuestionID: question.QuestionID,
+			Responses:  question.Responses,
+			Options:    frequenciesToEntity(question.Options),
+			Values:     frequenciesToEntity(question.Values),
+		})
+	}
+
+	for _, scale := range doc.Scales {
+		items := make([]entity.ItemStats, 0, len(scale.Items))
+		for _, item := range scale.Items {
+			items = append(items, entity.ItemStats{
+				QuestionID:     item.QuestionID,
+				Mean:           item.Mean,
+				Difficulty:     item.Difficulty,
+				Discrimination: item.Discrimination,
+				ItemTotal:      item.ItemTotal,
+				AlphaIfDeleted: item.AlphaIfDeleted,
+			})
+		}
+		analysis.Scales = append(analysis.Scales, entity.ScaleReliability{
+			ScaleID:     entity.ScaleID(scale.ScaleID),
+			Name:        scale.Name,
+			Respondents: scale.Respondents,
+			Mean:        scale.Mean,
+			StdDev:      scale.StdDev,
+			Alpha:       scale.Alpha,
+			Items:       items,
+		})
+	}
+
+	return analysis
+}
+
+func (r *ItemAnalysisRepository) toDocument(analysis entity.ItemAnalysis) (model.ItemAnalysisDocument, error) {
+	testID, err := primitive.ObjectIDFromHex(analysis.TestID.String())
+	if err != nil {
+		return model.ItemAnalysisDocument{}, domainErrors.ErrInvalidID
+	}
+
+	doc := model.ItemAnalysisDocument{
+		TestID:      testID,
+		Version:     analysis.Version,
+		Respondents: analysis.Respondents,
+		Questions:   make([]model.QuestionStatsDocument, 0, len(analysis.Questions)),
+		Scales:      make([]model.ScaleReliabilityDocument, 0, len(analysis.Scales)),
+		SourceCount: analysis.Source.Count,
+		ComputedAt:  analysis.ComputedAt,
+	}
+	if !analysis.Source.LastAnswerID.IsEmpty() {
+		lastAnswerID, err := primitive.ObjectIDFromHex(analysis.Source.LastAnswerID.String())
+		if err != nil {
+			return model.ItemAnalysisDocument{}, domainErrors.ErrInvalidID
+		}
+		doc.LastAnswerID = lastAnswerID
+	}
+
+	for _, question := range analysis.Questions {
+		doc.Questions = append(doc.Questions, model.QuestionStatsDocument{
+			QuestionID: question.QuestionID,
+			Responses:  question.Responses,
+			Options:    frequenciesToDocument(question.Options),
+			Values:     frequenciesToDocument(question.Values),
+		})
+	}
+
+	for _, scale := range analysis.Scales {
+		items := make([]model.ItemStatsDocument, 0, len(scale.Items))
+		for _, item := range scale.Items {
+			items = append(items, model.ItemStatsDocument{
+				QuestionID:     item.QuestionID,
+				Mean:           item.Mean,
+				Difficulty:     item.Difficulty,
+				Discrimination: item.Discrimination,
+				ItemTotal:      item.ItemTotal,
+				AlphaIfDeleted: item.AlphaIfDeleted,
+			})
+		}
+		doc.Scales = append(doc.Scales, model.ScaleReliabilityDocument{
+			ScaleID:     string(scale.ScaleID),
+			Name:        scale.Name,
+			Respondents: scale.Respondents,
+			Mean:        scale.Mean,
+			StdDev:      scale.StdDev,
+			Alpha:       scale.Alpha,
+			Items:       items,
+		})
+	}
+
+	return doc, nil
+}
+
+// frequenciesToDocument конвертирует частоты ответов в документы
+func frequenciesToDocument(frequencies []entity.ResponseFrequency) []model.ResponseFrequencyDocument {
+	if frequencies == nil {
+		return nil
+	}
+	docs := make([]model.ResponseFrequencyDocument, 0, len(frequencies))
+	for _, frequency := range frequencies {
+		docs = append(docs, model.ResponseFrequencyDocument{
+			Key:   frequency.Key,
+			Label: frequency.Label,
+			Count: frequency.Count,
+			Share: frequency.Share,
+		})
+	}
+	return docs
+}
+
+// frequenciesToEntity конвертирует документы частот ответов в сущности
+func frequenciesToEntity(docs []model.ResponseFrequencyDocument) []entity.ResponseFrequency {
+	if docs == nil {
+		return nil
+	}
+	frequencies := make([]entity.ResponseFrequency, 0, len(docs))
+	for _, doc := range docs {
+		frequencies = append(frequencies, entity.ResponseFrequency{
+			Key:   doc.Key,
+			Label: doc.Label,
+			Count: doc.Count,
+			Share: doc.Share,
+		})
+	}
+	return frequencies
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AnonymousAttemptDocument - MongoDB документ попытки без входа в аккаунт: результат
+// в формате ответа пользователя и ответы на вопросы. Хранится до expiresAt
+type AnonymousAttemptDocument struct {
+	ID             primitive.ObjectID       `bson:"_id,omitempty"`
+	ClaimTokenHash string                   `bson:"claimTokenHash"`
+	TestID         primitive.ObjectID       `bson:"testId"`
+	TestVersion    int                      `bson:"testVersion"`
+	Result         string                   `bson:"result"`
+	Scores         []ScaleScoreDocument     `bson:"scores,omitempty"`
+	Dominant       []string                 `bson:"dominant,omitempty"`
+	MatchedRules   []string                 `bson:"matchedRules,omitempty"`
+	Date           string                   `bson:"date"`
+	CompletedAt    time.Time                `bson:"completedAt"`
+	Responses      []QuestionAnswerDocument `bson:"responses"`
+	PsychoType     string                   `bson:"psychoType,omitempty"`
+	CreatedAt      time.Time                `bson:"createdAt"`
+	ExpiresAt      time.Time                `bson:"expiresAt"`
+}
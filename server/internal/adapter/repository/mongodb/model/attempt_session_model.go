@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AttemptSessionDocument - MongoDB документ незавершенной попытки. Ответы хранятся
+// по ID вопроса, чтобы сохранение ответа заменяло предыдущий одной операцией.
+// Время на ответ на один вопрос хранится в секундах, порядок вариантов и моменты
+// первого открытия вопросов - по ID вопроса
+type AttemptSessionDocument struct {
+	ID                   primitive.ObjectID                `bson:"_id,omitempty"`
+	UserID               primitive.ObjectID                `bson:"userId"`
+	TestID               primitive.ObjectID                `bson:"testId"`
+	TestVersion          int                               `bson:"testVersion"`
+	Answers              map[string]QuestionAnswerDocument `bson:"answers"`
+	StartedAt            time.Time                         `bson:"startedAt"`
+	UpdatedAt            time.Time                         `bson:"updatedAt"`
+	ExpiresAt            time.Time                         `bson:"expiresAt"`
+	SubmittedAt          *time.Time                        `bson:"submittedAt,omitempty"`
+	Deadline             *time.Time                        `bson:"deadline,omitempty"`
+	QuestionTimeLimitSec int                               `bson:"questionTimeLimitSec,omitempty"`
+	CurrentQuestionID    int                               `bson:"currentQuestionId,omitempty"`
+	CurrentQuestionAt    *time.Time                        `bson:"currentQuestionAt,omitempty"`
+	QuestionOpenedAt     map[string]time.Time              `bson:"questionOpenedAt,omitempty"`
+	QuestionOrder        []int                             `bson:"questionOrder,omitempty"`
+	OptionOrder          map[string][]int                  `bson:"optionOrder,omitempty"`
+}
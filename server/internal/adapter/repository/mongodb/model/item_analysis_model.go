@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ItemAnalysisDocument - MongoDB документ анализа вопросов версии теста. Один документ
+// на пару testId и version; sourceCount и lastAnswerId описывают учтенные попытки
+type ItemAnalysisDocument struct {
+	ID           primitive.ObjectID         `bson:"_id,omitempty"`
+	TestID       primitive.ObjectID         `bson:"testId"`
+	Version      int                        `bson:"version"`
+	Respondents  int                        `bson:"respondents"`
+	Questions    []QuestionStatsDocument    `bson:"questions"`
+	Scales       []ScaleReliabilityDocument `bson:"scales"`
+	SourceCount  int                        `bson:"sourceCount"`
+	LastAnswerID primitive.ObjectID         `bson:"lastAnswerId,omitempty"`
+	ComputedAt   time.Time                  `bson:"computedAt"`
+}
+
+// QuestionStatsDocument - MongoDB документ распределения ответов на вопрос
+type QuestionStatsDocument struct {
+	QuestionID int                         `bson:"questionId"`
+	Responses  int                         `bson:"responses"`
+	Options    []ResponseFrequencyDocument `bson:"options,omitempty"`
+	Values     []ResponseFrequencyDocument `bson:"values,omitempty"`
+}
+
+// ResponseFrequencyDocument - MongoDB документ частоты выбора варианта или значения
+type ResponseFrequencyDocument struct {
+	Key   int     `bson:"key"`
+	Label string  `bson:"label,omitempty"`
+	Count int     `bson:"count"`
+	Share float64 `bson:"share"`
+}
+
+// ScaleReliabilityDocument - MongoDB документ надежности шкалы
+type ScaleReliabilityDocument struct {
+	ScaleID     string              `bson:"scaleId"`
+	Name        string              `bson:"name"`
+	Respondents int                 `bson:"respondents"`
+	Mean        float64             `bson:"mean"`
+	StdDev      float64             `bson:"stdDev"`
+	Alpha       *float64            `bson:"alpha,omitempty"`
+	Items       []ItemStatsDocument `bson:"items"`
+}
+
+// ItemStatsDocument - MongoDB документ показателей вопроса по шкале
+type ItemStatsDocument struct {
+	QuestionID     int      `bson:"questionId"`
+	Mean           float64  `bson:"mean"`
+	Difficulty     *float64 `bson:"difficulty,omitempty"`
+	Discrimination *float64 `bson:"discrimination,omitempty"`
+	ItemTotal      *float64 `bson:"itemTotal,omitempty"`
+	AlphaIfDeleted *float64 `bson:"alphaIfDeleted,omitempty"`
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NormTableDocument - MongoDB документ таблицы норм теста. Version равен 0 у черновика.
+// У таблиц, построенных до учета версий теста, TestVersion отсутствует
+type NormTableDocument struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty"`
+	TestID      primitive.ObjectID  `bson:"testId"`
+	TestVersion int                 `bson:"testVersion,omitempty"`
+	Version     int                 `bson:"version"`
+	Name        string              `bson:"name"`
+	Filter      NormFilterDocument  `bson:"filter"`
+	Status      string              `bson:"status"`
+	Sample      int                 `bson:"sample"`
+	Scales      []ScaleNormDocument `bson:"scales"`
+	CreatedBy   primitive.ObjectID  `bson:"createdBy"`
+	CreatedAt   time.Time           `bson:"createdAt"`
+	FrozenAt    *time.Time          `bson:"frozenAt,omitempty"`
+}
+
+// NormFilterDocument - MongoDB документ отбора попыток для норм
+type NormFilterDocument struct {
+	From    *time.Time           `bson:"from,omitempty"`
+	To      *time.Time           `bson:"to,omitempty"`
+	Cohort  string               `bson:"cohort,omitempty"`
+	UserIDs []primitive.ObjectID `bson:"userIds,omitempty"`
+}
+
+// ScaleNormDocument - MongoDB документ распределения баллов шкалы
+type ScaleNormDocument struct {
+	ScaleID string              `bson:"scaleId"`
+	Name    string              `bson:"name"`
+	Sample  int                 `bson:"sample"`
+	Mean    float64             `bson:"mean"`
+	StdDev  float64             `bson:"stdDev"`
+	Points  []NormPointDocument `bson:"points"`
+}
+
+// NormPointDocument - MongoDB документ балла шкалы и числа попыток с ним
+type NormPointDocument struct {
+	Score float64 `bson:"score"`
+	Count int     `bson:"count"`
+}
@@ -1,54 +1,210 @@
 package model
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // TestDocument - MongoDB документ теста
 type TestDocument struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty"`
-	TestName      string             `bson:"testName"`
-	AuthorsName   []string           `bson:"authorsName"`
-	QuestionCount int                `bson:"questionCount"`
-	Description   string             `bson:"description"`
-	Date          string             `bson:"date"`
-	Status        string             `bson:"status"`
-	UserID        primitive.ObjectID `bson:"userId"`
+	ID                 primitive.ObjectID     `bson:"_id,omitempty"`
+	TestName           string                 `bson:"testName"`
+	AuthorsName        []string               `bson:"authorsName"`
+	QuestionCount      int                    `bson:"questionCount"`
+	Description        string                 `bson:"description"`
+	Category           string                 `bson:"category,omitempty"`
+	Tags               []string               `bson:"tags,omitempty"`
+	Date               string                 `bson:"date"`
+	Status             string                 `bson:"status"`
+	UserID             primitive.ObjectID     `bson:"userId"`
+	IsTyping           bool                   `bson:"isTyping,omitempty"`
+	Version            int                    `bson:"version,omitempty"`
+	PublishAt          *time.Time             `bson:"publishAt,omitempty"`
+	UnpublishAt        *time.Time             `bson:"unpublishAt,omitempty"`
+	ReviewComment      string                 `bson:"reviewComment,omitempty"`
+	RetakeMode         string                 `bson:"retakeMode,omitempty"`
+	RetakeCooldownDays int                    `bson:"retakeCooldownDays,omitempty"`
+	Collaborators      []CollaboratorDocument `bson:"collaborators,omitempty"`
+	ClonedFrom         *TestOriginDocument    `bson:"clonedFrom,omitempty"`
+}
+
+// TestOriginDocument - исходный тест копии
+type TestOriginDocument struct {
+	TestID  primitive.ObjectID `bson:"testId"`
+	Version int                `bson:"version"`
+}
+
+// CollaboratorDocument - соавтор теста
+type CollaboratorDocument struct {
+	UserID  primitive.ObjectID `bson:"userId"`
+	Role    string             `bson:"role"`
+	AddedAt time.Time          `bson:"addedAt"`
 }
 
-// QuestionsDocument - MongoDB документ с вопросами теста
+// TestSearchDocument - тест в результатах поиска по каталогу вместе
+// с релевантностью текстовому запросу
+type TestSearchDocument struct {
+	TestDocument `bson:",inline"`
+	Score        float64 `bson:"score,omitempty"`
+}
+
+// QuestionsDocument - MongoDB документ с вопросами одной версии теста.
+// ResultsLogic хранится как вложенный документ ResultsLogicDocument;
+// в старых документах это пустая строка. В документах до появления версий
+// поле version отсутствует - это первая версия. Ограничения времени хранятся в секундах
 type QuestionsDocument struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty"`
-	Questions    []QuestionDocument `bson:"questions"`
-	ResultsLogic string             `bson:"resultsLogic"`
-	TestingID    primitive.ObjectID `bson:"testingId"`
+	ID                   primitive.ObjectID `bson:"_id,omitempty"`
+	Questions            []QuestionDocument `bson:"questions"`
+	ResultsLogic         bson.RawValue      `bson:"resultsLogic"`
+	TestingID            primitive.ObjectID `bson:"testingId"`
+	Version              int                `bson:"version,omitempty"`
+	CreatedAt            time.Time          `bson:"createdAt,omitempty"`
+	TimeLimitSec         int                `bson:"timeLimitSec,omitempty"`
+	QuestionTimeLimitSec int                `bson:"questionTimeLimitSec,omitempty"`
+	ShuffleQuestions     bool               `bson:"shuffleQuestions,omitempty"`
+	ShuffleOptions       bool               `bson:"shuffleOptions,omitempty"`
+}
+
+// ResultsLogicDocument - MongoDB документ правил подсчета результатов
+type ResultsLogicDocument struct {
+	Scales   []ScaleDocument      `bson:"scales"`
+	Rules    []ResultRuleDocument `bson:"rules,omitempty"`
+	RuleMode string               `bson:"ruleMode,omitempty"`
+	TieBreak string               `bson:"tieBreak,omitempty"`
 }
 
-// QuestionDocument - MongoDB документ вопроса
+// ScaleDocument - MongoDB документ шкалы теста
+type ScaleDocument struct {
+	ID          string              `bson:"id"`
+	Name        string              `bson:"name"`
+	Description string              `bson:"description,omitempty"`
+	Bands       []ScoreBandDocument `bson:"bands,omitempty"`
+}
+
+// ScoreBandDocument - MongoDB документ диапазона баллов шкалы
+type ScoreBandDocument struct {
+	Min   float64 `bson:"min"`
+	Max   float64 `bson:"max"`
+	Label string  `bson:"label"`
+	Text  string  `bson:"text,omitempty"`
+}
+
+// ResultRuleDocument - MongoDB документ правила интерпретации
+type ResultRuleDocument struct {
+	ID         string                  `bson:"id"`
+	Conditions []RuleConditionDocument `bson:"conditions"`
+	Text       string                  `bson:"text"`
+	Priority   int                     `bson:"priority,omitempty"`
+}
+
+// RuleConditionDocument - MongoDB документ условия правила интерпретации
+type RuleConditionDocument struct {
+	ScaleID  string   `bson:"scaleId"`
+	Band     string   `bson:"band,omitempty"`
+	Min      *float64 `bson:"min,omitempty"`
+	Max      *float64 `bson:"max,omitempty"`
+	Above    string   `bson:"above,omitempty"`
+	Dominant bool     `bson:"dominant,omitempty"`
+}
+
+// QuestionDocument - MongoDB документ вопроса. SelectType хранит тип вопроса
 type QuestionDocument struct {
 	ID            int                    `bson:"id"`
 	QuestionBody  string                 `bson:"questionBody"`
 	AnswerOptions []AnswerOptionDocument `bson:"answerOptions"`
 	SelectType    string                 `bson:"selectType"`
+	Likert        *LikertScaleDocument   `bson:"likert,omitempty"`
+	Rows          []MatrixRowDocument    `bson:"rows,omitempty"`
+	MaxLength     int                    `bson:"maxLength,omitempty"`
+	Weights       map[string]float64     `bson:"weights,omitempty"`
+	Branches      []BranchRuleDocument   `bson:"branches,omitempty"`
 }
 
-// AnswerOptionDocument - MongoDB документ варианта ответа
-type AnswerOptionDocument struct {
+// BranchRuleDocument - MongoDB документ правила перехода после ответа на вопрос
+type BranchRuleDocument struct {
+	OptionIDs []int `bson:"optionIds,omitempty"`
+	GoTo      int   `bson:"goTo,omitempty"`
+	Finish    bool  `bson:"finish,omitempty"`
+}
+
+// LikertScaleDocument - MongoDB документ настроек шкалы Лайкерта
+type LikertScaleDocument struct {
+	Min      int    `bson:"min"`
+	Max      int    `bson:"max"`
+	MinLabel string `bson:"minLabel,omitempty"`
+	MaxLabel string `bson:"maxLabel,omitempty"`
+}
+
+// MatrixRowDocument - MongoDB документ строки матричного вопроса
+type MatrixRowDocument struct {
 	ID   int    `bson:"id"`
 	Body string `bson:"body"`
 }
 
+// AnswerOptionDocument - MongoDB документ варианта ответа
+type AnswerOptionDocument struct {
+	ID      int                `bson:"id"`
+	Body    string             `bson:"body"`
+	Weights map[string]float64 `bson:"weights,omitempty"`
+}
+
 // UserAnswerDocument - MongoDB документ ответа пользователя
 type UserAnswerDocument struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	UserID    primitive.ObjectID `bson:"userId"`
-	TestID    primitive.ObjectID `bson:"testId"`
-	Result    string             `bson:"result"`
-	Date      string             `bson:"date"`
-	CreatedAt interface{}        `bson:"createdAt,omitempty"`
+	ID           primitive.ObjectID   `bson:"_id,omitempty"`
+	UserID       primitive.ObjectID   `bson:"userId"`
+	TestID       primitive.ObjectID   `bson:"testId"`
+	TestVersion  int                  `bson:"testVersion,omitempty"`
+	Result       string               `bson:"result"`
+	Scores       []ScaleScoreDocument `bson:"scores,omitempty"`
+	Dominant     []string             `bson:"dominant,omitempty"`
+	MatchedRules []string             `bson:"matchedRules,omitempty"`
+	Date         string               `bson:"date"`
+	CreatedAt    interface{}          `bson:"createdAt,omitempty"`
+	CompletedAt  time.Time            `bson:"completedAt,omitempty"`
 }
 
-// UserAnswerDetailsDocument - MongoDB документ детальных ответов
+// ScaleScoreDocument - MongoDB документ баллов по шкале
+type ScaleScoreDocument struct {
+	ScaleID  string  `bson:"scaleId"`
+	Name     string  `bson:"name"`
+	Score    float64 `bson:"score"`
+	Band     string  `bson:"band,omitempty"`
+	BandText string  `bson:"bandText,omitempty"`
+}
+
+// UserAnswerDetailsDocument - MongoDB документ детальных ответов.
+// Responses хранит ответы всех типов; Answers - ответы с вариантами в прежнем формате
+// [ID вопроса, ID варианта...], единственный источник для документов без Responses
 type UserAnswerDetailsDocument struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty"`
-	TestingAnswerID primitive.ObjectID `bson:"testingAnswerId"`
-	Answers         [][]int            `bson:"answers"`
+	ID              primitive.ObjectID       `bson:"_id,omitempty"`
+	TestingAnswerID primitive.ObjectID       `bson:"testingAnswerId"`
+	Answers         [][]int                  `bson:"answers"`
+	Responses       []QuestionAnswerDocument `bson:"responses,omitempty"`
+}
+
+// RetakeDocument - MongoDB документ последнего прохождения теста пользователем
+// с ограничением повторного прохождения
+type RetakeDocument struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty"`
+	UserID primitive.ObjectID `bson:"userId"`
+	TestID primitive.ObjectID `bson:"testId"`
+	LastAt time.Time          `bson:"lastAt"`
+}
+
+// QuestionAnswerDocument - MongoDB документ ответа на вопрос
+type QuestionAnswerDocument struct {
+	QuestionID  int                  `bson:"questionId"`
+	OptionIDs   []int                `bson:"optionIds,omitempty"`
+	Value       *int                 `bson:"value,omitempty"`
+	Text        string               `bson:"text,omitempty"`
+	Cells       []MatrixCellDocument `bson:"cells,omitempty"`
+	TimeSpentMs int64                `bson:"timeSpentMs,omitempty"`
+}
+
+// MatrixCellDocument - MongoDB документ выбранного столбца в строке матрицы
+type MatrixCellDocument struct {
+	RowID    int `bson:"rowId"`
+	OptionID int `bson:"optionId"`
 }
@@ -1,6 +1,10 @@
 package model
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // UserDocument - MongoDB документ пользователя
 type UserDocument struct {
@@ -9,10 +13,37 @@ type UserDocument struct {
 	LastName      string             `bson:"lastName,omitempty"`
 	Email         string             `bson:"email"`
 	Status        string             `bson:"status"`
+	Role          string             `bson:"role,omitempty"`
 	Password      string             `bson:"password"`
 	PsychoType    string             `bson:"psychoType"`
 	Date          string             `bson:"date"`
 	IsGoogleAdded bool               `bson:"isGoogleAdded"`
 	IsYandexAdded bool               `bson:"isYandexAdded"`
-	Sessions      []interface{}      `bson:"sessions,omitempty"`
+	Sessions      []SessionDocument  `bson:"sessions,omitempty"`
+
+	ExternalAccounts  []ExternalAccountDocument      `bson:"externalAccounts,omitempty"`
+	PsychoTypeHistory []PsychoTypeAssignmentDocument `bson:"psychoTypeHistory,omitempty"`
+}
+
+// SessionDocument - MongoDB документ сессии пользователя
+type SessionDocument struct {
+	ID        string    `bson:"id"`
+	CreatedAt time.Time `bson:"createdAt"`
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+// PsychoTypeAssignmentDocument - MongoDB документ записи истории психотипа
+type PsychoTypeAssignmentDocument struct {
+	Value      string             `bson:"value"`
+	TestID     primitive.ObjectID `bson:"testId"`
+	AttemptID  primitive.ObjectID `bson:"attemptId"`
+	AssignedAt time.Time          `bson:"assignedAt"`
+}
+
+// ExternalAccountDocument - MongoDB документ привязанной учетной записи провайдера
+type ExternalAccountDocument struct {
+	Provider string    `bson:"provider"`
+	Subject  string    `bson:"subject"`
+	Email    string    `bson:"email"`
+	LinkedAt time.Time `bson:"linkedAt"`
 }
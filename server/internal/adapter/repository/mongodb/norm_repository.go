@@ -0,0 +1,297 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"server/internal/adapter/repository/mongodb/model"
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+const normTablesCollectionName = "NormTable"
+
+type NormRepository struct {
+	db *mongo.Database
+}
+
+func NewNormRepository(db *mongo.Database) *NormRepository {
+	return &NormRepository{db: db}
+}
+
+func (r *NormRepository) collection() *mongo.Collection {
+	return r.db.Collection(normTablesCollectionName)
+}
+
+// EnsureIndexes создает уникальный индекс версий закрепленных таблиц норм теста:
+// одновременное закрепление двух черновиков не получит одну версию
+func (r *NormRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "testId", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().
+			SetName("frozen_version").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": string(entity.NormStatusFrozen)}),
+	})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+	return nil
+}
+
+func (r *NormRepository) Insert(ctx context.Context, table entity.NormTable) (entity.NormTableID, error) {
+	doc, err := r.toDocument(table)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := r.collection().InsertOne(ctx, doc)
+	if err != nil {
+		return "", domainErrors.ErrDatabase
+	}
+
+	insertedID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", domainErrors.ErrDatabase
+	}
+	return entity.NormTableID(insertedID.Hex()), nil
+}
+
+func (r *NormRepository) FindByID(ctx context.Context, id entity.NormTableID) (entity.NormTable, error) {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return entity.NormTable{}, domainErrors.ErrInvalidID
+	}
+
+	var doc model.NormTableDocument
+	err = r.collection().FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entity.NormTable{}, domainErrors.ErrNotFound
+		}
+		return entity.NormTable{}, domainErrors.ErrDatabase
+	}
+
+	return r.toEntity(doc), nil
+}
+
+func (r *NormRepository) FindByTestID(ctx context.Context, testID entity.TestID) ([]entity.NormTable, error) {
+	objectID, err := primitive.ObjectIDFromHex(testID.String())
+	if err != nil {
+		return nil, domainErrors.ErrInvalidID
+	}
+
+	// Черновики имеют версию 0 и оказываются после закрепленных таблиц
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.collection().Find(ctx, bson.M{"testId": objectID}, opts)
+	if err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+	defer cursor.Close(ctx)
+
+	var docs []model.NormTableDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+
+	tables := make([]entity.NormTable, 0, len(docs))
+	for _, doc := range docs {
+		tables = append(tables, r.toEntity(doc))
+	}
+
+	return tables, nil
+}
+
+func (r *NormRepository) FindActive(ctx context.Context, testID entity.TestID, testVersion int) (entity.NormTable, error) {
+	objectID, err := primitive.ObjectIDFromHex(testID.String())
+	if err != nil {
+		return entity.NormTable{}, domainErrors.ErrInvalidID
+	}
+
+	var doc model.NormTableDocument
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	err = r.collection().FindOne(ctx, bson.M{
+		"testId":      objectID,
+		"testVersion": testVersion,
+		"status":      string(entity.NormStatusFrozen),
+	}, opts).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entity.NormTable{}, domainErrors.ErrNotFound
+		}
+		return entity.NormTable{}, domainErrors.ErrDatabase
+	}
+
+	return r.toEntity(doc), nil
+}
+
+func (r *NormRepository) Freeze(ctx context.Context, id entity.NormTableID, frozenAt time.Time) (entity.NormTable, error) {
+	table, err := r.FindByID(ctx, id)
+	if err != nil {
+		return entity.NormTable{}, err
+	}
+	if table.IsFrozen() {
+		return entity.NormTable{}, domainErrors.ErrNormFrozen
+	}
+
+	objectID, _ := primitive.ObjectIDFromHex(id.String())
+	testID, _ := primitive.ObjectIDFromHex(table.TestID.String())
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	// Версию могла занять таблица, закрепленная одновременно: уникальный индекс
+	// отклоняет повтор, и версия выбирается заново
+	for attempt := 0; attempt < maxFreezeAttempts; attempt++ {
+		version, err := r.latestVersion(ctx, testID)
+		if err != nil {
+			return entity.NormTable{}, err
+		}
+
+		// Условие на статус не дает закрепить черновик дважды при одновременных запросах
+		var doc model.NormTableDocument
+		err = r.collection().FindOneAndUpdate(ctx,
+			bson.M{"_id": objectID, "status": string(entity.NormStatusDraft)},
+			bson.M{"$set": bson.M{
+				"status":   string(entity.NormStatusFrozen),
+				"version":  version + 1,
+				"frozenAt": frozenAt,
+			}},
+			opts,
+		).Decode(&doc)
+		if err == nil {
+			return r.toEntity(doc), nil
+		}
+		if err == mongo.ErrNoDocuments {
+			return entity.NormTable{}, domainErrors.ErrNormFrozen
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return entity.NormTable{}, domainErrors.ErrDatabase
+		}
+	}
+	return entity.NormTable{}, domainErrors.ErrDatabase
+}
+
+// maxFreezeAttempts ограничивает число попыток выбрать свободную версию норм
+const maxFreezeAttempts = 3
+
+// latestVersion возвращает наибольшую версию закрепленных таблиц норм теста; 0 - таблиц нет
+func (r *NormRepository) latestVersion(ctx context.Context, testID primitive.ObjectID) (int, error) {
+	var doc model.NormTableDocument
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"version": 1})
+	err := r.collection().FindOne(ctx, bson.M{
+		"testId": testID,
+		"status": string(entity.NormStatusFrozen),
+	}, opts).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		return 0, domainErrors.ErrDatabase
+	}
+	return doc.Version, nil
+}
+
+// Конвертеры
+
+func (r *NormRepository) toEntity(doc model.NormTableDocument) entity.NormTable {
+	table := entity.NormTable{
+		ID:          entity.NormTableID(doc.ID.Hex()),
+		TestID:      entity.TestID(doc.TestID.Hex()),
+		TestVersion: doc.TestVersion,
+		Version:     doc.Version,
+		Name:        doc.Name,
+		Filter: entity.NormFilter{
+			From:    doc.Filter.From,
+			To:      doc.Filter.To,
+			Cohort:  doc.Filter.Cohort,
+			UserIDs: make([]entity.UserID, 0, len(doc.Filter.UserIDs)),
+		},
+		Status:    entity.NormStatus(doc.Status),
+		Sample:    doc.Sample,
+		Scales:    make([]entity.ScaleNorm, 0, len(doc.Scales)),
+		CreatedBy: entity.UserID(doc.CreatedBy.Hex()),
+		CreatedAt: doc.CreatedAt,
+		FrozenAt:  doc.FrozenAt,
+	}
+
+	for _, userID := range doc.Filter.UserIDs {
+		table.Filter.UserIDs = append(table.Filter.UserIDs, entity.UserID(userID.Hex()))
+	}
+
+	for _, scale := range doc.Scales {
+		points := make([]entity.NormPoint, 0, len(scale.Points))
+		for _, point := range scale.Points {
+			points = append(points, entity.NormPoint{Score: point.Score, Count: point.Count})
+		}
+		table.Scales = append(table.Scales, entity.ScaleNorm{
+			ScaleID: entity.ScaleID(scale.ScaleID),
+			Name:    scale.Name,
+			Sample:  scale.Sample,
+			Mean:    scale.Mean,
+			StdDev:  scale.StdDev,
+			Points:  points,
+		})
+	}
+
+	return table
+}
+
+func (r *NormRepository) toDocument(table entity.NormTable) (model.NormTableDocument, error) {
+	testID, err := primitive.ObjectIDFromHex(table.TestID.String())
+	if err != nil {
+		return model.NormTableDocument{}, domainErrors.ErrInvalidID
+	}
+	createdBy, err := primitive.ObjectIDFromHex(table.CreatedBy.String())
+	if err != nil {
+		return model.NormTableDocument{}, domainErrors.ErrInvalidID
+	}
+
+	doc := model.NormTableDocument{
+		TestID:      testID,
+		TestVersion: table.TestVersion,
+		Version:     table.Version,
+		Name:        table.Name,
+		Filter: model.NormFilterDocument{
+			From:   table.Filter.From,
+			To:     table.Filter.To,
+			Cohort: table.Filter.Cohort,
+		},
+		Status:    string(table.Status),
+		Sample:    table.Sample,
+		Scales:    make([]model.ScaleNormDocument, 0, len(table.Scales)),
+		CreatedBy: createdBy,
+		CreatedAt: table.CreatedAt,
+		FrozenAt:  table.FrozenAt,
+	}
+
+	for _, userID := range table.Filter.UserIDs {
+		objectID, err := primitive.ObjectIDFromHex(userID.String())
+		if err != nil {
+			return model.NormTableDocument{}, domainErrors.ErrInvalidID
+		}
+		doc.Filter.UserIDs = append(doc.Filter.UserIDs, objectID)
+	}
+
+	for _, scale := range table.Scales {
+		points := make([]model.NormPointDocument, 0, len(scale.Points))
+		for _, point := range scale.Points {
+			points = append(points, model.NormPointDocument{Score: point.Score, Count: point.Count})
+		}
+		doc.Scales = append(doc.Scales, model.ScaleNormDocument{
+			ScaleID: string(scale.ScaleID),
+			Name:    scale.Name,
+			Sample:  scale.Sample,
+			Mean:    scale.Mean,
+			StdDev:  scale.StdDev,
+			Points:  points,
+		})
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,126 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"server/internal/adapter/repository/mongodb/model"
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+const passwordResetCollectionName = "PasswordReset"
+
+type PasswordResetRepository struct {
+	db *mongo.Database
+}
+
+func NewPasswordResetRepository(db *mongo.Database) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+func (r *PasswordResetRepository) collection() *mongo.Collection {
+	return r.db.Collection(passwordResetCollectionName)
+}
+
+// EnsureIndexes создает TTL-индекс, по которому MongoDB удаляет истекшие запросы
+// на сброс пароля. Удаление запаздывает, поэтому срок действия токена
+// по-прежнему проверяется при его использовании
+func (r *PasswordResetRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetName("expires").SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+	return nil
+}
+
+func (r *PasswordResetRepository) Insert(ctx context.Context, reset entity.PasswordReset) error {
+	userID, err := primitive.ObjectIDFromHex(reset.UserID.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	doc := model.PasswordResetDocument{
+		UserID:    userID,
+		TokenHash: reset.TokenHash,
+		CreatedAt: reset.CreatedAt,
+		ExpiresAt: reset.ExpiresAt,
+		UsedAt:    reset.UsedAt,
+	}
+
+	if _, err := r.collection().InsertOne(ctx, doc); err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	return nil
+}
+
+func (r *PasswordResetRepository) FindByTokenHash(ctx context.Context, tokenHash string) (entity.PasswordReset, error) {
+	var doc model.PasswordResetDocument
+	err := r.collection().FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entity.PasswordReset{}, domainErrors.ErrInvalidToken
+		}
+		return entity.PasswordReset{}, domainErrors.ErrDatabase
+	}
+
+	return r.toEntity(doc), nil
+}
+
+func (r *PasswordResetRepository) MarkUsed(ctx context.Context, id entity.PasswordResetID, usedAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	// Условие на usedAt гарантирует, что токен сработает только один раз
+	result, err := r.collection().UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "usedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"usedAt": usedAt}},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.ModifiedCount == 0 {
+		return domainErrors.ErrInvalidToken
+	}
+
+	return nil
+}
+
+func (r *PasswordResetRepository) DeleteByUser(ctx context.Context, userID entity.UserID) error {
+	objectID, err := primitive.ObjectIDFromHex(userID.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	if _, err := r.collection().DeleteMany(ctx, bson.M{"userId": objectID}); err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	return nil
+}
+
+// Конвертеры
+
+func (r *PasswordResetRepository) toEntity(doc model.PasswordResetDocument) entity.PasswordReset {
+	return entity.PasswordReset{
+		ID:        entity.PasswordResetID(doc.ID.Hex()),
+		UserID:    entity.UserID(doc.UserID.Hex()),
+		TokenHash: doc.TokenHash,
+		CreatedAt: doc.CreatedAt,
+		ExpiresAt: doc.ExpiresAt,
+		UsedAt:    doc.UsedAt,
+	}
+}
@@ -2,10 +2,12 @@ package mongodb
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"server/internal/adapter/repository/mongodb/model"
 	"server/internal/domain/entity"
@@ -53,6 +55,231 @@ func (r *TestRepository) FindByStatus(ctx context.Context, status entity.TestSta
 	return tests, nil
 }
 
+// EnsureIndexes создает индексы тестов: текстовый индекс для поиска по названию,
+// авторам и описанию, индекс по меткам, индекс по соавторам и уникальный индекс
+// версий вопросов. Документы вопросов без версии созданы до появления версий
+// и в уникальный индекс не входят
+func (r *TestRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.testsCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "testName", Value: "text"},
+				{Key: "authorsName", Value: "text"},
+				{Key: "description", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("catalog_text").
+				SetWeights(bson.M{"testName": 10, "authorsName": 5, "description": 1}).
+				SetDefaultLanguage("russian"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "tags", Value: 1}},
+			Options: options.Index().SetName("catalog_tags"),
+		},
+		{
+			Keys:    bson.D{{Key: "collaborators.userId", Value: 1}},
+			Options: options.Index().SetName("collaborators"),
+		},
+	})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	_, err = r.questionsCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "testingId", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().
+			SetName("questions_version").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"version": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+	return nil
+}
+
+func (r *TestRepository) Search(ctx context.Context, search entity.TestSearch) (entity.TestSearchPage, error) {
+	// Тест без начала или окончания показа не попадает под условия $gt и $lte
+	match := bson.M{
+		"status":      string(entity.TestStatusPublished),
+		"publishAt":   bson.M{"$not": bson.M{"$gt": search.Now}},
+		"unpublishAt": bson.M{"$not": bson.M{"$lte": search.Now}},
+	}
+	if search.Query != "" {
+		match["$text"] = bson.M{"$search": search.Query}
+	}
+	if search.Category != "" {
+		match["category"] = search.Category
+	}
+	if len(search.Tags) > 0 {
+		match["tags"] = bson.M{"$all": search.Tags}
+	}
+
+	idFilter := bson.M{}
+	if search.IncludeIDs != nil {
+		idFilter["$in"] = objectIDs(search.IncludeIDs)
+	}
+	if len(search.ExcludeIDs) > 0 {
+		idFilter["$nin"] = objectIDs(search.ExcludeIDs)
+	}
+	if len(idFilter) > 0 {
+		match["_id"] = idFilter
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: match}}}
+	if search.Query != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{
+			"score": bson.M{"$meta": "textScore"},
+		}}})
+	}
+
+	after, err := searchCursorFilter(search)
+	if err != nil {
+		return entity.TestSearchPage{}, err
+	}
+	if after != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: after}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: searchSort(search.Sort)}},
+		bson.D{{Key: "$limit", Value: search.Limit + 1}},
+	)
+
+	cursor, err := r.testsCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return entity.TestSearchPage{}, domainErrors.ErrDatabase
+	}
+	defer cursor.Close(ctx)
+
+	var docs []model.TestSearchDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return entity.TestSearchPage{}, domainErrors.ErrDatabase
+	}
+
+	page := entity.TestSearchPage{Tests: make([]entity.Test, 0, len(docs))}
+	if len(docs) > search.Limit {
+		docs = docs[:search.Limit]
+		last := docs[len(docs)-1]
+		page.Next = &entity.TestCursor{
+			ID:    entity.TestID(last.ID.Hex()),
+			Name:  last.TestName,
+			Score: last.Score,
+		}
+	}
+	for _, doc := range docs {
+		page.Tests = append(page.Tests, r.toEntity(doc.TestDocument))
+	}
+	return page, nil
+}
+
+// searchSort - порядок сортировки результатов поиска; при равенстве ключей
+// тесты упорядочиваются по ID, чтобы выдача по курсору была устойчивой
+func searchSort(sort entity.TestSort) bson.D {
+	switch sort {
+	case entity.TestSortRelevance:
+		return bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}
+	case entity.TestSortName:
+		return bson.D{{Key: "testName", Value: 1}, {Key: "_id", Value: 1}}
+	default:
+		return bson.D{{Key: "_id", Value: -1}}
+	}
+}
+
+// searchCursorFilter - условие на тесты, следующие в порядке сортировки за курсором
+func searchCursorFilter(search entity.TestSearch) (bson.M, error) {
+	if search.After == nil {
+		return nil, nil
+	}
+	afterID, err := primitive.ObjectIDFromHex(search.After.ID.String())
+	if err != nil {
+		return nil, domainErrors.ErrInvalidID
+	}
+
+	switch search.Sort {
+	case entity.TestSortRelevance:
+		return bson.M{"$or": bson.A{
+			bson.M{"score": bson.M{"$lt": search.After.Score}},
+			bson.M{"score": search.After.Score, "_id": bson.M{"$gt": afterID}},
+		}}, nil
+	case entity.TestSortName:
+		return bson.M{"$or": bson.A{
+			bson.M{"testName": bson.M{"$gt": search.After.Name}},
+			bson.M{"testName": search.After.Name, "_id": bson.M{"$gt": afterID}},
+		}}, nil
+	default:
+		return bson.M{"_id": bson.M{"$lt": afterID}}, nil
+	}
+}
+
+// objectIDs переводит ID тестов в ObjectID, пропуская некорректные
+func objectIDs(ids []entity.TestID) []primitive.ObjectID {
+	result := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id.String()); err == nil {
+			result = append(result, objectID)
+		}
+	}
+	return result
+}
+
+func (r *TestRepository) FindByUserID(ctx context.Context, userID entity.UserID) ([]entity.Test, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID.String())
+	if err != nil {
+		return nil, domainErrors.ErrInvalidID
+	}
+
+	filter := bson.M{
+		"userId": objectID,
+		"status": bson.M{"$ne": string(entity.TestStatusDeleted)},
+	}
+	cursor, err := r.testsCollection().Find(ctx, filter)
+	if err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+	defer cursor.Close(ctx)
+
+	var docs []model.TestDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+
+	tests := make([]entity.Test, 0, len(docs))
+	for _, doc := range docs {
+		tests = append(tests, r.toEntity(doc))
+	}
+
+	return tests, nil
+}
+
+func (r *TestRepository) FindByCollaborator(ctx context.Context, userID entity.UserID) ([]entity.Test, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID.String())
+	if err != nil {
+		return nil, domainErrors.ErrInvalidID
+	}
+
+	filter := bson.M{
+		"collaborators.userId": objectID,
+		"status":               bson.M{"$ne": string(entity.TestStatusDeleted)},
+	}
+	cursor, err := r.testsCollection().Find(ctx, filter)
+	if err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+	defer cursor.Close(ctx)
+
+	var docs []model.TestDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+
+	tests := make([]entity.Test, 0, len(docs))
+	for _, doc := range docs {
+		tests = append(tests, r.toEntity(doc))
+	}
+
+	return tests, nil
+}
+
 func (r *TestRepository) FindByID(ctx context.Context, id entity.TestID) (entity.Test, error) {
 	objectID, err := primitive.ObjectIDFromHex(id.String())
 	if err != nil {
@@ -71,14 +298,59 @@ func (r *TestRepository) FindByID(ctx context.Context, id entity.TestID) (entity
 	return r.toEntity(doc), nil
 }
 
+// FindQuestionsByTestID возвращает версию вопросов, на которую указывает тест.
+// Версия, сохраненная изменением, которое не успело обновить тест, не читается
 func (r *TestRepository) FindQuestionsByTestID(ctx context.Context, testID entity.TestID) (entity.QuestionsDocument, error) {
 	objectID, err := primitive.ObjectIDFromHex(testID.String())
 	if err != nil {
 		return entity.QuestionsDocument{}, domainErrors.ErrInvalidID
 	}
 
+	var test model.TestDocument
+	opts := options.FindOne().SetProjection(bson.M{"version": 1})
+	err = r.testsCollection().FindOne(ctx, bson.M{"_id": objectID}, opts).Decode(&test)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entity.QuestionsDocument{}, domainErrors.ErrNotFound
+		}
+		return entity.QuestionsDocument{}, domainErrors.ErrDatabase
+	}
+
+	return r.FindQuestionsVersion(ctx, testID, versionOrFirst(test.Version))
+}
+
+// LastQuestionsVersion возвращает наибольший номер сохраненной версии вопросов,
+// включая версии, на которые тест так и не переключился
+func (r *TestRepository) LastQuestionsVersion(ctx context.Context, testID entity.TestID) (int, error) {
+	objectID, err := primitive.ObjectIDFromHex(testID.String())
+	if err != nil {
+		return 0, domainErrors.ErrInvalidID
+	}
+
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"version": 1})
+
+	var doc model.QuestionsDocument
+	err = r.questionsCollection().FindOne(ctx, bson.M{"testingId": objectID}, opts).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, domainErrors.ErrNotFound
+		}
+		return 0, domainErrors.ErrDatabase
+	}
+
+	return versionOrFirst(doc.Version), nil
+}
+
+func (r *TestRepository) FindQuestionsVersion(ctx context.Context, testID entity.TestID, version int) (entity.QuestionsDocument, error) {
+	objectID, err := primitive.ObjectIDFromHex(testID.String())
+	if err != nil {
+		return entity.QuestionsDocument{}, domainErrors.ErrInvalidID
+	}
+
 	var doc model.QuestionsDocument
-	err = r.questionsCollection().FindOne(ctx, bson.M{"testingId": objectID}).Decode(&doc)
+	err = r.questionsCollection().FindOne(ctx, questionsVersionFilter(objectID, version)).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return entity.QuestionsDocument{}, domainErrors.ErrNotFound
@@ -104,6 +376,9 @@ func (r *TestRepository) InsertQuestions(ctx context.Context, doc entity.Questio
 	mongoDoc := r.questionsDocToDocument(doc)
 	_, err := r.questionsCollection().InsertOne(ctx, mongoDoc)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domainErrors.ErrVersionExists
+		}
 		return domainErrors.ErrDatabase
 	}
 	return nil
@@ -129,22 +404,37 @@ func (r *TestRepository) UpdateStatus(ctx context.Context, id entity.TestID, sta
 	return nil
 }
 
-func (r *TestRepository) UpdateTest(ctx context.Context, test entity.Test) error {
+func (r *TestRepository) UpdateLifecycle(ctx context.Context, test entity.Test, from entity.TestStatus) error {
 	objectID, err := primitive.ObjectIDFromHex(test.ID.String())
 	if err != nil {
 		return domainErrors.ErrInvalidID
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"testName":      test.TestName,
-			"authorsName":   test.AuthorsName,
-			"questionCount": test.QuestionCount,
-			"description":   test.Description,
-		},
+	set := bson.M{"status": string(test.Status)}
+	unset := bson.M{}
+	if test.PublishAt != nil {
+		set["publishAt"] = test.PublishAt
+	} else {
+		unset["publishAt"] = ""
+	}
+	if test.UnpublishAt != nil {
+		set["unpublishAt"] = test.UnpublishAt
+	} else {
+		unset["unpublishAt"] = ""
+	}
+	if test.ReviewComment != "" {
+		set["reviewComment"] = test.ReviewComment
+	} else {
+		unset["reviewComment"] = ""
 	}
 
-	result, err := r.testsCollection().UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	// Фильтр по исходному статусу защищает от одновременных переходов
+	result, err := r.testsCollection().UpdateOne(ctx, bson.M{"_id": objectID, "status": string(from)}, update)
 	if err != nil {
 		return domainErrors.ErrDatabase
 	}
@@ -154,27 +444,111 @@ func (r *TestRepository) UpdateTest(ctx context.Context, test entity.Test) error
 	return nil
 }
 
-func (r *TestRepository) UpsertQuestions(ctx context.Context, doc entity.QuestionsDocument) error {
-	testingID, err := primitive.ObjectIDFromHex(doc.TestingID.String())
+// UpdateAccess сохраняет владельца и соавторов теста. Фильтр по прежнему владельцу
+// защищает от одновременной передачи владения
+func (r *TestRepository) UpdateAccess(ctx context.Context, test entity.Test, owner entity.UserID) error {
+	objectID, err := primitive.ObjectIDFromHex(test.ID.String())
 	if err != nil {
 		return domainErrors.ErrInvalidID
 	}
+	ownerID, err := ownerFilter(owner)
+	if err != nil {
+		return err
+	}
 
-	mongoDoc := r.questionsDocToDocument(doc)
-	_, err = r.questionsCollection().UpdateOne(
-		ctx,
-		bson.M{"testingId": testingID},
-		bson.M{"$set": bson.M{
-			"questions":    mongoDoc.Questions,
-			"resultsLogic": mongoDoc.ResultsLogic,
-		}},
-	)
+	set := bson.M{"collaborators": collaboratorsToDocument(test.Collaborators)}
+	if !isOwnerless(test.UserID) {
+		newOwnerID, err := primitive.ObjectIDFromHex(test.UserID.String())
+		if err != nil {
+			return domainErrors.ErrInvalidID
+		}
+		set["userId"] = newOwnerID
+	}
+
+	result, err := r.testsCollection().UpdateOne(ctx, bson.M{"_id": objectID, "userId": ownerID}, bson.M{"$set": set})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrNotFound
+	}
+	return nil
+}
+
+// isOwnerless проверяет, что у теста нет владельца. Тесты, созданные до появления
+// владельцев, хранятся без userId, и при чтении их владелец - нулевой ObjectID
+func isOwnerless(owner entity.UserID) bool {
+	return owner.IsEmpty() || owner.String() == primitive.NilObjectID.Hex()
+}
+
+// ownerFilter возвращает условие на владельца теста. Тест без владельца
+// соответствует отсутствующему, пустому или нулевому userId
+func ownerFilter(owner entity.UserID) (interface{}, error) {
+	if isOwnerless(owner) {
+		return bson.M{"$in": bson.A{nil, primitive.NilObjectID}}, nil
+	}
+	ownerID, err := primitive.ObjectIDFromHex(owner.String())
+	if err != nil {
+		return nil, domainErrors.ErrInvalidID
+	}
+	return ownerID, nil
+}
+
+// UpdateTest сохраняет данные теста и переключает его на версию test.Version.
+// Фильтр по статусу и прежней версии защищает от параллельного изменения теста
+// и от его одновременной отправки на проверку
+func (r *TestRepository) UpdateTest(ctx context.Context, test entity.Test, previousVersion int) error {
+	objectID, err := primitive.ObjectIDFromHex(test.ID.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"testName":           test.TestName,
+			"authorsName":        test.AuthorsName,
+			"questionCount":      test.QuestionCount,
+			"description":        test.Description,
+			"category":           test.Category,
+			"tags":               test.Tags,
+			"isTyping":           test.IsTyping,
+			"retakeMode":         string(test.Retake.Mode),
+			"retakeCooldownDays": test.Retake.CooldownDays,
+			"version":            test.Version,
+		},
+	}
+
+	filter := bson.M{"_id": objectID, "status": string(test.Status), "version": previousVersion}
+	if previousVersion == entity.FirstTestVersion {
+		// Тест без счетчика создан до появления версий и находится в первой версии
+		filter["version"] = bson.M{"$in": bson.A{previousVersion, nil}}
+	}
+
+	result, err := r.testsCollection().UpdateOne(ctx, filter, update)
 	if err != nil {
 		return domainErrors.ErrDatabase
 	}
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrNotFound
+	}
 	return nil
 }
 
+// questionsVersionFilter - фильтр документа вопросов по версии. Первой версии
+// соответствуют и документы, созданные до появления версий
+func questionsVersionFilter(testingID primitive.ObjectID, version int) bson.M {
+	if version == entity.FirstTestVersion {
+		return bson.M{
+			"testingId": testingID,
+			"$or": bson.A{
+				bson.M{"version": version},
+				bson.M{"version": bson.M{"$exists": false}},
+			},
+		}
+	}
+	return bson.M{"testingId": testingID, "version": version}
+}
+
 // Конвертеры
 
 func (r *TestRepository) toEntity(doc model.TestDocument) entity.Test {
@@ -184,20 +558,44 @@ func (r *TestRepository) toEntity(doc model.TestDocument) entity.Test {
 		AuthorsName:   doc.AuthorsName,
 		QuestionCount: doc.QuestionCount,
 		Description:   doc.Description,
+		Category:      doc.Category,
+		Tags:          doc.Tags,
 		Date:          doc.Date,
 		Status:        entity.TestStatus(doc.Status),
 		UserID:        entity.UserID(doc.UserID.Hex()),
+		IsTyping:      doc.IsTyping,
+		Version:       versionOrFirst(doc.Version),
+		PublishAt:     doc.PublishAt,
+		UnpublishAt:   doc.UnpublishAt,
+		ReviewComment: doc.ReviewComment,
+		Retake: entity.RetakePolicy{
+			Mode:         entity.RetakeMode(doc.RetakeMode),
+			CooldownDays: doc.RetakeCooldownDays,
+		},
+		Collaborators: collaboratorsToEntity(doc.Collaborators),
+		ClonedFrom:    originToEntity(doc.ClonedFrom),
 	}
 }
 
 func (r *TestRepository) toDocument(test entity.Test) model.TestDocument {
 	doc := model.TestDocument{
-		TestName:      test.TestName,
-		AuthorsName:   test.AuthorsName,
-		QuestionCount: test.QuestionCount,
-		Description:   test.Description,
-		Date:          test.Date,
-		Status:        string(test.Status),
+		TestName:           test.TestName,
+		AuthorsName:        test.AuthorsName,
+		QuestionCount:      test.QuestionCount,
+		Description:        test.Description,
+		Category:           test.Category,
+		Tags:               test.Tags,
+		Date:               test.Date,
+		Status:             string(test.Status),
+		IsTyping:           test.IsTyping,
+		Version:            test.Version,
+		PublishAt:          test.PublishAt,
+		UnpublishAt:        test.UnpublishAt,
+		ReviewComment:      test.ReviewComment,
+		RetakeMode:         string(test.Retake.Mode),
+		RetakeCooldownDays: test.Retake.CooldownDays,
+		Collaborators:      collaboratorsToDocument(test.Collaborators),
+		ClonedFrom:         originToDocument(test.ClonedFrom),
 	}
 
 	if !test.ID.IsEmpty() {
@@ -215,60 +613,338 @@ func (r *TestRepository) toDocument(test entity.Test) model.TestDocument {
 	return doc
 }
 
+func collaboratorsToEntity(docs []model.CollaboratorDocument) []entity.Collaborator {
+	if len(docs) == 0 {
+		return nil
+	}
+	collaborators := make([]entity.Collaborator, 0, len(docs))
+	for _, doc := range docs {
+		collaborators = append(collaborators, entity.Collaborator{
+			UserID:  entity.UserID(doc.UserID.Hex()),
+			Role:    entity.CollaboratorRole(doc.Role),
+			AddedAt: doc.AddedAt,
+		})
+	}
+	return collaborators
+}
+
+func collaboratorsToDocument(collaborators []entity.Collaborator) []model.CollaboratorDocument {
+	docs := make([]model.CollaboratorDocument, 0, len(collaborators))
+	for _, collaborator := range collaborators {
+		userID, err := primitive.ObjectIDFromHex(collaborator.UserID.String())
+		if err != nil {
+			continue
+		}
+		docs = append(docs, model.CollaboratorDocument{
+			UserID:  userID,
+			Role:    string(collaborator.Role),
+			AddedAt: collaborator.AddedAt,
+		})
+	}
+	return docs
+}
+
+func originToEntity(doc *model.TestOriginDocument) *entity.TestOrigin {
+	if doc == nil {
+		return nil
+	}
+	return &entity.TestOrigin{TestID: entity.TestID(doc.TestID.Hex()), Version: doc.Version}
+}
+
+func originToDocument(origin *entity.TestOrigin) *model.TestOriginDocument {
+	if origin == nil {
+		return nil
+	}
+	testID, err := primitive.ObjectIDFromHex(origin.TestID.String())
+	if err != nil {
+		return nil
+	}
+	return &model.TestOriginDocument{TestID: testID, Version: origin.Version}
+}
+
 func (r *TestRepository) questionsDocToEntity(doc model.QuestionsDocument) entity.QuestionsDocument {
-	questions := make([]entity.Question, 0, len(doc.Questions))
-	for _, q := range doc.Questions {
+	questions := questionsToEntity(doc.Questions)
+
+	return entity.QuestionsDocument{
+		ID:           entity.TestID(doc.ID.Hex()),
+		Questions:    questions,
+		ResultsLogic: resultsLogicToEntity(doc.ResultsLogic),
+		TestingID:    entity.TestID(doc.TestingID.Hex()),
+		Version:      versionOrFirst(doc.Version),
+		CreatedAt:    doc.CreatedAt,
+		TimeLimits: entity.TimeLimits{
+			Total:       time.Duration(doc.TimeLimitSec) * time.Second,
+			PerQuestion: time.Duration(doc.QuestionTimeLimitSec) * time.Second,
+		},
+		Shuffle: entity.ShuffleSettings{
+			Questions: doc.ShuffleQuestions,
+			Options:   doc.ShuffleOptions,
+		},
+	}
+}
+
+// versionOrFirst возвращает номер версии; отсутствующая версия считается первой
+func versionOrFirst(version int) int {
+	if version <= 0 {
+		return entity.FirstTestVersion
+	}
+	return version
+}
+
+func (r *TestRepository) questionsDocToDocument(doc entity.QuestionsDocument) model.QuestionsDocument {
+	questions := questionsToDocument(doc.Questions)
+
+	result := model.QuestionsDocument{
+		Questions:            questions,
+		ResultsLogic:         resultsLogicToDocument(doc.ResultsLogic),
+		Version:              doc.Version,
+		CreatedAt:            doc.CreatedAt,
+		TimeLimitSec:         int(doc.TimeLimits.Total / time.Second),
+		QuestionTimeLimitSec: int(doc.TimeLimits.PerQuestion / time.Second),
+		ShuffleQuestions:     doc.Shuffle.Questions,
+		ShuffleOptions:       doc.Shuffle.Options,
+	}
+
+	if !doc.TestingID.IsEmpty() {
+		if objID, err := primitive.ObjectIDFromHex(doc.TestingID.String()); err == nil {
+			result.TestingID = objID
+		}
+	}
+
+	return result
+}
+
+func questionsToEntity(docs []model.QuestionDocument) []entity.Question {
+	questions := make([]entity.Question, 0, len(docs))
+	for _, q := range docs {
 		options := make([]entity.AnswerOption, 0, len(q.AnswerOptions))
 		for _, opt := range q.AnswerOptions {
 			options = append(options, entity.AnswerOption{
-				ID:   opt.ID,
-				Body: opt.Body,
+				ID:      opt.ID,
+				Body:    opt.Body,
+				Weights: weightsToEntity(opt.Weights),
 			})
 		}
+
+		var likert *entity.LikertScale
+		if q.Likert != nil {
+			likert = &entity.LikertScale{
+				Min:      q.Likert.Min,
+				Max:      q.Likert.Max,
+				MinLabel: q.Likert.MinLabel,
+				MaxLabel: q.Likert.MaxLabel,
+			}
+		}
+
+		rows := make([]entity.MatrixRow, 0, len(q.Rows))
+		for _, row := range q.Rows {
+			rows = append(rows, entity.MatrixRow{ID: row.ID, Body: row.Body})
+		}
+
+		var branches []entity.BranchRule
+		for _, rule := range q.Branches {
+			branches = append(branches, entity.BranchRule{
+				OptionIDs: rule.OptionIDs,
+				GoTo:      rule.GoTo,
+				Finish:    rule.Finish,
+			})
+		}
+
 		questions = append(questions, entity.Question{
 			ID:            q.ID,
 			QuestionBody:  q.QuestionBody,
 			AnswerOptions: options,
-			SelectType:    q.SelectType,
+			SelectType:    entity.ParseQuestionType(q.SelectType),
+			Likert:        likert,
+			Rows:          rows,
+			MaxLength:     q.MaxLength,
+			Weights:       weightsToEntity(q.Weights),
+			Branches:      branches,
 		})
 	}
-
-	return entity.QuestionsDocument{
-		ID:           entity.TestID(doc.ID.Hex()),
-		Questions:    questions,
-		ResultsLogic: doc.ResultsLogic,
-		TestingID:    entity.TestID(doc.TestingID.Hex()),
-	}
+	return questions
 }
 
-func (r *TestRepository) questionsDocToDocument(doc entity.QuestionsDocument) model.QuestionsDocument {
-	questions := make([]model.QuestionDocument, 0, len(doc.Questions))
-	for _, q := range doc.Questions {
+func questionsToDocument(questions []entity.Question) []model.QuestionDocument {
+	docs := make([]model.QuestionDocument, 0, len(questions))
+	for _, q := range questions {
 		options := make([]model.AnswerOptionDocument, 0, len(q.AnswerOptions))
 		for _, opt := range q.AnswerOptions {
 			options = append(options, model.AnswerOptionDocument{
-				ID:   opt.ID,
-				Body: opt.Body,
+				ID:      opt.ID,
+				Body:    opt.Body,
+				Weights: weightsToDocument(opt.Weights),
 			})
 		}
-		questions = append(questions, model.QuestionDocument{
+
+		var likert *model.LikertScaleDocument
+		if q.Likert != nil {
+			likert = &model.LikertScaleDocument{
+				Min:      q.Likert.Min,
+				Max:      q.Likert.Max,
+				MinLabel: q.Likert.MinLabel,
+				MaxLabel: q.Likert.MaxLabel,
+			}
+		}
+
+		var rows []model.MatrixRowDocument
+		for _, row := range q.Rows {
+			rows = append(rows, model.MatrixRowDocument{ID: row.ID, Body: row.Body})
+		}
+
+		var branches []model.BranchRuleDocument
+		for _, rule := range q.Branches {
+			branches = append(branches, model.BranchRuleDocument{
+				OptionIDs: rule.OptionIDs,
+				GoTo:      rule.GoTo,
+				Finish:    rule.Finish,
+			})
+		}
+
+		docs = append(docs, model.QuestionDocument{
 			ID:            q.ID,
 			QuestionBody:  q.QuestionBody,
 			AnswerOptions: options,
-			SelectType:    q.SelectType,
+			SelectType:    string(q.SelectType),
+			Likert:        likert,
+			Rows:          rows,
+			MaxLength:     q.MaxLength,
+			Weights:       weightsToDocument(q.Weights),
+			Branches:      branches,
 		})
 	}
+	return docs
+}
 
-	result := model.QuestionsDocument{
-		Questions:    questions,
-		ResultsLogic: doc.ResultsLogic,
+func weightsToEntity(weights map[string]float64) map[entity.ScaleID]float64 {
+	if len(weights) == 0 {
+		return nil
 	}
+	result := make(map[entity.ScaleID]float64, len(weights))
+	for scaleID, weight := range weights {
+		result[entity.ScaleID(scaleID)] = weight
+	}
+	return result
+}
 
-	if !doc.TestingID.IsEmpty() {
-		if objID, err := primitive.ObjectIDFromHex(doc.TestingID.String()); err == nil {
-			result.TestingID = objID
+func weightsToDocument(weights map[entity.ScaleID]float64) map[string]float64 {
+	if len(weights) == 0 {
+		return nil
+	}
+	result := make(map[string]float64, len(weights))
+	for scaleID, weight := range weights {
+		result[string(scaleID)] = weight
+	}
+	return result
+}
+
+// resultsLogicToEntity разбирает правила подсчета; устаревшее строковое значение дает пустые правила
+func resultsLogicToEntity(raw bson.RawValue) entity.ResultsLogic {
+	logic := entity.ResultsLogic{Scales: []entity.Scale{}, Rules: []entity.ResultRule{}}
+	if raw.Type != bson.TypeEmbeddedDocument {
+		return logic
+	}
+
+	var doc model.ResultsLogicDocument
+	if err := raw.Unmarshal(&doc); err != nil {
+		return logic
+	}
+
+	for _, scale := range doc.Scales {
+		bands := make([]entity.ScoreBand, 0, len(scale.Bands))
+		for _, band := range scale.Bands {
+			bands = append(bands, entity.ScoreBand{
+				Min:   band.Min,
+				Max:   band.Max,
+				Label: band.Label,
+				Text:  band.Text,
+			})
 		}
+
+		logic.Scales = append(logic.Scales, entity.Scale{
+			ID:          entity.ScaleID(scale.ID),
+			Name:        scale.Name,
+			Description: scale.Description,
+			Bands:       bands,
+		})
 	}
 
-	return result
+	for _, rule := range doc.Rules {
+		conditions := make([]entity.RuleCondition, 0, len(rule.Conditions))
+		for _, condition := range rule.Conditions {
+			conditions = append(conditions, entity.RuleCondition{
+				ScaleID:  entity.ScaleID(condition.ScaleID),
+				Band:     condition.Band,
+				Min:      condition.Min,
+				Max:      condition.Max,
+				Above:    entity.ScaleID(condition.Above),
+				Dominant: condition.Dominant,
+			})
+		}
+
+		logic.Rules = append(logic.Rules, entity.ResultRule{
+			ID:         rule.ID,
+			Conditions: conditions,
+			Text:       rule.Text,
+			Priority:   rule.Priority,
+		})
+	}
+
+	logic.RuleMode = entity.RuleMode(doc.RuleMode)
+	logic.TieBreak = entity.TieBreak(doc.TieBreak)
+
+	return logic
+}
+
+func resultsLogicToDocument(logic entity.ResultsLogic) bson.RawValue {
+	doc := model.ResultsLogicDocument{
+		Scales:   make([]model.ScaleDocument, 0, len(logic.Scales)),
+		RuleMode: string(logic.RuleMode),
+		TieBreak: string(logic.TieBreak),
+	}
+	for _, scale := range logic.Scales {
+		var bands []model.ScoreBandDocument
+		for _, band := range scale.Bands {
+			bands = append(bands, model.ScoreBandDocument{
+				Min:   band.Min,
+				Max:   band.Max,
+				Label: band.Label,
+				Text:  band.Text,
+			})
+		}
+
+		doc.Scales = append(doc.Scales, model.ScaleDocument{
+			ID:          string(scale.ID),
+			Name:        scale.Name,
+			Description: scale.Description,
+			Bands:       bands,
+		})
+	}
+
+	for _, rule := range logic.Rules {
+		conditions := make([]model.RuleConditionDocument, 0, len(rule.Conditions))
+		for _, condition := range rule.Conditions {
+			conditions = append(conditions, model.RuleConditionDocument{
+				ScaleID:  string(condition.ScaleID),
+				Band:     condition.Band,
+				Min:      condition.Min,
+				Max:      condition.Max,
+				Above:    string(condition.Above),
+				Dominant: condition.Dominant,
+			})
+		}
+
+		doc.Rules = append(doc.Rules, model.ResultRuleDocument{
+			ID:         rule.ID,
+			Conditions: conditions,
+			Text:       rule.Text,
+			Priority:   rule.Priority,
+		})
+	}
+
+	valueType, data, err := bson.MarshalValue(doc)
+	if err != nil {
+		valueType, data, _ = bson.MarshalValue("")
+	}
+	return bson.RawValue{Type: valueType, Value: data}
 }
@@ -0,0 +1,42 @@
+package mongodb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+func TestOwnerFilter(t *testing.T) {
+	ownerID := primitive.NewObjectID()
+	ownerless := bson.M{"$in": bson.A{nil, primitive.NilObjectID}}
+
+	tests := []struct {
+		name    string
+		owner   entity.UserID
+		want    interface{}
+		wantErr error
+	}{
+		{name: "владелец задан", owner: entity.UserID(ownerID.Hex()), want: ownerID},
+		{name: "тест без владельца", owner: "", want: ownerless},
+		{name: "нулевой владелец старого теста", owner: entity.UserID(primitive.NilObjectID.Hex()), want: ownerless},
+		{name: "некорректный ID", owner: "owner", wantErr: domainErrors.ErrInvalidID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ownerFilter(tt.owner)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ownerFilter(%q) error = %v; want %v", tt.owner, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ownerFilter(%q) = %v; want %v", tt.owner, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package mongodb
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"server/internal/adapter/repository/mongodb/model"
 	"server/internal/domain/entity"
@@ -13,8 +15,9 @@ import (
 )
 
 const (
-	userAnswersCollectionName    = "UserAnswer"
-	userAnswerIDsCollectionName  = "UserAnswerID"
+	userAnswersCollectionName       = "UserAnswer"
+	userAnswerIDsCollectionName     = "UserAnswerID"
+	userAnswerRetakesCollectionName = "UserAnswerRetake"
 )
 
 type UserAnswerRepository struct {
@@ -33,6 +36,23 @@ func (r *UserAnswerRepository) detailsCollection() *mongo.Collection {
 	return r.db.Collection(userAnswerIDsCollectionName)
 }
 
+func (r *UserAnswerRepository) retakesCollection() *mongo.Collection {
+	return r.db.Collection(userAnswerRetakesCollectionName)
+}
+
+// EnsureIndexes создает уникальный индекс прохождений по пользователю и тесту,
+// на котором держится атомарная проверка повторного прохождения
+func (r *UserAnswerRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.retakesCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "testId", Value: 1}},
+		Options: options.Index().SetName("user_test").SetUnique(true),
+	})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+	return nil
+}
+
 func (r *UserAnswerRepository) FindByUserID(ctx context.Context, userID entity.UserID) ([]entity.UserAnswer, error) {
 	objectID, err := primitive.ObjectIDFromHex(userID.String())
 	if err != nil {
@@ -91,10 +111,74 @@ func (r *UserAnswerRepository) FindByUserAndTest(ctx context.Context, userID ent
 	return answers, nil
 }
 
+func (r *UserAnswerRepository) FindByTestID(ctx context.Context, testID entity.TestID) ([]entity.UserAnswer, error) {
+	objectID, err := primitive.ObjectIDFromHex(testID.String())
+	if err != nil {
+		return nil, domainErrors.ErrInvalidID
+	}
+
+	cursor, err := r.answersCollection().Find(ctx, bson.M{"testId": objectID})
+	if err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+	defer cursor.Close(ctx)
+
+	var docs []model.UserAnswerDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+
+	answers := make([]entity.UserAnswer, 0, len(docs))
+	for _, doc := range docs {
+		answers = append(answers, r.toEntity(doc))
+	}
+
+	return answers, nil
+}
+
+func (r *UserAnswerRepository) UpdateResult(ctx context.Context, answer entity.UserAnswer) error {
+	objectID, err := primitive.ObjectIDFromHex(answer.ID.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	updateResult, err := r.answersCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{
+			"result":       answer.Result,
+			"scores":       scaleScoresToDocument(answer.Scores),
+			"dominant":     scaleIDsToDocument(answer.Dominant),
+			"matchedRules": answer.MatchedRules,
+		}},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return domainErrors.ErrNotFound
+	}
+
+	return nil
+}
+
 func (r *UserAnswerRepository) Insert(ctx context.Context, answer entity.UserAnswer) (entity.UserAnswerID, error) {
 	doc := r.toDocument(answer)
+	if !answer.ID.IsEmpty() {
+		objectID, err := primitive.ObjectIDFromHex(answer.ID.String())
+		if err != nil {
+			return "", domainErrors.ErrInvalidID
+		}
+		doc.ID = objectID
+	}
+
 	result, err := r.answersCollection().InsertOne(ctx, doc)
 	if err != nil {
+		// Ответ с заданным ID уже сохранен при прошлой попытке записи
+		if !answer.ID.IsEmpty() && mongo.IsDuplicateKeyError(err) {
+			return answer.ID, nil
+		}
 		return "", domainErrors.ErrDatabase
 	}
 
@@ -103,17 +187,14 @@ func (r *UserAnswerRepository) Insert(ctx context.Context, answer entity.UserAns
 }
 
 func (r *UserAnswerRepository) InsertDetails(ctx context.Context, details entity.UserAnswerDetails) error {
-	doc := model.UserAnswerDetailsDocument{
-		Answers: details.Answers,
-	}
-
-	if !details.TestingAnswerID.IsEmpty() {
-		if objID, err := primitive.ObjectIDFromHex(details.TestingAnswerID.String()); err == nil {
-			doc.TestingAnswerID = objID
-		}
-	}
-
-	_, err := r.detailsCollection().InsertOne(ctx, doc)
+	doc := answerDetailsToDocument(details)
+
+	// $setOnInsert не дает записать детальные ответы дважды при повторном сохранении
+	_, err := r.detailsCollection().UpdateOne(ctx,
+		bson.M{"testingAnswerId": doc.TestingAnswerID},
+		bson.M{"$setOnInsert": doc},
+		options.Update().SetUpsert(true),
+	)
 	if err != nil {
 		return domainErrors.ErrDatabase
 	}
@@ -135,13 +216,129 @@ func (r *UserAnswerRepository) FindDetailsByAnswerID(ctx context.Context, answer
 		return entity.UserAnswerDetails{}, domainErrors.ErrDatabase
 	}
 
-	return entity.UserAnswerDetails{
-		ID:              entity.UserAnswerID(doc.ID.Hex()),
-		TestingAnswerID: entity.UserAnswerID(doc.TestingAnswerID.Hex()),
-		Answers:         doc.Answers,
+	return answerDetailsDocToEntity(doc), nil
+}
+
+func (r *UserAnswerRepository) FindDetailsByAnswerIDs(ctx context.Context, answerIDs []entity.UserAnswerID) ([]entity.UserAnswerDetails, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(answerIDs))
+	for _, answerID := range answerIDs {
+		objectID, err := primitive.ObjectIDFromHex(answerID.String())
+		if err != nil {
+			return nil, domainErrors.ErrInvalidID
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+	if len(objectIDs) == 0 {
+		return []entity.UserAnswerDetails{}, nil
+	}
+
+	cursor, err := r.detailsCollection().Find(ctx, bson.M{"testingAnswerId": bson.M{"$in": objectIDs}})
+	if err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+	defer cursor.Close(ctx)
+
+	var docs []model.UserAnswerDetailsDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, domainErrors.ErrDatabase
+	}
+
+	details := make([]entity.UserAnswerDetails, 0, len(docs))
+	for _, doc := range docs {
+		details = append(details, answerDetailsDocToEntity(doc))
+	}
+
+	return details, nil
+}
+
+func (r *UserAnswerRepository) SummarizeByTestVersion(ctx context.Context, testID entity.TestID, version int) (entity.AnswerSummary, error) {
+	objectID, err := primitive.ObjectIDFromHex(testID.String())
+	if err != nil {
+		return entity.AnswerSummary{}, domainErrors.ErrInvalidID
+	}
+
+	// Ответы, сохраненные до появления версий, относятся к первой версии
+	filter := bson.M{"testId": objectID, "testVersion": version}
+	if version == entity.FirstTestVersion {
+		filter = bson.M{
+			"testId": objectID,
+			"$or": bson.A{
+				bson.M{"testVersion": version},
+				bson.M{"testVersion": bson.M{"$exists": false}},
+			},
+		}
+	}
+
+	count, err := r.answersCollection().CountDocuments(ctx, filter)
+	if err != nil {
+		return entity.AnswerSummary{}, domainErrors.ErrDatabase
+	}
+	if count == 0 {
+		return entity.AnswerSummary{}, nil
+	}
+
+	var last model.UserAnswerDocument
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}}).SetProjection(bson.M{"_id": 1})
+	if err := r.answersCollection().FindOne(ctx, filter, opts).Decode(&last); err != nil {
+		return entity.AnswerSummary{}, domainErrors.ErrDatabase
+	}
+
+	return entity.AnswerSummary{
+		Count:        int(count),
+		LastAnswerID: entity.UserAnswerID(last.ID.Hex()),
 	}, nil
 }
 
+// ReserveCompletion отмечает прохождение одним запросом: если отметка не
+// подходит под условие, upsert пытается вставить второй документ пользователя
+// и теста и упирается в уникальный индекс
+func (r *UserAnswerRepository) ReserveCompletion(ctx context.Context, userID entity.UserID, testID entity.TestID, at, lastBefore time.Time) error {
+	userObjectID, testObjectID, err := retakeObjectIDs(userID, testID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.retakesCollection().UpdateOne(ctx,
+		bson.M{"userId": userObjectID, "testId": testObjectID, "lastAt": bson.M{"$lte": lastBefore}},
+		bson.M{"$set": bson.M{"lastAt": at}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domainErrors.ErrRetakeDenied
+		}
+		return domainErrors.ErrDatabase
+	}
+	return nil
+}
+
+func (r *UserAnswerRepository) ReleaseCompletion(ctx context.Context, userID entity.UserID, testID entity.TestID, at time.Time) error {
+	userObjectID, testObjectID, err := retakeObjectIDs(userID, testID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.retakesCollection().DeleteOne(ctx,
+		bson.M{"userId": userObjectID, "testId": testObjectID, "lastAt": at})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+	return nil
+}
+
+// retakeObjectIDs преобразует ID пользователя и теста для отметки прохождения
+func retakeObjectIDs(userID entity.UserID, testID entity.TestID) (primitive.ObjectID, primitive.ObjectID, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID.String())
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, domainErrors.ErrInvalidID
+	}
+	testObjectID, err := primitive.ObjectIDFromHex(testID.String())
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, domainErrors.ErrInvalidID
+	}
+	return userObjectID, testObjectID, nil
+}
+
 func (r *UserAnswerRepository) DeleteByUserID(ctx context.Context, userID entity.UserID) error {
 	objectID, err := primitive.ObjectIDFromHex(userID.String())
 	if err != nil {
@@ -152,25 +349,55 @@ func (r *UserAnswerRepository) DeleteByUserID(ctx context.Context, userID entity
 	if err != nil {
 		return domainErrors.ErrDatabase
 	}
+	_, err = r.retakesCollection().DeleteMany(ctx, bson.M{"userId": objectID})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
 	return nil
 }
 
 // Конвертеры
 
 func (r *UserAnswerRepository) toEntity(doc model.UserAnswerDocument) entity.UserAnswer {
+	return userAnswerDocToEntity(doc)
+}
+
+// userAnswerDocToEntity конвертирует документ ответа пользователя в сущность
+func userAnswerDocToEntity(doc model.UserAnswerDocument) entity.UserAnswer {
+	matchedRules := doc.MatchedRules
+	if matchedRules == nil {
+		matchedRules = []string{}
+	}
+
+	// Попытки, сохраненные до появления completedAt, датируются временем создания документа
+	completedAt := doc.CompletedAt
+	if completedAt.IsZero() {
+		completedAt = doc.ID.Timestamp()
+	}
+
 	return entity.UserAnswer{
-		ID:     entity.UserAnswerID(doc.ID.Hex()),
-		UserID: entity.UserID(doc.UserID.Hex()),
-		TestID: entity.TestID(doc.TestID.Hex()),
-		Result: doc.Result,
-		Date:   doc.Date,
+		ID:           entity.UserAnswerID(doc.ID.Hex()),
+		UserID:       entity.UserID(doc.UserID.Hex()),
+		TestID:       entity.TestID(doc.TestID.Hex()),
+		TestVersion:  versionOrFirst(doc.TestVersion),
+		Result:       doc.Result,
+		Scores:       scaleScoresToEntity(doc.Scores),
+		Dominant:     scaleIDsToEntity(doc.Dominant),
+		MatchedRules: matchedRules,
+		Date:         doc.Date,
+		CompletedAt:  completedAt,
 	}
 }
 
 func (r *UserAnswerRepository) toDocument(answer entity.UserAnswer) model.UserAnswerDocument {
 	doc := model.UserAnswerDocument{
-		Result: answer.Result,
-		Date:   answer.Date,
+		TestVersion:  answer.TestVersion,
+		Result:       answer.Result,
+		Scores:       scaleScoresToDocument(answer.Scores),
+		Dominant:     scaleIDsToDocument(answer.Dominant),
+		MatchedRules: answer.MatchedRules,
+		Date:         answer.Date,
+		CompletedAt:  answer.CompletedAt,
 	}
 
 	if !answer.UserID.IsEmpty() {
@@ -187,3 +414,129 @@ func (r *UserAnswerRepository) toDocument(answer entity.UserAnswer) model.UserAn
 
 	return doc
 }
+
+func scaleScoresToDocument(scores []entity.ScaleScore) []model.ScaleScoreDocument {
+	docs := make([]model.ScaleScoreDocument, 0, len(scores))
+	for _, score := range scores {
+		docs = append(docs, model.ScaleScoreDocument{
+			ScaleID:  string(score.ScaleID),
+			Name:     score.Name,
+			Score:    score.Score,
+			Band:     score.Band,
+			BandText: score.BandText,
+		})
+	}
+	return docs
+}
+
+func scaleScoresToEntity(docs []model.ScaleScoreDocument) []entity.ScaleScore {
+	scores := make([]entity.ScaleScore, 0, len(docs))
+	for _, doc := range docs {
+		scores = append(scores, entity.ScaleScore{
+			ScaleID:  entity.ScaleID(doc.ScaleID),
+			Name:     doc.Name,
+			Score:    doc.Score,
+			Band:     doc.Band,
+			BandText: doc.BandText,
+		})
+	}
+	return scores
+}
+
+func scaleIDsToDocument(ids []entity.ScaleID) []string {
+	docs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		docs = append(docs, string(id))
+	}
+	return docs
+}
+
+func scaleIDsToEntity(docs []string) []entity.ScaleID {
+	ids := make([]entity.ScaleID, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, entity.ScaleID(doc))
+	}
+	return ids
+}
+
+// answerDetailsToDocument конвертирует детальные ответы в документ. Ответы с вариантами
+// дублируются в прежнем формате для клиентов, читающих поле answers
+func answerDetailsToDocument(details entity.UserAnswerDetails) model.UserAnswerDetailsDocument {
+	doc := model.UserAnswerDetailsDocument{
+		Answers:   [][]int{},
+		Responses: make([]model.QuestionAnswerDocument, 0, len(details.Answers)),
+	}
+
+	for _, answer := range details.Answers {
+		if row, ok := answer.Row(); ok {
+			doc.Answers = append(doc.Answers, row)
+		}
+
+		doc.Responses = append(doc.Responses, questionAnswerToDocument(answer))
+	}
+
+	if !details.TestingAnswerID.IsEmpty() {
+		if objID, err := primitive.ObjectIDFromHex(details.TestingAnswerID.String()); err == nil {
+			doc.TestingAnswerID = objID
+		}
+	}
+
+	return doc
+}
+
+// answerDetailsDocToEntity конвертирует документ детальных ответов в сущность.
+// Документы без responses созданы до появления типов вопросов и читаются из answers
+func answerDetailsDocToEntity(doc model.UserAnswerDetailsDocument) entity.UserAnswerDetails {
+	details := entity.UserAnswerDetails{
+		ID:              entity.UserAnswerID(doc.ID.Hex()),
+		TestingAnswerID: entity.UserAnswerID(doc.TestingAnswerID.Hex()),
+	}
+
+	if len(doc.Responses) == 0 {
+		details.Answers = make([]entity.QuestionAnswer, 0, len(doc.Answers))
+		for _, row := range doc.Answers {
+			details.Answers = append(details.Answers, entity.QuestionAnswerFromRow(row))
+		}
+		return details
+	}
+
+	details.Answers = make([]entity.QuestionAnswer, 0, len(doc.Responses))
+	for _, response := range doc.Responses {
+		details.Answers = append(details.Answers, questionAnswerToEntity(response))
+	}
+	return details
+}
+
+// questionAnswerToDocument конвертирует ответ на вопрос в документ
+func questionAnswerToDocument(answer entity.QuestionAnswer) model.QuestionAnswerDocument {
+	var cells []model.MatrixCellDocument
+	for _, cell := range answer.Cells {
+		cells = append(cells, model.MatrixCellDocument{RowID: cell.RowID, OptionID: cell.OptionID})
+	}
+
+	return model.QuestionAnswerDocument{
+		QuestionID:  answer.QuestionID,
+		OptionIDs:   answer.OptionIDs,
+		Value:       answer.Value,
+		Text:        answer.Text,
+		Cells:       cells,
+		TimeSpentMs: answer.TimeSpent.Milliseconds(),
+	}
+}
+
+// questionAnswerToEntity конвертирует документ ответа на вопрос в сущность
+func questionAnswerToEntity(doc model.QuestionAnswerDocument) entity.QuestionAnswer {
+	cells := make([]entity.MatrixCell, 0, len(doc.Cells))
+	for _, cell := range doc.Cells {
+		cells = append(cells, entity.MatrixCell{RowID: cell.RowID, OptionID: cell.OptionID})
+	}
+
+	return entity.QuestionAnswer{
+		QuestionID: doc.QuestionID,
+		OptionIDs:  doc.OptionIDs,
+		Value:      doc.Value,
+		Text:       doc.Text,
+		Cells:      cells,
+		TimeSpent:  time.Duration(doc.TimeSpentMs) * time.Millisecond,
+	}
+}
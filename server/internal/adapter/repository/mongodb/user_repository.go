@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"regexp"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -97,6 +98,29 @@ func (r *UserRepository) UpdateStatus(ctx context.Context, id entity.UserID, sta
 	return nil
 }
 
+func (r *UserRepository) UpdatePassword(ctx context.Context, id entity.UserID, passwordHash string) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	result, err := r.collection().UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{"password": passwordHash}},
+	)
+
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (r *UserRepository) UpdateData(ctx context.Context, id entity.UserID, firstName, lastName string) error {
 	objectID, err := primitive.ObjectIDFromHex(id.String())
 	if err != nil {
@@ -166,6 +190,218 @@ func (r *UserRepository) FindAllExcept(ctx context.Context, excludeID entity.Use
 	return users, nil
 }
 
+func (r *UserRepository) AddSession(ctx context.Context, id entity.UserID, session entity.Session) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	result, err := r.collection().UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$push": bson.M{"sessions": sessionToDocument(session)}},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) RemoveSession(ctx context.Context, id entity.UserID, sessionID entity.SessionID) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	result, err := r.collection().UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$pull": bson.M{"sessions": bson.M{"id": sessionID.String()}}},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) FindByExternalAccount(ctx context.Context, provider entity.OAuthProvider, subject string) (entity.User, error) {
+	var doc model.UserDocument
+	err := r.collection().FindOne(ctx, bson.M{
+		"externalAccounts": bson.M{"$elemMatch": bson.M{
+			"provider": string(provider),
+			"subject":  subject,
+		}},
+	}).Decode(&doc)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entity.User{}, domainErrors.ErrUserNotFound
+		}
+		return entity.User{}, domainErrors.ErrDatabase
+	}
+
+	return r.toEntity(doc), nil
+}
+
+func (r *UserRepository) LinkExternalAccount(ctx context.Context, id entity.UserID, account entity.ExternalAccount) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	// Убираем прежнюю привязку этого провайдера
+	_, err = r.collection().UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$pull": bson.M{"externalAccounts": bson.M{"provider": string(account.Provider)}}},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	set := bson.M{}
+	if field := providerFlagField(account.Provider); field != "" {
+		set[field] = true
+	}
+
+	update := bson.M{"$push": bson.M{"externalAccounts": externalAccountToDocument(account)}}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UnlinkExternalAccount(ctx context.Context, id entity.UserID, provider entity.OAuthProvider) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	update := bson.M{"$pull": bson.M{"externalAccounts": bson.M{"provider": string(provider)}}}
+	if field := providerFlagField(provider); field != "" {
+		update["$set"] = bson.M{field: false}
+	}
+
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) RemoveExpiredSessions(ctx context.Context, id entity.UserID, now time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	result, err := r.collection().UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$pull": bson.M{"sessions": bson.M{"expiresAt": bson.M{"$lte": now}}}},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) RemoveAllSessions(ctx context.Context, id entity.UserID) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	result, err := r.collection().UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{"sessions": bson.A{}}},
+	)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) AssignPsychoType(ctx context.Context, id entity.UserID, assignment entity.PsychoTypeAssignment) error {
+	objectID, err := primitive.ObjectIDFromHex(id.String())
+	if err != nil {
+		return domainErrors.ErrInvalidID
+	}
+
+	doc := psychoTypeAssignmentToDocument(assignment)
+
+	// Привязанная попытка может быть старше уже назначенного психотипа: запись встает
+	// в историю по времени назначения. Повторная запись о той же попытке не добавляется
+	filter := bson.M{"_id": objectID}
+	if !doc.AttemptID.IsZero() {
+		filter["psychoTypeHistory.attemptId"] = bson.M{"$ne": doc.AttemptID}
+	}
+	_, err = r.collection().UpdateOne(ctx, filter, bson.M{
+		"$push": bson.M{"psychoTypeHistory": bson.M{
+			"$each": bson.A{doc},
+			"$sort": bson.M{"assignedAt": 1},
+		}},
+	})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	// Психотип меняется, только если в истории нет более новых записей. Запись уже
+	// добавлена, поэтому при одновременных назначениях остается самое новое
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": objectID}, bson.A{
+		bson.M{"$set": bson.M{"psychoType": bson.M{"$cond": bson.A{
+			bson.M{"$gt": bson.A{bson.M{"$max": "$psychoTypeHistory.assignedAt"}, doc.AssignedAt}},
+			"$psychoType",
+			doc.Value,
+		}}}},
+	})
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	if result.MatchedCount == 0 {
+		return domainErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
 // Конвертеры
 
 func (r *UserRepository) toEntity(doc model.UserDocument) entity.User {
@@ -175,12 +411,16 @@ func (r *UserRepository) toEntity(doc model.UserDocument) entity.User {
 		LastName:      doc.LastName,
 		Email:         doc.Email,
 		Status:        entity.UserStatus(doc.Status),
+		Role:          entity.Role(doc.Role),
 		Password:      doc.Password,
 		PsychoType:    doc.PsychoType,
 		Date:          doc.Date,
 		IsGoogleAdded: doc.IsGoogleAdded,
 		IsYandexAdded: doc.IsYandexAdded,
-		Sessions:      doc.Sessions,
+		Sessions:      sessionsToEntity(doc.Sessions),
+
+		ExternalAccounts:  externalAccountsToEntity(doc.ExternalAccounts),
+		PsychoTypeHistory: psychoTypeHistoryToEntity(doc.PsychoTypeHistory),
 	}
 }
 
@@ -190,12 +430,16 @@ func (r *UserRepository) toDocument(user entity.User) model.UserDocument {
 		LastName:      user.LastName,
 		Email:         user.Email,
 		Status:        string(user.Status),
+		Role:          string(user.Role),
 		Password:      user.Password,
 		PsychoType:    user.PsychoType,
 		Date:          user.Date,
 		IsGoogleAdded: user.IsGoogleAdded,
 		IsYandexAdded: user.IsYandexAdded,
-		Sessions:      user.Sessions,
+		Sessions:      sessionsToDocument(user.Sessions),
+
+		ExternalAccounts:  externalAccountsToDocument(user.ExternalAccounts),
+		PsychoTypeHistory: psychoTypeHistoryToDocument(user.PsychoTypeHistory),
 	}
 
 	// Если ID не пустой, конвертируем его
@@ -207,3 +451,108 @@ func (r *UserRepository) toDocument(user entity.User) model.UserDocument {
 
 	return doc
 }
+
+func sessionToDocument(session entity.Session) model.SessionDocument {
+	return model.SessionDocument{
+		ID:        session.ID.String(),
+		CreatedAt: session.CreatedAt,
+		ExpiresAt: session.ExpiresAt,
+	}
+}
+
+func sessionsToDocument(sessions []entity.Session) []model.SessionDocument {
+	docs := make([]model.SessionDocument, 0, len(sessions))
+	for _, session := range sessions {
+		docs = append(docs, sessionToDocument(session))
+	}
+	return docs
+}
+
+func sessionsToEntity(docs []model.SessionDocument) []entity.Session {
+	sessions := make([]entity.Session, 0, len(docs))
+	for _, doc := range docs {
+		sessions = append(sessions, entity.Session{
+			ID:        entity.SessionID(doc.ID),
+			CreatedAt: doc.CreatedAt,
+			ExpiresAt: doc.ExpiresAt,
+		})
+	}
+	return sessions
+}
+
+// providerFlagField возвращает поле документа с флагом привязки провайдера
+func providerFlagField(provider entity.OAuthProvider) string {
+	switch provider {
+	case entity.OAuthProviderGoogle:
+		return "isGoogleAdded"
+	case entity.OAuthProviderYandex:
+		return "isYandexAdded"
+	default:
+		return ""
+	}
+}
+
+func externalAccountToDocument(account entity.ExternalAccount) model.ExternalAccountDocument {
+	return model.ExternalAccountDocument{
+		Provider: string(account.Provider),
+		Subject:  account.Subject,
+		Email:    account.Email,
+		LinkedAt: account.LinkedAt,
+	}
+}
+
+func externalAccountsToDocument(accounts []entity.ExternalAccount) []model.ExternalAccountDocument {
+	docs := make([]model.ExternalAccountDocument, 0, len(accounts))
+	for _, account := range accounts {
+		docs = append(docs, externalAccountToDocument(account))
+	}
+	return docs
+}
+
+func externalAccountsToEntity(docs []model.ExternalAccountDocument) []entity.ExternalAccount {
+	accounts := make([]entity.ExternalAccount, 0, len(docs))
+	for _, doc := range docs {
+		accounts = append(accounts, entity.ExternalAccount{
+			Provider: entity.OAuthProvider(doc.Provider),
+			Subject:  doc.Subject,
+			Email:    doc.Email,
+			LinkedAt: doc.LinkedAt,
+		})
+	}
+	return accounts
+}
+
+func psychoTypeAssignmentToDocument(assignment entity.PsychoTypeAssignment) model.PsychoTypeAssignmentDocument {
+	doc := model.PsychoTypeAssignmentDocument{
+		Value:      assignment.Value,
+		AssignedAt: assignment.AssignedAt,
+	}
+	if objID, err := primitive.ObjectIDFromHex(assignment.TestID.String()); err == nil {
+		doc.TestID = objID
+	}
+	if objID, err := primitive.ObjectIDFromHex(assignment.AttemptID.String()); err == nil {
+		doc.AttemptID = objID
+	}
+	return doc
+}
+
+func psychoTypeHistoryToDocument(history []entity.PsychoTypeAssignment) []model.PsychoTypeAssignmentDocument {
+	docs := make([]model.PsychoTypeAssignmentDocument, 0, len(history))
+	for _, assignment := range history {
+		docs = append(docs, psychoTypeAssignmentToDocument(assignment))
+	}
+	return docs
+}
+
+func psychoTypeHistoryToEntity(docs []model.PsychoTypeAssignmentDocument) []entity.PsychoTypeAssignment {
+	history := make([]entity.PsychoTypeAssignment, 0, len(docs))
+	for _, doc := range docs {
+		history = append(history, entity.PsychoTypeAssignment{
+			Value:      doc.Value,
+			TestID:     entity.TestID(doc.TestID.Hex()),
+			AttemptID:  entity.UserAnswerID(doc.AttemptID.Hex()),
+			AssignedAt: doc.AssignedAt,
+		})
+	}
+	return history
+}
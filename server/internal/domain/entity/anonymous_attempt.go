@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// AnonymousAttemptID представляет уникальный идентификатор попытки без входа в аккаунт
+type AnonymousAttemptID string
+
+func (id AnonymousAttemptID) String() string { return string(id) }
+func (id AnonymousAttemptID) IsEmpty() bool  { return id == "" }
+
+// AnonymousAttempt - попытка прохождения теста без входа в аккаунт. Хранится ограниченное
+// время и по токену привязки переносится в историю пользователя после входа.
+// Хранится только хеш токена: сам токен известен лишь участнику
+type AnonymousAttempt struct {
+	ID             AnonymousAttemptID
+	ClaimTokenHash string
+	Answer         UserAnswer // результат попытки; UserID не заполнен
+	Answers        []QuestionAnswer
+	PsychoType     string // психотип, назначаемый пользователю при привязке; пусто - не назначается
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+}
+
+// IsExpired проверяет, истек ли срок хранения попытки
+func (a *AnonymousAttempt) IsExpired(now time.Time) bool {
+	return !now.Before(a.ExpiresAt)
+}
@@ -0,0 +1,106 @@
+package entity
+
+// AttemptLayout - порядок показа вопросов и вариантов ответа в попытке. Варианты
+// перемешанного вопроса показываются под номерами по порядку показа (с 1), а ответы
+// переводятся в идентификаторы вариантов теста до проверки и подсчета баллов
+type AttemptLayout struct {
+	QuestionOrder []int         // ID вопросов в порядке показа; пусто - порядок теста
+	OptionOrder   map[int][]int // ID вопроса -> ID вариантов в порядке показа
+}
+
+// IsEmpty проверяет, что попытка показывается в порядке теста
+func (l AttemptLayout) IsEmpty() bool {
+	return len(l.QuestionOrder) == 0 && len(l.OptionOrder) == 0
+}
+
+// Arrange возвращает вопросы в порядке показа попытки с перенумерованными вариантами.
+// Вопросы, отсутствующие в порядке показа, следуют в конце в порядке теста
+func (l AttemptLayout) Arrange(questions []Question) []Question {
+	if l.IsEmpty() {
+		return questions
+	}
+
+	byID := make(map[int]Question, len(questions))
+	for _, question := range questions {
+		byID[question.ID] = question
+	}
+
+	arranged := make([]Question, 0, len(questions))
+	placed := make(map[int]struct{}, len(questions))
+	for _, id := range l.QuestionOrder {
+		if question, ok := byID[id]; ok {
+			arranged = append(arranged, l.arrangeOptions(question))
+			placed[id] = struct{}{}
+		}
+	}
+	for _, question := range questions {
+		if _, ok := placed[question.ID]; !ok {
+			arranged = append(arranged, l.arrangeOptions(question))
+		}
+	}
+	return arranged
+}
+
+// arrangeOptions упорядочивает варианты вопроса и присваивает им номера показа.
+// Варианты в правилах перехода переводятся в те же номера
+func (l AttemptLayout) arrangeOptions(question Question) Question {
+	order, ok := l.OptionOrder[question.ID]
+	if !ok {
+		return question
+	}
+
+	options := make([]AnswerOption, 0, len(order))
+	for position, optionID := range order {
+		if option, ok := question.Option(optionID); ok {
+			option.ID = position + 1
+			options = append(options, option)
+		}
+	}
+	question.AnswerOptions = options
+
+	branches := make([]BranchRule, 0, len(question.Branches))
+	for _, rule := range question.Branches {
+		rule.OptionIDs = l.Present(QuestionAnswer{QuestionID: question.ID, OptionIDs: rule.OptionIDs}).OptionIDs
+		branches = append(branches, rule)
+	}
+	question.Branches = branches
+	return question
+}
+
+// Present переводит ответ с идентификаторами вариантов теста в номера показа попытки
+func (l AttemptLayout) Present(answer QuestionAnswer) QuestionAnswer {
+	order, ok := l.OptionOrder[answer.QuestionID]
+	if !ok || len(answer.OptionIDs) == 0 {
+		return answer
+	}
+
+	positions := make(map[int]int, len(order))
+	for position, optionID := range order {
+		positions[optionID] = position + 1
+	}
+	presented := make([]int, 0, len(answer.OptionIDs))
+	for _, optionID := range answer.OptionIDs {
+		presented = append(presented, positions[optionID])
+	}
+	answer.OptionIDs = presented
+	return answer
+}
+
+// Canonical переводит номера показа в ответе в идентификаторы вариантов теста.
+// Возвращает false, если в ответе есть номер, под которым вариант не показывался
+func (l AttemptLayout) Canonical(answer QuestionAnswer) (QuestionAnswer, bool) {
+	order, ok := l.OptionOrder[answer.QuestionID]
+	if !ok || len(answer.OptionIDs) == 0 {
+		return answer, true
+	}
+
+	canonical := make([]int, 0, len(answer.OptionIDs))
+	for _, position := range answer.OptionIDs {
+		if position < 1 || position > len(order) {
+			return answer, false
+		}
+		canonical = append(canonical, order[position-1])
+	}
+	answer.OptionIDs = canonical
+	return answer, true
+}
@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// AttemptSessionID представляет уникальный идентификатор незавершенной попытки
+type AttemptSessionID string
+
+func (id AttemptSessionID) String() string { return string(id) }
+func (id AttemptSessionID) IsEmpty() bool  { return id == "" }
+
+// TimeLimitGrace - запас на задержку сети при проверке ограничений времени
+const TimeLimitGrace = 5 * time.Second
+
+// AttemptSession - незавершенная попытка прохождения теста. Ответы сохраняются по одному
+// и доступны с любого устройства пользователя до отправки. Попытка без изменений дольше
+// установленного срока считается брошенной и истекает.
+// Ограничения времени версии теста и порядок показа вопросов фиксируются при начале попытки
+type AttemptSession struct {
+	ID                AttemptSessionID
+	UserID            UserID
+	TestID            TestID
+	TestVersion       int // версия теста, вопросы которой видит пользователь
+	Answers           []QuestionAnswer
+	StartedAt         time.Time
+	UpdatedAt         time.Time
+	ExpiresAt         time.Time
+	SubmittedAt       *time.Time
+	Deadline          *time.Time        // срок ответа на все вопросы; nil - без ограничения
+	QuestionTimeLimit time.Duration     // время на ответ на один вопрос; 0 - без ограничения
+	CurrentQuestionID int               // вопрос, открытый последним и еще не отвеченный
+	CurrentQuestionAt *time.Time        // момент открытия текущего вопроса
+	QuestionOpenedAt  map[int]time.Time // момент первого открытия вопроса по ID вопроса
+	Layout            AttemptLayout
+}
+
+// IsSubmitted проверяет, отправлена ли попытка
+func (s *AttemptSession) IsSubmitted() bool {
+	return s.SubmittedAt != nil
+}
+
+// IsExpired проверяет, истек ли срок попытки
+func (s *AttemptSession) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// IsActive проверяет, можно ли продолжить попытку
+func (s *AttemptSession) IsActive(now time.Time) bool {
+	return !s.IsSubmitted() && !s.IsExpired(now)
+}
+
+// IsOwnedBy проверяет, начата ли попытка пользователем
+func (s *AttemptSession) IsOwnedBy(userID UserID) bool {
+	return !userID.IsEmpty() && s.UserID == userID
+}
+
+// IsOverdue проверяет, истекло ли время на всю попытку с учетом TimeLimitGrace
+func (s *AttemptSession) IsOverdue(now time.Time) bool {
+	return s.Deadline != nil && now.After(s.Deadline.Add(TimeLimitGrace))
+}
+
+// QuestionOpened возвращает момент первого открытия вопроса, от которого отсчитывается
+// время ответа на него. В попытках, начатых до учета первого открытия, известен только
+// момент открытия текущего вопроса
+func (s *AttemptSession) QuestionOpened(questionID int) (time.Time, bool) {
+	if openedAt, ok := s.QuestionOpenedAt[questionID]; ok {
+		return openedAt, true
+	}
+	if s.CurrentQuestionAt != nil && s.CurrentQuestionID == questionID {
+		return *s.CurrentQuestionAt, true
+	}
+	return time.Time{}, false
+}
+
+// IsQuestionOverdue проверяет, истекло ли время на ответ на вопрос с учетом TimeLimitGrace.
+// Время отсчитывается от первого открытия; у вопроса, который не открывался, оно не истекает
+func (s *AttemptSession) IsQuestionOverdue(questionID int, now time.Time) bool {
+	if s.QuestionTimeLimit <= 0 {
+		return false
+	}
+	openedAt, ok := s.QuestionOpened(questionID)
+	return ok && now.After(openedAt.Add(s.QuestionTimeLimit+TimeLimitGrace))
+}
+
+// Answer возвращает сохраненный ответ на вопрос
+func (s *AttemptSession) Answer(questionID int) (QuestionAnswer, bool) {
+	for _, answer := range s.Answers {
+		if answer.QuestionID == questionID {
+			return answer, true
+		}
+	}
+	return QuestionAnswer{}, false
+}
+
+// SetAnswer сохраняет ответ на вопрос, заменяя предыдущий ответ на тот же вопрос
+func (s *AttemptSession) SetAnswer(answer QuestionAnswer) {
+	for i := range s.Answers {
+		if s.Answers[i].QuestionID == answer.QuestionID {
+			s.Answers[i] = answer
+			return
+		}
+	}
+	s.Answers = append(s.Answers, answer)
+	sort.Slice(s.Answers, func(i, j int) bool { return s.Answers[i].QuestionID < s.Answers[j].QuestionID })
+}
@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// OAuthProvider описывает внешнего провайдера входа
+type OAuthProvider string
+
+const (
+	OAuthProviderGoogle OAuthProvider = "google"
+	OAuthProviderYandex OAuthProvider = "yandex"
+)
+
+// IsValid проверяет, что провайдер поддерживается
+func (p OAuthProvider) IsValid() bool {
+	return p == OAuthProviderGoogle || p == OAuthProviderYandex
+}
+
+// ExternalAccount - привязанная к пользователю учетная запись внешнего провайдера
+type ExternalAccount struct {
+	Provider OAuthProvider
+	Subject  string
+	Email    string
+	LinkedAt time.Time
+}
+
+// ExternalProfile - профиль пользователя, полученный от внешнего провайдера
+type ExternalProfile struct {
+	Provider      OAuthProvider
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
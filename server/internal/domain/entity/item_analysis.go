@@ -0,0 +1,67 @@
+package entity
+
+import "time"
+
+// ItemAnalysis - психометрический анализ вопросов одной версии теста по сохраненным
+// попыткам: распределение ответов и надежность шкал. Показатели, которые нельзя вычислить
+// (слишком мало попыток или нет разброса баллов), не заполняются
+type ItemAnalysis struct {
+	TestID      TestID
+	Version     int
+	Respondents int // число попыток по версии
+	Questions   []QuestionStats
+	Scales      []ScaleReliability
+	Source      AnswerSummary // попытки, по которым выполнен анализ
+	ComputedAt  time.Time
+}
+
+// AnswerSummary - сводка попыток по версии теста: по ней определяется, устарел ли
+// сохраненный анализ
+type AnswerSummary struct {
+	Count        int
+	LastAnswerID UserAnswerID
+}
+
+// QuestionStats - распределение ответов на вопрос. Options заполняется для вопросов
+// с вариантами, Values - для шкалы Лайкерта
+type QuestionStats struct {
+	QuestionID int
+	Responses  int // число попыток с ответом на вопрос
+	Options    []ResponseFrequency
+	Values     []ResponseFrequency
+}
+
+// ResponseFrequency - сколько раз выбран вариант или значение и доля от числа ответов.
+// Для ранжирования учитывается вариант на первом месте, для матрицы - выбор столбца
+// в строке, и доля считается от числа заполненных строк
+type ResponseFrequency struct {
+	Key   int // ID варианта или значение шкалы
+	Label string
+	Count int
+	Share float64
+}
+
+// ScaleReliability - надежность шкалы: альфа Кронбаха и показатели вопросов, дающих
+// баллы по шкале. Учитываются попытки с ответами на все вопросы шкалы
+type ScaleReliability struct {
+	ScaleID     ScaleID
+	Name        string
+	Respondents int
+	Mean        float64
+	StdDev      float64
+	Alpha       *float64
+	Items       []ItemStats
+}
+
+// ItemStats - показатели вопроса по шкале. Difficulty - средний балл в долях от возможного
+// диапазона; Discrimination - разность средних баллов групп с высоким и низким итогом
+// (по 27% попыток) в долях от диапазона; ItemTotal - корреляция балла вопроса с суммой
+// остальных вопросов шкалы; AlphaIfDeleted - альфа шкалы без этого вопроса
+type ItemStats struct {
+	QuestionID     int
+	Mean           float64
+	Difficulty     *float64
+	Discrimination *float64
+	ItemTotal      *float64
+	AlphaIfDeleted *float64
+}
@@ -0,0 +1,86 @@
+package entity
+
+import "time"
+
+// NormTableID представляет уникальный идентификатор таблицы норм
+type NormTableID string
+
+func (id NormTableID) String() string { return string(id) }
+func (id NormTableID) IsEmpty() bool  { return id == "" }
+
+// NormStatus описывает состояние таблицы норм
+type NormStatus string
+
+const (
+	// NormStatusDraft - таблица построена, но не применяется к результатам
+	NormStatusDraft NormStatus = "draft"
+	// NormStatusFrozen - таблица закреплена как версия норм и больше не меняется
+	NormStatusFrozen NormStatus = "frozen"
+)
+
+// NormFilter - отбор попыток для построения норм. Пустые поля не ограничивают отбор.
+// Cohort - название выборки, UserIDs - ее участники
+type NormFilter struct {
+	From    *time.Time
+	To      *time.Time
+	Cohort  string
+	UserIDs []UserID
+}
+
+// NormTable - таблица норм теста: распределение баллов по шкалам в выборке попыток
+// одной версии теста. Version присваивается при закреплении; к результату попытки
+// применяется закрепленная таблица с наибольшей версией, построенная по той же версии теста
+type NormTable struct {
+	ID          NormTableID
+	TestID      TestID
+	TestVersion int // версия теста, по попыткам которой построена таблица
+	Version     int // 0 - черновик
+	Name        string
+	Filter      NormFilter
+	Status      NormStatus
+	Sample      int // число попыток в выборке
+	Scales      []ScaleNorm
+	CreatedBy   UserID
+	CreatedAt   time.Time
+	FrozenAt    *time.Time
+}
+
+// IsFrozen проверяет, закреплена ли таблица норм
+func (t *NormTable) IsFrozen() bool {
+	return t.Status == NormStatusFrozen
+}
+
+// Scale возвращает нормы шкалы
+func (t *NormTable) Scale(id ScaleID) (ScaleNorm, bool) {
+	for _, scale := range t.Scales {
+		if scale.ScaleID == id {
+			return scale, true
+		}
+	}
+	return ScaleNorm{}, false
+}
+
+// ScaleNorm - распределение баллов шкалы в выборке. Points - различные баллы
+// по возрастанию с числом попыток, набравших каждый из них
+type ScaleNorm struct {
+	ScaleID ScaleID
+	Name    string
+	Sample  int
+	Mean    float64
+	StdDev  float64
+	Points  []NormPoint
+}
+
+// NormPoint - балл шкалы и число попыток выборки с этим баллом
+type NormPoint struct {
+	Score float64
+	Count int
+}
+
+// NormScore - положение балла шкалы относительно выборки: процентильный ранг (0-100)
+// и стэнайн (1-9)
+type NormScore struct {
+	ScaleID    ScaleID
+	Percentile float64
+	Stanine    int
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// PasswordResetID представляет уникальный идентификатор запроса на сброс пароля
+type PasswordResetID string
+
+func (id PasswordResetID) String() string { return string(id) }
+func (id PasswordResetID) IsEmpty() bool  { return id == "" }
+
+// PasswordReset - одноразовый запрос на сброс пароля.
+// Хранится только хеш токена: сам токен известен лишь получателю письма
+type PasswordReset struct {
+	ID        PasswordResetID
+	UserID    UserID
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// IsExpired проверяет, истек ли срок действия токена сброса
+func (r *PasswordReset) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// IsUsed проверяет, был ли токен сброса уже использован
+func (r *PasswordReset) IsUsed() bool {
+	return r.UsedAt != nil
+}
+
+// IsValid проверяет, можно ли использовать токен сброса
+func (r *PasswordReset) IsValid(now time.Time) bool {
+	return !r.IsUsed() && !r.IsExpired(now)
+}
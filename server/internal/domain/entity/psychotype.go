@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// PsychoTypeAssignment - запись истории психотипа пользователя: значение и попытка
+// теста определения психотипа, по которой оно было установлено
+type PsychoTypeAssignment struct {
+	Value      string
+	TestID     TestID
+	AttemptID  UserAnswerID
+	AssignedAt time.Time
+}
@@ -0,0 +1,101 @@
+package entity
+
+// BranchRule - правило перехода после ответа на вопрос. Срабатывает, если в ответе выбран
+// хотя бы один из вариантов OptionIDs; пустой OptionIDs - при любом ответе. Следующим
+// показывается вопрос GoTo, а при Finish прохождение теста заканчивается
+type BranchRule struct {
+	OptionIDs []int
+	GoTo      int
+	Finish    bool
+}
+
+// IsUnconditional проверяет, срабатывает ли правило при любом ответе
+func (r BranchRule) IsUnconditional() bool {
+	return len(r.OptionIDs) == 0
+}
+
+// Matches проверяет, срабатывает ли правило для ответа на вопрос
+func (r BranchRule) Matches(answer QuestionAnswer) bool {
+	if r.IsUnconditional() {
+		return true
+	}
+	for _, chosen := range answer.OptionIDs {
+		for _, optionID := range r.OptionIDs {
+			if chosen == optionID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CanBranch проверяет, можно ли задавать переходы для вопроса этого типа
+func (t QuestionType) CanBranch() bool {
+	return t == QuestionTypeOne || t == QuestionTypeMultiple
+}
+
+// Branch возвращает первое правило перехода, сработавшее для ответа на вопрос
+func (q *Question) Branch(answer QuestionAnswer) (BranchRule, bool) {
+	for _, rule := range q.Branches {
+		if rule.Matches(answer) {
+			return rule, true
+		}
+	}
+	return BranchRule{}, false
+}
+
+// HasBranches проверяет, заданы ли в тесте переходы между вопросами
+func (d *QuestionsDocument) HasBranches() bool {
+	for _, question := range d.Questions {
+		if len(question.Branches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Route возвращает ID вопросов, которые показываются при данных ответах, в порядке показа.
+// Вопросы идут по порядку теста, пока сработавшее правило не переведет к другому вопросу
+// или не завершит тест. На вопрос без ответа правила не действуют
+func (d *QuestionsDocument) Route(answers []QuestionAnswer) []int {
+	byQuestion := make(map[int]QuestionAnswer, len(answers))
+	for _, answer := range answers {
+		byQuestion[answer.QuestionID] = answer
+	}
+	positions := make(map[int]int, len(d.Questions))
+	for position, question := range d.Questions {
+		positions[question.ID] = position
+	}
+
+	route := make([]int, 0, len(d.Questions))
+	visited := make(map[int]struct{}, len(d.Questions))
+	for position := 0; position < len(d.Questions); {
+		question := d.Questions[position]
+		// Циклы отклоняются при сохранении теста; повтор означает поврежденные данные
+		if _, ok := visited[question.ID]; ok {
+			break
+		}
+		visited[question.ID] = struct{}{}
+		route = append(route, question.ID)
+
+		answer, answered := byQuestion[question.ID]
+		if !answered {
+			position++
+			continue
+		}
+		rule, ok := question.Branch(answer)
+		if !ok {
+			position++
+			continue
+		}
+		if rule.Finish {
+			break
+		}
+		next, ok := positions[rule.GoTo]
+		if !ok {
+			break
+		}
+		position = next
+	}
+	return route
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoute(t *testing.T) {
+	option := []AnswerOption{{ID: 1}, {ID: 2}}
+	doc := QuestionsDocument{Questions: []Question{
+		{ID: 1, SelectType: QuestionTypeOne, AnswerOptions: option, Branches: []BranchRule{
+			{OptionIDs: []int{1}, GoTo: 3},
+			{OptionIDs: []int{2}, Finish: true},
+		}},
+		{ID: 2, SelectType: QuestionTypeOne, AnswerOptions: option},
+		{ID: 3, SelectType: QuestionTypeOne, AnswerOptions: option, Branches: []BranchRule{
+			{OptionIDs: []int{2}, GoTo: 5},
+		}},
+		{ID: 4, SelectType: QuestionTypeOne, AnswerOptions: option},
+		{ID: 5, SelectType: QuestionTypeOne, AnswerOptions: option},
+	}}
+
+	tests := []struct {
+		name    string
+		answers []QuestionAnswer
+		want    []int
+	}{
+		{name: "без ответов", answers: nil, want: []int{1, 2, 3, 4, 5}},
+		{
+			name:    "переход и продолжение по порядку",
+			answers: []QuestionAnswer{{QuestionID: 1, OptionIDs: []int{1}}, {QuestionID: 3, OptionIDs: []int{1}}},
+			want:    []int{1, 3, 4, 5},
+		},
+		{
+			name:    "два перехода",
+			answers: []QuestionAnswer{{QuestionID: 1, OptionIDs: []int{1}}, {QuestionID: 3, OptionIDs: []int{2}}},
+			want:    []int{1, 3, 5},
+		},
+		{name: "завершение", answers: []QuestionAnswer{{QuestionID: 1, OptionIDs: []int{2}}}, want: []int{1}},
+	}
+
+	for _, tt := range tests {
+		if got := doc.Route(tt.answers); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Route = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Поврежденный документ с циклом не зацикливает обход
+	cyclic := QuestionsDocument{Questions: []Question{
+		{ID: 1, SelectType: QuestionTypeOne, AnswerOptions: option},
+		{ID: 2, SelectType: QuestionTypeOne, AnswerOptions: option, Branches: []BranchRule{{GoTo: 1}}},
+	}}
+	got := cyclic.Route([]QuestionAnswer{{QuestionID: 2, OptionIDs: []int{1}}})
+	if want := []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("cyclic Route = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package entity
+
+import "strings"
+
+// QuestionType - тип вопроса. Определяет, какие поля вопроса заполнены и в каком виде
+// принимается ответ на него
+type QuestionType string
+
+const (
+	QuestionTypeOne      QuestionType = "one"      // выбор одного варианта
+	QuestionTypeMultiple QuestionType = "multiple" // выбор нескольких вариантов
+	QuestionTypeLikert   QuestionType = "likert"   // числовая шкала Лайкерта
+	QuestionTypeText     QuestionType = "text"     // свободный текстовый ответ
+	QuestionTypeRanking  QuestionType = "ranking"  // упорядочивание всех вариантов
+	QuestionTypeMatrix   QuestionType = "matrix"   // выбор одного столбца в каждой строке
+)
+
+// IsValid проверяет, что тип вопроса известен
+func (t QuestionType) IsValid() bool {
+	switch t {
+	case QuestionTypeOne, QuestionTypeMultiple, QuestionTypeLikert,
+		QuestionTypeText, QuestionTypeRanking, QuestionTypeMatrix:
+		return true
+	default:
+		return false
+	}
+}
+
+// legacyMultipleChoice - значение, которым клиент обозначал выбор нескольких вариантов
+const legacyMultipleChoice = "couple"
+
+// LookupQuestionType разбирает тип вопроса из входных данных. Пустое значение означает
+// выбор одного варианта; неизвестные значения не принимаются
+func LookupQuestionType(raw string) (QuestionType, bool) {
+	t := QuestionType(strings.ToLower(strings.TrimSpace(raw)))
+	switch {
+	case t == "":
+		return QuestionTypeOne, true
+	case t == legacyMultipleChoice:
+		return QuestionTypeMultiple, true
+	case t.IsValid():
+		return t, true
+	default:
+		return "", false
+	}
+}
+
+// ParseQuestionType приводит сохраненный тип к известному. Неизвестные значения
+// из старых документов означают выбор нескольких вариантов, как их трактовал клиент
+func ParseQuestionType(raw string) QuestionType {
+	if t, ok := LookupQuestionType(raw); ok {
+		return t
+	}
+	return QuestionTypeMultiple
+}
+
+// HasOptions проверяет, использует ли вопрос этого типа варианты ответа
+// (для матрицы варианты - это столбцы)
+func (t QuestionType) HasOptions() bool {
+	return t != QuestionTypeLikert && t != QuestionTypeText
+}
+
+// CanShuffleOptions проверяет, можно ли перемешивать варианты вопроса этого типа.
+// Столбцы матрицы обычно образуют шкалу, и их порядок не меняется
+func (t QuestionType) CanShuffleOptions() bool {
+	return t == QuestionTypeOne || t == QuestionTypeMultiple || t == QuestionTypeRanking
+}
+
+// LikertScale - настройки шкалы Лайкерта: допустимые значения [Min, Max] и подписи краев
+type LikertScale struct {
+	Min      int
+	Max      int
+	MinLabel string
+	MaxLabel string
+}
+
+// Contains проверяет, что значение входит в шкалу
+func (s LikertScale) Contains(value int) bool {
+	return value >= s.Min && value <= s.Max
+}
+
+// MatrixRow - строка матричного вопроса
+type MatrixRow struct {
+	ID   int
+	Body string
+}
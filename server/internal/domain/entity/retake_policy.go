@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// RetakeMode определяет, можно ли проходить тест повторно
+type RetakeMode string
+
+const (
+	RetakeUnlimited RetakeMode = "unlimited" // без ограничений
+	RetakeOnce      RetakeMode = "once"      // только один раз
+	RetakeCooldown  RetakeMode = "cooldown"  // повторно - не раньше чем через CooldownDays дней
+)
+
+// IsValid проверяет, известен ли режим повторного прохождения
+func (m RetakeMode) IsValid() bool {
+	switch m {
+	case RetakeUnlimited, RetakeOnce, RetakeCooldown:
+		return true
+	default:
+		return false
+	}
+}
+
+// RetakePolicy - правило повторного прохождения теста. Нулевое значение - без ограничений
+type RetakePolicy struct {
+	Mode         RetakeMode
+	CooldownDays int // только для RetakeCooldown
+}
+
+// IsUnlimited проверяет, что повторное прохождение не ограничено
+func (p RetakePolicy) IsUnlimited() bool {
+	return p.Mode == "" || p.Mode == RetakeUnlimited
+}
+
+// NextAttemptAt возвращает момент, начиная с которого тест, последний раз пройденный
+// в lastAt, можно пройти снова. Если повторное прохождение запрещено, возвращает false
+func (p RetakePolicy) NextAttemptAt(lastAt time.Time) (time.Time, bool) {
+	switch p.Mode {
+	case RetakeOnce:
+		return time.Time{}, false
+	case RetakeCooldown:
+		return lastAt.AddDate(0, 0, p.CooldownDays), true
+	default:
+		return lastAt, true
+	}
+}
@@ -45,3 +45,8 @@ func (r *Review) IsApproved() bool {
 func (r *Review) IsModeration() bool {
 	return r.Status == ReviewStatusModeration
 }
+
+// IsOwnedBy проверяет, является ли пользователь автором отзыва
+func (r *Review) IsOwnedBy(userID UserID) bool {
+	return !userID.IsEmpty() && r.UserID == userID
+}
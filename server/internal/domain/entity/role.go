@@ -0,0 +1,77 @@
+package entity
+
+// Role описывает роль пользователя, от которой зависят его права
+type Role string
+
+const (
+	RoleUser         Role = "user"
+	RolePsychologist Role = "psychologist"
+	RoleAdmin        Role = "admin"
+)
+
+// Permission описывает отдельное право на действие в системе
+type Permission string
+
+const (
+	PermissionTestsTake           Permission = "tests:take"
+	PermissionTestsWrite          Permission = "tests:write"
+	PermissionTestsReview         Permission = "tests:review"
+	PermissionTestsImport         Permission = "tests:import"
+	PermissionTestsManageAll      Permission = "tests:manage-all"
+	PermissionReviewsWrite        Permission = "reviews:write"
+	PermissionReviewsModerate     Permission = "reviews:moderate"
+	PermissionRecommendationsEdit Permission = "recommendations:edit"
+	PermissionAnswersReadAll      Permission = "answers:read-all"
+	PermissionUsersManage         Permission = "users:manage"
+	PermissionTerminal            Permission = "dashboard:terminal"
+)
+
+// rolePermissions - декларативная таблица прав каждой роли
+var rolePermissions = map[Role][]Permission{
+	RoleUser: {
+		PermissionTestsTake,
+		PermissionReviewsWrite,
+	},
+	RolePsychologist: {
+		PermissionTestsTake,
+		PermissionReviewsWrite,
+		PermissionTestsWrite,
+		PermissionRecommendationsEdit,
+	},
+	RoleAdmin: {
+		PermissionTestsTake,
+		PermissionReviewsWrite,
+		PermissionTestsWrite,
+		PermissionTestsReview,
+		PermissionTestsImport,
+		PermissionTestsManageAll,
+		PermissionRecommendationsEdit,
+		PermissionReviewsModerate,
+		PermissionAnswersReadAll,
+		PermissionUsersManage,
+		PermissionTerminal,
+	},
+}
+
+// IsValid проверяет, что роль известна системе
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Has проверяет, входит ли право в набор прав роли
+func (r Role) Has(permission Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions возвращает список прав роли
+func (r Role) Permissions() []Permission {
+	permissions := make([]Permission, len(rolePermissions[r]))
+	copy(permissions, rolePermissions[r])
+	return permissions
+}
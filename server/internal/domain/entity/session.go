@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// SessionID представляет уникальный идентификатор сессии пользователя
+type SessionID string
+
+func (id SessionID) String() string { return string(id) }
+func (id SessionID) IsEmpty() bool  { return id == "" }
+
+// Session - сессия пользователя, к которой привязан выданный токен доступа
+type Session struct {
+	ID        SessionID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// IsExpired проверяет, истек ли срок действия сессии
+func (s *Session) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
@@ -1,16 +1,21 @@
 package entity
 
+import "time"
+
 // TestID представляет уникальный идентификатор теста
 type TestID string
 
 func (id TestID) String() string { return string(id) }
 func (id TestID) IsEmpty() bool  { return id == "" }
 
-// TestStatus описывает статус теста
+// TestStatus описывает статус теста. Переходы между статусами описаны в test_lifecycle.go
 type TestStatus string
 
 const (
+	TestStatusDraft     TestStatus = "Черновик"
+	TestStatusReview    TestStatus = "На проверке"
 	TestStatusPublished TestStatus = "Выложен"
+	TestStatusArchived  TestStatus = "В архиве"
 	TestStatusDeleted   TestStatus = "Удален"
 )
 
@@ -21,31 +26,150 @@ type Test struct {
 	AuthorsName   []string
 	QuestionCount int
 	Description   string
+	Category      string   // раздел каталога; пусто - без раздела
+	Tags          []string // метки для поиска в каталоге, в нижнем регистре
 	Date          string
 	Status        TestStatus
-	UserID        UserID // ID создателя теста
+	UserID        UserID     // ID владельца теста: создателя или получателя владения
+	IsTyping      bool       // результат теста определяет психотип пользователя
+	Version       int        // номер текущей версии вопросов
+	PublishAt     *time.Time // начало показа опубликованного теста; nil - сразу
+	UnpublishAt   *time.Time // окончание показа опубликованного теста; nil - без ограничения
+	ReviewComment string     // причина последнего отклонения при проверке
+	Retake        RetakePolicy
+	Collaborators []Collaborator // соавторы, приглашенные владельцем
+	ClonedFrom    *TestOrigin    // тест, копией которого создан этот; nil - создан с нуля
+}
+
+// TestOrigin - исходный тест и версия его вопросов, с которых снята копия
+type TestOrigin struct {
+	TestID  TestID
+	Version int
 }
 
-// Question - вопрос теста
+// FirstTestVersion - номер первой версии теста. Документы вопросов, созданные до
+// появления версий, считаются первой версией
+const FirstTestVersion = 1
+
+// Question - вопрос теста. Набор заполненных полей зависит от типа SelectType
 type Question struct {
 	ID            int
 	QuestionBody  string
-	AnswerOptions []AnswerOption
-	SelectType    string
+	AnswerOptions []AnswerOption // для матрицы - столбцы
+	SelectType    QuestionType
+	Likert        *LikertScale        // только для QuestionTypeLikert
+	Rows          []MatrixRow         // только для QuestionTypeMatrix
+	MaxLength     int                 // только для QuestionTypeText; 0 - без ограничения
+	Weights       map[ScaleID]float64 // только для QuestionTypeLikert: баллы шкал за единицу значения
+	Branches      []BranchRule        // только для one и multiple: переходы после ответа
 }
 
 // AnswerOption - вариант ответа на вопрос
 type AnswerOption struct {
-	ID   int
-	Body string
+	ID      int
+	Body    string
+	Weights map[ScaleID]float64 // баллы, которые выбор варианта добавляет к шкалам
+}
+
+// ScaleID представляет идентификатор шкалы теста
+type ScaleID string
+
+// Scale - шкала, по которой подсчитываются баллы теста
+type Scale struct {
+	ID          ScaleID
+	Name        string
+	Description string
+	Bands       []ScoreBand
+}
+
+// ScoreBand - диапазон баллов шкалы [Min, Max] с меткой и текстом интерпретации.
+// Если с Max начинается соседний диапазон, граница относится к соседнему диапазону
+type ScoreBand struct {
+	Min   float64
+	Max   float64
+	Label string
+	Text  string
+}
+
+// RuleCondition - условие правила интерпретации. Для шкалы ScaleID проверяются
+// все заданные ограничения: диапазон (Band), границы (Min/Max), превосходство
+// над другой шкалой (Above) и принадлежность к ведущим шкалам (Dominant)
+type RuleCondition struct {
+	ScaleID  ScaleID
+	Band     string
+	Min      *float64
+	Max      *float64
+	Above    ScaleID
+	Dominant bool
+}
+
+// ResultRule - правило интерпретации для сочетания шкал. Срабатывает, когда
+// выполнены все условия; правила с большим Priority проверяются раньше
+type ResultRule struct {
+	ID         string
+	Conditions []RuleCondition
+	Text       string
+	Priority   int
+}
+
+// RuleMode определяет, сколько сработавших правил попадает в результат
+type RuleMode string
+
+const (
+	RuleModeFirst RuleMode = "first" // только первое по приоритету
+	RuleModeAll   RuleMode = "all"   // все сработавшие
+)
+
+// TieBreak определяет выбор ведущей шкалы при равенстве баллов
+type TieBreak string
+
+const (
+	TieBreakOrder TieBreak = "order" // побеждает шкала, объявленная раньше
+	TieBreakAll   TieBreak = "all"   // ведущими считаются все шкалы с максимумом
+	TieBreakNone  TieBreak = "none"  // при равенстве ведущей шкалы нет
+)
+
+// ResultsLogic - правила подсчета и интерпретации результатов теста
+type ResultsLogic struct {
+	Scales   []Scale
+	Rules    []ResultRule
+	RuleMode RuleMode
+	TieBreak TieBreak
 }
 
 // QuestionsDocument - документ с вопросами теста
 type QuestionsDocument struct {
 	ID           TestID
 	Questions    []Question
-	ResultsLogic string
+	ResultsLogic ResultsLogic
 	TestingID    TestID
+	Version      int       // версия неизменяема: каждое изменение теста создает новую
+	CreatedAt    time.Time // время создания версии; пусто для документов до появления версий
+	TimeLimits   TimeLimits
+	Shuffle      ShuffleSettings
+}
+
+// TimeLimits - ограничения времени прохождения теста. Нулевое значение - без ограничения
+type TimeLimits struct {
+	Total       time.Duration // на всю попытку, от ее начала
+	PerQuestion time.Duration // на ответ на один вопрос
+}
+
+// IsSet проверяет, задано ли хотя бы одно ограничение времени
+func (l TimeLimits) IsSet() bool {
+	return l.Total > 0 || l.PerQuestion > 0
+}
+
+// ShuffleSettings - перемешивание при прохождении: порядок вопросов и порядок вариантов
+// ответа выбираются случайно для каждой попытки
+type ShuffleSettings struct {
+	Questions bool
+	Options   bool
+}
+
+// IsSet проверяет, включено ли перемешивание
+func (s ShuffleSettings) IsSet() bool {
+	return s.Questions || s.Options
 }
 
 // TestWithCompletion - тест с флагом завершения пользователем
@@ -54,6 +178,91 @@ type TestWithCompletion struct {
 	IsCompleted bool
 }
 
+// IsEmpty проверяет, заданы ли правила подсчета результатов
+func (l *ResultsLogic) IsEmpty() bool {
+	return len(l.Scales) == 0
+}
+
+// Scale возвращает шкалу по идентификатору
+func (l *ResultsLogic) Scale(id ScaleID) (Scale, bool) {
+	for _, scale := range l.Scales {
+		if scale.ID == id {
+			return scale, true
+		}
+	}
+	return Scale{}, false
+}
+
+// Band возвращает диапазон шкалы, в который попадает количество баллов. Смежные
+// диапазоны [0, 10] и [10, 20] делят шкалу без пропусков: 10 баллов относятся ко второму
+func (s *Scale) Band(score float64) (ScoreBand, bool) {
+	for i, band := range s.Bands {
+		if score < band.Min || score > band.Max {
+			continue
+		}
+		if score < band.Max || !s.bandStartsAt(band.Max, i) {
+			return band, true
+		}
+	}
+	return ScoreBand{}, false
+}
+
+// bandStartsAt проверяет, начинается ли с указанного значения диапазон, отличный от skip
+func (s *Scale) bandStartsAt(value float64, skip int) bool {
+	for i, band := range s.Bands {
+		if i != skip && band.Min == value {
+			return true
+		}
+	}
+	return false
+}
+
+// HasBand проверяет, есть ли у шкалы диапазон с указанной меткой
+func (s *Scale) HasBand(label string) bool {
+	for _, band := range s.Bands {
+		if band.Label == label {
+			return true
+		}
+	}
+	return false
+}
+
+// Question возвращает вопрос по идентификатору
+func (d *QuestionsDocument) Question(id int) (Question, bool) {
+	for _, question := range d.Questions {
+		if question.ID == id {
+			return question, true
+		}
+	}
+	return Question{}, false
+}
+
+// Option возвращает вариант ответа по идентификатору
+func (q *Question) Option(id int) (AnswerOption, bool) {
+	for _, option := range q.AnswerOptions {
+		if option.ID == id {
+			return option, true
+		}
+	}
+	return AnswerOption{}, false
+}
+
+// Row возвращает строку матричного вопроса по идентификатору
+func (q *Question) Row(id int) (MatrixRow, bool) {
+	for _, row := range q.Rows {
+		if row.ID == id {
+			return row, true
+		}
+	}
+	return MatrixRow{}, false
+}
+
+// IsDraft проверяет, является ли тест черновиком. Изменять можно только черновик:
+// опубликованный тест показывается в проверенном виде
+func (t *Test) IsDraft() bool {
+	return t.Status == TestStatusDraft
+}
+
 // IsPublished проверяет, опубликован ли тест
 func (t *Test) IsPublished() bool {
 	return t.Status == TestStatusPublished
@@ -63,3 +272,22 @@ func (t *Test) IsPublished() bool {
 func (t *Test) IsDeleted() bool {
 	return t.Status == TestStatusDeleted
 }
+
+// IsAvailable проверяет, что тест опубликован и момент now входит в период показа
+func (t *Test) IsAvailable(now time.Time) bool {
+	if !t.IsPublished() {
+		return false
+	}
+	if t.PublishAt != nil && now.Before(*t.PublishAt) {
+		return false
+	}
+	if t.UnpublishAt != nil && !now.Before(*t.UnpublishAt) {
+		return false
+	}
+	return true
+}
+
+// IsOwnedBy проверяет, является ли пользователь владельцем теста
+func (t *Test) IsOwnedBy(userID UserID) bool {
+	return !userID.IsEmpty() && t.UserID == userID
+}
@@ -0,0 +1,74 @@
+package entity
+
+import "time"
+
+// CollaboratorRole описывает права соавтора теста
+type CollaboratorRole string
+
+const (
+	CollaboratorEditor CollaboratorRole = "editor" // редактирует тест и переводит его по жизненному циклу
+	CollaboratorViewer CollaboratorRole = "viewer" // видит неопубликованный тест и выгружает его
+)
+
+// IsValid проверяет, что роль соавтора известна системе
+func (r CollaboratorRole) IsValid() bool {
+	return r == CollaboratorEditor || r == CollaboratorViewer
+}
+
+// Collaborator - соавтор теста, приглашенный владельцем
+type Collaborator struct {
+	UserID  UserID
+	Role    CollaboratorRole
+	AddedAt time.Time
+}
+
+// TestAccess - уровень доступа пользователя к тесту. Уровни упорядочены:
+// каждый следующий включает права предыдущего
+type TestAccess int
+
+const (
+	TestAccessNone  TestAccess = iota
+	TestAccessView             // просмотр неопубликованного теста и выгрузка
+	TestAccessEdit             // изменение теста и его статуса
+	TestAccessOwner            // удаление, соавторы и передача владения
+)
+
+// String возвращает название уровня доступа
+func (a TestAccess) String() string {
+	switch a {
+	case TestAccessView:
+		return "viewer"
+	case TestAccessEdit:
+		return "editor"
+	case TestAccessOwner:
+		return "owner"
+	default:
+		return "none"
+	}
+}
+
+// Collaborator находит соавтора теста по ID пользователя
+func (t *Test) Collaborator(userID UserID) (Collaborator, bool) {
+	for _, collaborator := range t.Collaborators {
+		if collaborator.UserID == userID {
+			return collaborator, true
+		}
+	}
+	return Collaborator{}, false
+}
+
+// AccessFor возвращает уровень доступа пользователя к тесту. Пользователь с правом
+// tests:manage-all распоряжается любым тестом как владелец
+func (t *Test) AccessFor(user User) TestAccess {
+	if t.IsOwnedBy(user.ID) || user.HasPermission(PermissionTestsManageAll) {
+		return TestAccessOwner
+	}
+	collaborator, ok := t.Collaborator(user.ID)
+	if !ok || user.ID.IsEmpty() {
+		return TestAccessNone
+	}
+	if collaborator.Role == CollaboratorEditor {
+		return TestAccessEdit
+	}
+	return TestAccessView
+}
@@ -0,0 +1,59 @@
+package entity
+
+// TestAction - действие, переводящее тест из одного статуса в другой
+type TestAction string
+
+const (
+	TestActionSubmit   TestAction = "submit"   // черновик отправляется на проверку
+	TestActionWithdraw TestAction = "withdraw" // тест отзывается с проверки в черновики
+	TestActionApprove  TestAction = "approve"  // проверенный тест публикуется
+	TestActionReject   TestAction = "reject"   // тест возвращается автору с комментарием
+	TestActionSchedule TestAction = "schedule" // меняется период показа опубликованного теста
+	TestActionArchive  TestAction = "archive"  // тест скрывается из списка, результаты сохраняются
+	TestActionRestore  TestAction = "restore"  // тест из архива возвращается в черновики
+)
+
+// testTransition - допустимый переход: исходные статусы, итоговый статус
+// и требуется ли право tests:review (иначе действие доступно и создателю теста)
+type testTransition struct {
+	from          []TestStatus
+	to            TestStatus
+	reviewersOnly bool
+}
+
+// testTransitions - декларативная таблица жизненного цикла теста
+var testTransitions = map[TestAction]testTransition{
+	TestActionSubmit:   {from: []TestStatus{TestStatusDraft}, to: TestStatusReview},
+	TestActionWithdraw: {from: []TestStatus{TestStatusReview}, to: TestStatusDraft},
+	TestActionApprove:  {from: []TestStatus{TestStatusReview}, to: TestStatusPublished, reviewersOnly: true},
+	TestActionReject:   {from: []TestStatus{TestStatusReview}, to: TestStatusDraft, reviewersOnly: true},
+	TestActionSchedule: {from: []TestStatus{TestStatusPublished}, to: TestStatusPublished, reviewersOnly: true},
+	TestActionArchive:  {from: []TestStatus{TestStatusPublished}, to: TestStatusArchived},
+	TestActionRestore:  {from: []TestStatus{TestStatusArchived}, to: TestStatusDraft},
+}
+
+// IsValid проверяет, что действие известно системе
+func (a TestAction) IsValid() bool {
+	_, ok := testTransitions[a]
+	return ok
+}
+
+// ReviewersOnly проверяет, требует ли действие права tests:review
+func (a TestAction) ReviewersOnly() bool {
+	return testTransitions[a].reviewersOnly
+}
+
+// Apply возвращает статус теста после действия; false, если из статуса from
+// действие недопустимо
+func (a TestAction) Apply(from TestStatus) (TestStatus, bool) {
+	transition, ok := testTransitions[a]
+	if !ok {
+		return "", false
+	}
+	for _, status := range transition.from {
+		if status == from {
+			return transition.to, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// TestSort - порядок тестов в результатах поиска по каталогу
+type TestSort string
+
+const (
+	TestSortRelevance TestSort = "relevance" // по соответствию текстовому запросу
+	TestSortNewest    TestSort = "newest"    // сначала недавно созданные
+	TestSortName      TestSort = "name"      // по названию
+)
+
+// IsValid проверяет, что порядок сортировки известен
+func (s TestSort) IsValid() bool {
+	switch s {
+	case TestSortRelevance, TestSortNewest, TestSortName:
+		return true
+	}
+	return false
+}
+
+// TestCursor - положение последнего теста страницы, с которого продолжается выдача.
+// Name заполняется при сортировке по названию, Score - при сортировке по релевантности
+type TestCursor struct {
+	ID    TestID
+	Name  string
+	Score float64
+}
+
+// TestSearch - условия поиска опубликованных тестов в каталоге. Пустые условия
+// не ограничивают выдачу; тест должен содержать все метки из Tags.
+// IncludeIDs ограничивает выдачу перечисленными тестами, ExcludeIDs исключает тесты
+type TestSearch struct {
+	Query      string
+	Category   string
+	Tags       []string
+	IncludeIDs []TestID
+	ExcludeIDs []TestID
+	Sort       TestSort
+	After      *TestCursor
+	Limit      int
+	Now        time.Time // момент, на который проверяется период показа
+}
+
+// TestSearchPage - страница результатов поиска. Next - положение для следующей
+// страницы; nil, если тестов больше нет
+type TestSearchPage struct {
+	Tests []Test
+	Next  *TestCursor
+}
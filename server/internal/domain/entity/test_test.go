@@ -0,0 +1,36 @@
+package entity
+
+import "testing"
+
+func TestScaleBand(t *testing.T) {
+	scale := Scale{Bands: []ScoreBand{
+		{Min: 0, Max: 10, Label: "low"},
+		{Min: 10, Max: 20, Label: "mid"},
+		{Min: 25, Max: 30, Label: "high"},
+		{Min: 30, Max: 30, Label: "max"},
+	}}
+
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{score: -1, want: ""},
+		{score: 0, want: "low"},
+		{score: 9.99, want: "low"},
+		{score: 10, want: "mid"},
+		{score: 10.5, want: "mid"},
+		{score: 20, want: "mid"},
+		{score: 22, want: ""},
+		{score: 25, want: "high"},
+		{score: 29.5, want: "high"},
+		{score: 30, want: "max"},
+		{score: 31, want: ""},
+	}
+
+	for _, tt := range tests {
+		band, ok := scale.Band(tt.score)
+		if ok != (tt.want != "") || band.Label != tt.want {
+			t.Errorf("Band(%v) = %q, %v; want %q", tt.score, band.Label, ok, tt.want)
+		}
+	}
+}
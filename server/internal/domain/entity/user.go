@@ -1,6 +1,10 @@
 package entity
 
-import domainErrors "server/internal/domain/errors"
+import (
+	"time"
+
+	domainErrors "server/internal/domain/errors"
+)
 
 // UserID представляет уникальный идентификатор пользователя
 type UserID string
@@ -25,17 +29,38 @@ type User struct {
 	LastName      string
 	Email         string
 	Status        UserStatus
+	Role          Role
 	Password      string
 	PsychoType    string
 	Date          string
 	IsGoogleAdded bool
 	IsYandexAdded bool
-	Sessions      []interface{}
+	Sessions      []Session
+
+	ExternalAccounts  []ExternalAccount
+	PsychoTypeHistory []PsychoTypeAssignment // от старых записей к новым; последняя совпадает с PsychoType
+}
+
+// EffectiveRole возвращает роль пользователя. Для документов, созданных до
+// появления ролей, роль выводится из статуса администратора
+func (u *User) EffectiveRole() Role {
+	if u.Role.IsValid() {
+		return u.Role
+	}
+	if u.Status == UserStatusAdmin {
+		return RoleAdmin
+	}
+	return RoleUser
 }
 
 // IsAdmin проверяет, является ли пользователь администратором
 func (u *User) IsAdmin() bool {
-	return u.Status == UserStatusAdmin
+	return u.EffectiveRole() == RoleAdmin
+}
+
+// HasPermission проверяет, есть ли у пользователя указанное право
+func (u *User) HasPermission(permission Permission) bool {
+	return u.IsActive() && u.EffectiveRole().Has(permission)
 }
 
 // IsActive проверяет, активен ли аккаунт пользователя
@@ -43,16 +68,78 @@ func (u *User) IsActive() bool {
 	return u.Status == UserStatusAdmin || u.Status == UserStatusUser
 }
 
-// CanLogin проверяет возможность входа в систему и возвращает соответствующую ошибку
-func (u *User) CanLogin(password string) error {
+// CanLogin проверяет, что статус пользователя допускает вход в систему.
+// Пароль проверяется отдельно через service.PasswordHasher
+func (u *User) CanLogin() error {
 	if u.Status == UserStatusDeleted {
 		return domainErrors.ErrUserDeleted
 	}
 	if u.Status == UserStatusBlocked {
 		return domainErrors.ErrUserBlocked
 	}
-	if u.Password != password {
-		return domainErrors.ErrWrongPassword
-	}
 	return nil
 }
+
+// HasPassword проверяет, может ли пользователь войти по паролю
+func (u *User) HasPassword() bool {
+	return u.Password != ""
+}
+
+// ExternalAccount возвращает привязанную учетную запись указанного провайдера
+func (u *User) ExternalAccount(provider OAuthProvider) (ExternalAccount, bool) {
+	for _, account := range u.ExternalAccounts {
+		if account.Provider == provider {
+			return account, true
+		}
+	}
+	return ExternalAccount{}, false
+}
+
+// IsProviderLinked проверяет, привязан ли к пользователю внешний провайдер.
+// Учитываются и флаги документов, созданных до хранения привязок
+func (u *User) IsProviderLinked(provider OAuthProvider) bool {
+	if _, ok := u.ExternalAccount(provider); ok {
+		return true
+	}
+	switch provider {
+	case OAuthProviderGoogle:
+		return u.IsGoogleAdded
+	case OAuthProviderYandex:
+		return u.IsYandexAdded
+	default:
+		return false
+	}
+}
+
+// CanUnlink проверяет, останется ли у пользователя способ входа после отвязки провайдера
+func (u *User) CanUnlink(provider OAuthProvider) bool {
+	if u.HasPassword() {
+		return true
+	}
+	for _, account := range u.ExternalAccounts {
+		if account.Provider != provider {
+			return true
+		}
+	}
+	return false
+}
+
+// HasExpiredSessions проверяет, есть ли у пользователя истекшие сессии
+func (u *User) HasExpiredSessions(now time.Time) bool {
+	for _, session := range u.Sessions {
+		if session.IsExpired(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasActiveSession проверяет, что у пользователя есть действующая сессия с указанным ID
+func (u *User) HasActiveSession(id SessionID, now time.Time) bool {
+	for _, session := range u.Sessions {
+		if session.ID == id {
+			return !session.IsExpired(now)
+		}
+	}
+	return false
+}
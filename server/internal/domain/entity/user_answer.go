@@ -1,23 +1,76 @@
 package entity
 
+import "time"
+
 // UserAnswerID представляет уникальный идентификатор ответа пользователя
 type UserAnswerID string
 
 func (id UserAnswerID) String() string { return string(id) }
 func (id UserAnswerID) IsEmpty() bool  { return id == "" }
 
-// UserAnswer - ответ пользователя на тест
+// ScaleScore - количество баллов, набранных по шкале, и диапазон, в который они попали
+type ScaleScore struct {
+	ScaleID  ScaleID
+	Name     string
+	Score    float64
+	Band     string
+	BandText string
+}
+
+// UserAnswer - ответ пользователя на тест. Result (текст интерпретации), Scores,
+// Dominant и MatchedRules вычисляются сервером
 type UserAnswer struct {
-	ID     UserAnswerID
-	UserID UserID
-	TestID TestID
-	Result string
-	Date   string
+	ID           UserAnswerID
+	UserID       UserID
+	TestID       TestID
+	TestVersion  int // версия теста, по которой пройдена попытка
+	Result       string
+	Scores       []ScaleScore
+	Dominant     []ScaleID
+	MatchedRules []string
+	Date         string
+	CompletedAt  time.Time // время сохранения попытки сервером
 }
 
 // UserAnswerDetails - детальные ответы пользователя на вопросы
 type UserAnswerDetails struct {
 	ID              UserAnswerID
 	TestingAnswerID UserAnswerID
-	Answers         [][]int
+	Answers         []QuestionAnswer
+}
+
+// QuestionAnswer - ответ на один вопрос. Заполняются поля, соответствующие типу вопроса
+type QuestionAnswer struct {
+	QuestionID int
+	OptionIDs  []int         // one и multiple - выбранные варианты; ranking - варианты по порядку
+	Value      *int          // likert
+	Text       string        // text
+	Cells      []MatrixCell  // matrix
+	TimeSpent  time.Duration // время ответа, измеренное сервером; 0 - неизвестно
+}
+
+// MatrixCell - выбранный столбец в строке матричного вопроса
+type MatrixCell struct {
+	RowID    int
+	OptionID int
+}
+
+// QuestionAnswerFromRow разбирает ответ в прежнем формате [ID вопроса, ID варианта...]
+func QuestionAnswerFromRow(row []int) QuestionAnswer {
+	if len(row) == 0 {
+		return QuestionAnswer{}
+	}
+	return QuestionAnswer{
+		QuestionID: row[0],
+		OptionIDs:  append([]int(nil), row[1:]...),
+	}
+}
+
+// Row возвращает ответ в прежнем формате [ID вопроса, ID варианта...].
+// Для ответов без вариантов (шкала, текст, матрица) возвращает false
+func (a QuestionAnswer) Row() ([]int, bool) {
+	if len(a.OptionIDs) == 0 {
+		return nil, false
+	}
+	return append([]int{a.QuestionID}, a.OptionIDs...), true
 }
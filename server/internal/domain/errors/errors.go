@@ -22,9 +22,40 @@ var (
 	ErrInvalidID = errors.New("invalid id")
 )
 
+// Auth errors
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// OAuth errors
+var (
+	ErrProviderNotConfigured = errors.New("oauth provider not configured")
+	ErrProviderExchange      = errors.New("oauth provider exchange failed")
+	ErrEmailNotVerified      = errors.New("provider email not verified")
+	ErrAccountLinked         = errors.New("provider account linked to another user")
+	ErrLastLoginMethod       = errors.New("last login method")
+)
+
 // Test errors
 var (
-	ErrNoQuestions = errors.New("no questions")
+	ErrNoQuestions     = errors.New("no questions")
+	ErrInvalidScoring  = errors.New("invalid scoring rules")
+	ErrInvalidAnswers  = errors.New("invalid answers")
+	ErrTestUnavailable = errors.New("test unavailable")
+	ErrTestTransition  = errors.New("invalid test status transition")
+	ErrTestNotEditable = errors.New("test is not a draft")
+	ErrAttemptClosed   = errors.New("attempt submitted or expired")
+	ErrTimeLimit       = errors.New("time limit exceeded")
+	ErrRetakeDenied    = errors.New("retake not allowed")
+	ErrOwnerChanged    = errors.New("test owner changed")
+	ErrVersionExists   = errors.New("test version already exists")
+)
+
+// Norm errors
+var (
+	ErrNormFrozen      = errors.New("norm table already frozen")
+	ErrEmptyNormSample = errors.New("no attempts for norms")
 )
 
 // Review errors
@@ -0,0 +1,45 @@
+package errors
+
+import "strings"
+
+// ValidationError - ошибка валидации с пояснением, которое можно показать пользователю.
+// Сравнивается через errors.Is с базовой ошибкой Err. Fields содержит ошибки
+// отдельных полей запроса, если их можно указать
+type ValidationError struct {
+	Err     error
+	Message string
+	Fields  []FieldError
+}
+
+// FieldError - ошибка валидации конкретного поля запроса.
+// Field - путь к полю в запросе, например "answers[2].optionIds[1]"
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// NewValidationError создает ошибку валидации с пояснением
+func NewValidationError(err error, message string) error {
+	return &ValidationError{Err: err, Message: message}
+}
+
+// NewFieldValidationError создает ошибку валидации с ошибками отдельных полей
+func NewFieldValidationError(err error, message string, fields []FieldError) error {
+	return &ValidationError{Err: err, Message: message, Fields: fields}
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Err.Error() + ": " + e.Message
+	}
+
+	parts := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		parts = append(parts, field.Field+": "+field.Message)
+	}
+	return e.Err.Error() + ": " + e.Message + " (" + strings.Join(parts, "; ") + ")"
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
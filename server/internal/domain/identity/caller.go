@@ -0,0 +1,39 @@
+package identity
+
+import (
+	"context"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+// Caller - пользователь, от имени которого выполняется запрос
+type Caller struct {
+	User      entity.User
+	SessionID entity.SessionID
+}
+
+type callerKey struct{}
+
+// WithCaller возвращает контекст, содержащий данные вызывающего пользователя
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext извлекает вызывающего пользователя из контекста
+func CallerFromContext(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(Caller)
+	if !ok || caller.User.ID.IsEmpty() {
+		return Caller{}, false
+	}
+	return caller, true
+}
+
+// RequireCaller извлекает вызывающего пользователя или возвращает ErrUnauthorized
+func RequireCaller(ctx context.Context) (Caller, error) {
+	caller, ok := CallerFromContext(ctx)
+	if !ok {
+		return Caller{}, domainErrors.ErrUnauthorized
+	}
+	return caller, nil
+}
@@ -0,0 +1,167 @@
+// Package norms строит таблицы норм по сохраненным попыткам и определяет положение
+// балла шкалы относительно выборки: процентильный ранг и стэнайн
+package norms
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"server/internal/domain/entity"
+)
+
+// scoreEpsilon - допуск при сравнении баллов: баллы - суммы дробных весов
+const scoreEpsilon = 1e-9
+
+// stanineBounds - верхние границы стэнайнов 1-8 в процентах выборки (4-7-12-17-20-17-12-7-4)
+var stanineBounds = []float64{4, 11, 23, 40, 60, 77, 89, 96}
+
+// Includes проверяет, попадает ли попытка в выборку по отбору filter. Дата попытки
+// определяется по времени завершения, а для старых записей - по дате прохождения
+func Includes(filter entity.NormFilter, answer entity.UserAnswer) bool {
+	if len(filter.UserIDs) > 0 {
+		member := false
+		for _, userID := range filter.UserIDs {
+			if userID == answer.UserID {
+				member = true
+				break
+			}
+		}
+		if !member {
+			return false
+		}
+	}
+
+	if filter.From == nil && filter.To == nil {
+		return true
+	}
+	completedAt := answer.CompletedAt
+	if completedAt.IsZero() {
+		parsed, err := time.ParseInLocation("02.01.2006", answer.Date, time.Local)
+		if err != nil {
+			return false
+		}
+		completedAt = parsed
+	}
+	if filter.From != nil && completedAt.Before(*filter.From) {
+		return false
+	}
+	if filter.To != nil && completedAt.After(*filter.To) {
+		return false
+	}
+	return true
+}
+
+// Build строит нормы шкал по баллам попыток версии теста testVersion. Попытки других
+// версий пропускаются: вопросы и веса шкал в них могут отличаться. Название шкалы
+// берется из последней попытки, где она встречается
+func Build(testVersion int, answers []entity.UserAnswer) []entity.ScaleNorm {
+	type collected struct {
+		name   string
+		scores []float64
+	}
+	byScale := make(map[entity.ScaleID]*collected)
+	var order []entity.ScaleID
+	for _, answer := range answers {
+		if answer.TestVersion != testVersion {
+			continue
+		}
+		for _, score := range answer.Scores {
+			entry, ok := byScale[score.ScaleID]
+			if !ok {
+				entry = &collected{}
+				byScale[score.ScaleID] = entry
+				order = append(order, score.ScaleID)
+			}
+			entry.name = score.Name
+			entry.scores = append(entry.scores, score.Score)
+		}
+	}
+
+	scales := make([]entity.ScaleNorm, 0, len(order))
+	for _, scaleID := range order {
+		entry := byScale[scaleID]
+		scales = append(scales, scaleNorm(scaleID, entry.name, entry.scores))
+	}
+	return scales
+}
+
+// scaleNorm вычисляет распределение баллов одной шкалы
+func scaleNorm(scaleID entity.ScaleID, name string, scores []float64) entity.ScaleNorm {
+	sorted := append([]float64(nil), scores...)
+	sort.Float64s(sorted)
+
+	norm := entity.ScaleNorm{ScaleID: scaleID, Name: name, Sample: len(sorted)}
+	sum := 0.0
+	for _, score := range sorted {
+		sum += score
+		last := len(norm.Points) - 1
+		if last >= 0 && math.Abs(norm.Points[last].Score-score) <= scoreEpsilon {
+			norm.Points[last].Count++
+			continue
+		}
+		norm.Points = append(norm.Points, entity.NormPoint{Score: score, Count: 1})
+	}
+	norm.Mean = sum / float64(len(sorted))
+
+	if len(sorted) > 1 {
+		squares := 0.0
+		for _, score := range sorted {
+			squares += (score - norm.Mean) * (score - norm.Mean)
+		}
+		norm.StdDev = math.Sqrt(squares / float64(len(sorted)-1))
+	}
+	return norm
+}
+
+// Rank возвращает процентильный ранг и стэнайн балла относительно выборки шкалы.
+// Процентильный ранг - доля попыток с меньшим баллом плюс половина доли попыток
+// с таким же баллом. Возвращает false для пустой выборки
+func Rank(norm entity.ScaleNorm, score float64) (entity.NormScore, bool) {
+	if norm.Sample == 0 {
+		return entity.NormScore{}, false
+	}
+
+	below, equal := 0, 0
+	for _, point := range norm.Points {
+		switch {
+		case math.Abs(point.Score-score) <= scoreEpsilon:
+			equal += point.Count
+		case point.Score < score:
+			below += point.Count
+		}
+	}
+
+	percentile := (float64(below) + float64(equal)/2) / float64(norm.Sample) * 100
+	return entity.NormScore{
+		ScaleID:    norm.ScaleID,
+		Percentile: percentile,
+		Stanine:    Stanine(percentile),
+	}, true
+}
+
+// Stanine переводит процентильный ранг в стэнайн
+func Stanine(percentile float64) int {
+	for index, bound := range stanineBounds {
+		if percentile < bound {
+			return index + 1
+		}
+	}
+	return len(stanineBounds) + 1
+}
+
+// Apply определяет положение баллов попытки по таблице норм. Шкалы, которых нет
+// в таблице, пропускаются
+func Apply(table entity.NormTable, scores []entity.ScaleScore) []entity.NormScore {
+	ranked := make([]entity.NormScore, 0, len(scores))
+	for _, score := range scores {
+		norm, ok := table.Scale(score.ScaleID)
+		if !ok {
+			continue
+		}
+		if rank, ok := Rank(norm, score.Score); ok {
+			ranked = append(ranked, rank)
+		}
+	}
+	return ranked
+}
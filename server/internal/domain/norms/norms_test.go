@@ -0,0 +1,96 @@
+package norms
+
+import (
+	"math"
+	"testing"
+
+	"server/internal/domain/entity"
+)
+
+func TestStanine(t *testing.T) {
+	tests := []struct {
+		percentile float64
+		want       int
+	}{
+		{percentile: 0, want: 1},
+		{percentile: 3.9, want: 1},
+		{percentile: 4, want: 2},
+		{percentile: 22.9, want: 3},
+		{percentile: 50, want: 5},
+		{percentile: 60, want: 6},
+		{percentile: 95.9, want: 8},
+		{percentile: 96, want: 9},
+		{percentile: 100, want: 9},
+	}
+
+	for _, tt := range tests {
+		if got := Stanine(tt.percentile); got != tt.want {
+			t.Errorf("Stanine(%v) = %d; want %d", tt.percentile, got, tt.want)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	// Баллы выборки: 1, 2, 2, 3
+	norm := entity.ScaleNorm{
+		ScaleID: "s",
+		Sample:  4,
+		Points:  []entity.NormPoint{{Score: 1, Count: 1}, {Score: 2, Count: 2}, {Score: 3, Count: 1}},
+	}
+
+	tests := []struct {
+		name           string
+		score          float64
+		wantPercentile float64
+		wantStanine    int
+	}{
+		{name: "ниже выборки", score: 0, wantPercentile: 0, wantStanine: 1},
+		{name: "наименьший балл", score: 1, wantPercentile: 12.5, wantStanine: 3},
+		{name: "повторяющийся балл", score: 2, wantPercentile: 50, wantStanine: 5},
+		{name: "балл с погрешностью суммы весов", score: 2 + 1e-12, wantPercentile: 50, wantStanine: 5},
+		{name: "между баллами", score: 2.5, wantPercentile: 75, wantStanine: 6},
+		{name: "выше выборки", score: 4, wantPercentile: 100, wantStanine: 9},
+	}
+
+	for _, tt := range tests {
+		got, ok := Rank(norm, tt.score)
+		if !ok {
+			t.Fatalf("%s: Rank returned false", tt.name)
+		}
+		if math.Abs(got.Percentile-tt.wantPercentile) > 1e-9 || got.Stanine != tt.wantStanine || got.ScaleID != "s" {
+			t.Errorf("%s: Rank(%v) = %+v; want percentile %v, stanine %d",
+				tt.name, tt.score, got, tt.wantPercentile, tt.wantStanine)
+		}
+	}
+
+	if _, ok := Rank(entity.ScaleNorm{ScaleID: "s"}, 1); ok {
+		t.Error("Rank on empty sample returned true")
+	}
+}
+
+func TestBuild(t *testing.T) {
+	answer := func(version int, score float64) entity.UserAnswer {
+		return entity.UserAnswer{
+			TestVersion: version,
+			Scores:      []entity.ScaleScore{{ScaleID: "s", Name: "Шкала", Score: score}},
+		}
+	}
+	answers := []entity.UserAnswer{answer(2, 3), answer(1, 100), answer(2, 1), answer(2, 3)}
+
+	scales := Build(2, answers)
+	if len(scales) != 1 {
+		t.Fatalf("Build returned %d scales; want 1", len(scales))
+	}
+	norm := scales[0]
+	want := []entity.NormPoint{{Score: 1, Count: 1}, {Score: 3, Count: 2}}
+	if norm.Sample != 3 || len(norm.Points) != len(want) || norm.Points[0] != want[0] || norm.Points[1] != want[1] {
+		t.Errorf("Build = sample %d, points %v; want sample 3, points %v", norm.Sample, norm.Points, want)
+	}
+	if math.Abs(norm.Mean-7.0/3) > 1e-9 {
+		t.Errorf("Mean = %v; want %v", norm.Mean, 7.0/3)
+	}
+
+	if scales := Build(3, answers); len(scales) != 0 {
+		t.Errorf("Build for version without attempts = %v; want none", scales)
+	}
+}
@@ -0,0 +1,384 @@
+// Package psychometrics вычисляет показатели качества теста по сохраненным попыткам:
+// распределение ответов, трудность и дискриминативность вопросов, корреляцию вопроса
+// с остальной шкалой и альфу Кронбаха. Баллы вопросов считаются так же, как в scoring
+package psychometrics
+
+import (
+	"math"
+	"sort"
+
+	"server/internal/domain/entity"
+	"server/internal/domain/scoring"
+)
+
+// extremeGroupShare - доля попыток в группах с высоким и низким итогом при расчете
+// дискриминативности
+const extremeGroupShare = 0.27
+
+// Analysis - результат анализа версии теста
+type Analysis struct {
+	Questions []entity.QuestionStats
+	Scales    []entity.ScaleReliability
+}
+
+// Analyze анализирует попытки по версии теста doc. Каждый элемент attempts - ответы
+// одной попытки; ответы на неизвестные вопросы не учитываются
+func Analyze(doc entity.QuestionsDocument, attempts [][]entity.QuestionAnswer) Analysis {
+	byQuestion := make([]map[int]entity.QuestionAnswer, 0, len(attempts))
+	for _, answers := range attempts {
+		indexed := make(map[int]entity.QuestionAnswer, len(answers))
+		for _, answer := range answers {
+			if _, seen := indexed[answer.QuestionID]; !seen {
+				indexed[answer.QuestionID] = answer
+			}
+		}
+		byQuestion = append(byQuestion, indexed)
+	}
+
+	questions := make([]entity.QuestionStats, 0, len(doc.Questions))
+	for _, question := range doc.Questions {
+		questions = append(questions, questionStats(question, byQuestion))
+	}
+
+	scales := make([]entity.ScaleReliability, 0, len(doc.ResultsLogic.Scales))
+	for _, scale := range doc.ResultsLogic.Scales {
+		scales = append(scales, scaleReliability(scale, doc.Questions, byQuestion))
+	}
+
+	return Analysis{Questions: questions, Scales: scales}
+}
+
+// questionStats подсчитывает распределение ответов на вопрос
+func questionStats(question entity.Question, attempts []map[int]entity.QuestionAnswer) entity.QuestionStats {
+	stats := entity.QuestionStats{QuestionID: question.ID}
+
+	optionCounts := make(map[int]int, len(question.AnswerOptions))
+	valueCounts := make(map[int]int)
+	filledRows := 0
+	for _, answers := range attempts {
+		answer, ok := answers[question.ID]
+		if !ok {
+			continue
+		}
+		stats.Responses++
+
+		switch question.SelectType {
+		case entity.QuestionTypeLikert:
+			if answer.Value != nil {
+				valueCounts[*answer.Value]++
+			}
+		case entity.QuestionTypeRanking:
+			if len(answer.OptionIDs) > 0 {
+				optionCounts[answer.OptionIDs[0]]++
+			}
+		case entity.QuestionTypeMatrix:
+			for _, cell := range answer.Cells {
+				optionCounts[cell.OptionID]++
+				filledRows++
+			}
+		case entity.QuestionTypeText:
+		default:
+			for _, optionID := range answer.OptionIDs {
+				optionCounts[optionID]++
+			}
+		}
+	}
+
+	base := stats.Responses
+	if question.SelectType == entity.QuestionTypeMatrix {
+		base = filledRows
+	}
+
+	if question.SelectType.HasOptions() {
+		stats.Options = make([]entity.ResponseFrequency, 0, len(question.AnswerOptions))
+		for _, option := range question.AnswerOptions {
+			stats.Options = append(stats.Options, frequency(option.ID, option.Body, optionCounts[option.ID], base))
+		}
+	}
+	if question.SelectType == entity.QuestionTypeLikert && question.Likert != nil {
+		stats.Values = make([]entity.ResponseFrequency, 0, question.Likert.Max-question.Likert.Min+1)
+		for value := question.Likert.Min; value <= question.Likert.Max; value++ {
+			label := ""
+			switch value {
+			case question.Likert.Min:
+				label = question.Likert.MinLabel
+			case question.Likert.Max:
+				label = question.Likert.MaxLabel
+			}
+			stats.Values = append(stats.Values, frequency(value, label, valueCounts[value], base))
+		}
+	}
+	return stats
+}
+
+// frequency возвращает частоту выбора с долей от base
+func frequency(key int, label string, count, base int) entity.ResponseFrequency {
+	share := 0.0
+	if base > 0 {
+		share = float64(count) / float64(base)
+	}
+	return entity.ResponseFrequency{Key: key, Label: label, Count: count, Share: share}
+}
+
+// scaleReliability вычисляет показатели шкалы по попыткам с ответами на все ее вопросы
+func scaleReliability(scale entity.Scale, questions []entity.Question, attempts []map[int]entity.QuestionAnswer) entity.ScaleReliability {
+	result := entity.ScaleReliability{ScaleID: scale.ID, Name: scale.Name, Items: []entity.ItemStats{}}
+
+	var items []entity.Question
+	for _, question := range questions {
+		if contributes(question, scale.ID) {
+			items = append(items, question)
+		}
+	}
+	if len(items) == 0 {
+		return result
+	}
+
+	// scores[i][j] - балл вопроса i в попытке j
+	scores := make([][]float64, len(items))
+	var totals []float64
+	for _, answers := range attempts {
+		complete := true
+		for _, item := range items {
+			if _, ok := answers[item.ID]; !ok {
+				complete = false
+				break
+			}
+		}
+		if !complete {
+			continue
+		}
+
+		total := 0.0
+		for i, item := range items {
+			score := scoring.QuestionScores(item, answers[item.ID])[scale.ID]
+			scores[i] = append(scores[i], score)
+			total += score
+		}
+		totals = append(totals, total)
+	}
+
+	result.Respondents = len(totals)
+	if result.Respondents == 0 {
+		for _, item := range items {
+			result.Items = append(result.Items, entity.ItemStats{QuestionID: item.ID})
+		}
+		return result
+	}
+	result.Mean = mean(totals)
+	result.StdDev = math.Sqrt(variance(totals))
+	result.Alpha = cronbachAlpha(scores)
+
+	upper, lower := extremeGroups(totals)
+	for i, item := range items {
+		stats := entity.ItemStats{QuestionID: item.ID, Mean: mean(scores[i])}
+
+		low, high := itemRange(item, scale.ID)
+		if width := high - low; width > 0 {
+			stats.Difficulty = ratio(stats.Mean-low, width)
+			if len(upper) > 0 {
+				stats.Discrimination = ratio(meanAt(scores[i], upper)-meanAt(scores[i], lower), width)
+			}
+		}
+
+		rest := make([]float64, len(totals))
+		for j := range totals {
+			rest[j] = totals[j] - scores[i][j]
+		}
+		stats.ItemTotal = correlation(scores[i], rest)
+
+		if len(items) > 2 {
+			others := make([][]float64, 0, len(items)-1)
+			others = append(others, scores[:i]...)
+			others = append(others, scores[i+1:]...)
+			stats.AlphaIfDeleted = cronbachAlpha(others)
+		}
+		result.Items = append(result.Items, stats)
+	}
+	return result
+}
+
+// contributes проверяет, дает ли вопрос баллы по шкале
+func contributes(question entity.Question, scaleID entity.ScaleID) bool {
+	if question.SelectType == entity.QuestionTypeText {
+		return false
+	}
+	if question.Weights[scaleID] != 0 {
+		return true
+	}
+	for _, option := range question.AnswerOptions {
+		if option.Weights[scaleID] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// itemRange возвращает наименьший и наибольший возможный балл вопроса по шкале
+func itemRange(question entity.Question, scaleID entity.ScaleID) (float64, float64) {
+	weights := make([]float64, 0, len(question.AnswerOptions))
+	for _, option := range question.AnswerOptions {
+		weights = append(weights, option.Weights[scaleID])
+	}
+	sort.Float64s(weights)
+
+	switch question.SelectType {
+	case entity.QuestionTypeLikert:
+		if question.Likert == nil {
+			return 0, 0
+		}
+		weight := question.Weights[scaleID]
+		a, b := float64(question.Likert.Min)*weight, float64(question.Likert.Max)*weight
+		return math.Min(a, b), math.Max(a, b)
+
+	case entity.QuestionTypeRanking:
+		// Первое место дает n очков, последнее - одно: наибольший балл - при тяжелых
+		// весах на первых местах, наименьший - наоборот
+		n := len(weights)
+		low, high := 0.0, 0.0
+		for k := range weights {
+			points := float64(n - k)
+			low += weights[k] * points
+			high += weights[n-1-k] * points
+		}
+		return low, high
+
+	case entity.QuestionTypeMatrix:
+		if len(weights) == 0 {
+			return 0, 0
+		}
+		rows := float64(len(question.Rows))
+		return weights[0] * rows, weights[len(weights)-1] * rows
+
+	case entity.QuestionTypeMultiple:
+		// Выбирается хотя бы один вариант
+		if len(weights) == 0 {
+			return 0, 0
+		}
+		low, high := 0.0, 0.0
+		for _, weight := range weights {
+			low += math.Min(weight, 0)
+			high += math.Max(weight, 0)
+		}
+		if weights[0] > 0 {
+			low = weights[0]
+		}
+		if weights[len(weights)-1] < 0 {
+			high = weights[len(weights)-1]
+		}
+		return low, high
+
+	default:
+		if len(weights) == 0 {
+			return 0, 0
+		}
+		return weights[0], weights[len(weights)-1]
+	}
+}
+
+// extremeGroups возвращает номера попыток с наибольшим и наименьшим итогом
+// (по extremeGroupShare попыток, но не меньше одной). При одной попытке группы пусты
+func extremeGroups(totals []float64) ([]int, []int) {
+	if len(totals) < 2 {
+		return nil, nil
+	}
+	order := make([]int, len(totals))
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(a, b int) bool { return totals[order[a]] < totals[order[b]] })
+
+	size := int(math.Round(extremeGroupShare * float64(len(totals))))
+	if size < 1 {
+		size = 1
+	}
+	return order[len(order)-size:], order[:size]
+}
+
+// cronbachAlpha вычисляет альфу Кронбаха по баллам вопросов. Нужно хотя бы два вопроса,
+// две попытки и ненулевая дисперсия суммы
+func cronbachAlpha(scores [][]float64) *float64 {
+	k := len(scores)
+	if k < 2 || len(scores[0]) < 2 {
+		return nil
+	}
+
+	totals := make([]float64, len(scores[0]))
+	itemVariance := 0.0
+	for _, item := range scores {
+		itemVariance += variance(item)
+		for j, score := range item {
+			totals[j] += score
+		}
+	}
+
+	totalVariance := variance(totals)
+	if totalVariance == 0 {
+		return nil
+	}
+	alpha := float64(k) / float64(k-1) * (1 - itemVariance/totalVariance)
+	return &alpha
+}
+
+// correlation вычисляет коэффициент корреляции Пирсона; без разброса значений не определен
+func correlation(x, y []float64) *float64 {
+	if len(x) < 2 {
+		return nil
+	}
+	mx, my := mean(x), mean(y)
+	var sxy, sxx, syy float64
+	for j := range x {
+		dx, dy := x[j]-mx, y[j]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return nil
+	}
+	r := sxy / math.Sqrt(sxx*syy)
+	return &r
+}
+
+// mean вычисляет среднее значение
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// meanAt вычисляет среднее значение по выбранным номерам
+func meanAt(values []float64, indexes []int) float64 {
+	if len(indexes) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, j := range indexes {
+		sum += values[j]
+	}
+	return sum / float64(len(indexes))
+}
+
+// variance вычисляет выборочную дисперсию (с делением на n-1)
+func variance(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m := mean(values)
+	sum := 0.0
+	for _, value := range values {
+		sum += (value - m) * (value - m)
+	}
+	return sum / float64(len(values)-1)
+}
+
+// ratio возвращает частное как необязательный показатель
+func ratio(numerator, denominator float64) *float64 {
+	value := numerator / denominator
+	return &value
+}
@@ -0,0 +1,132 @@
+package psychometrics
+
+import (
+	"math"
+	"testing"
+
+	"server/internal/domain/entity"
+)
+
+const tolerance = 1e-9
+
+// equalOptional сравнивает необязательный показатель с ожидаемым; nil - показатель не определен
+func equalOptional(got, want *float64) bool {
+	if got == nil || want == nil {
+		return got == nil && want == nil
+	}
+	return math.Abs(*got-*want) <= tolerance
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestCronbachAlpha(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores [][]float64
+		want   *float64
+	}{
+		{name: "согласованные вопросы", scores: [][]float64{{1, 2, 3}, {1, 2, 3}}, want: floatPtr(1)},
+		{name: "три вопроса", scores: [][]float64{{1, 2, 3, 4}, {2, 1, 4, 3}, {1, 1, 2, 2}}, want: floatPtr(0.84)},
+		{name: "один вопрос", scores: [][]float64{{1, 2, 3}}, want: nil},
+		{name: "одна попытка", scores: [][]float64{{1}, {2}}, want: nil},
+		{name: "нулевая дисперсия суммы", scores: [][]float64{{1, 2, 3}, {3, 2, 1}}, want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := cronbachAlpha(tt.scores); !equalOptional(got, tt.want) {
+			t.Errorf("%s: cronbachAlpha = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want *float64
+	}{
+		{name: "прямая связь", x: []float64{1, 2, 3}, y: []float64{2, 4, 6}, want: floatPtr(1)},
+		{name: "обратная связь", x: []float64{1, 2, 3}, y: []float64{3, 2, 1}, want: floatPtr(-1)},
+		{name: "частичная связь", x: []float64{1, 2, 3, 4}, y: []float64{2, 1, 4, 3}, want: floatPtr(0.6)},
+		{name: "одно значение", x: []float64{1}, y: []float64{2}, want: nil},
+		{name: "без разброса", x: []float64{2, 2, 2}, y: []float64{1, 2, 3}, want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := correlation(tt.x, tt.y); !equalOptional(got, tt.want) {
+			t.Errorf("%s: correlation = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestItemRange(t *testing.T) {
+	options := func(weights ...float64) []entity.AnswerOption {
+		result := make([]entity.AnswerOption, 0, len(weights))
+		for i, weight := range weights {
+			result = append(result, entity.AnswerOption{ID: i + 1, Weights: map[entity.ScaleID]float64{"s": weight}})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		question  entity.Question
+		low, high float64
+	}{
+		{
+			name:     "один ответ",
+			question: entity.Question{SelectType: entity.QuestionTypeOne, AnswerOptions: options(2, -1, 0)},
+			low:      -1, high: 2,
+		},
+		{
+			name:     "несколько ответов с положительными весами",
+			question: entity.Question{SelectType: entity.QuestionTypeMultiple, AnswerOptions: options(1, 2)},
+			low:      1, high: 3,
+		},
+		{
+			name:     "несколько ответов с весами разных знаков",
+			question: entity.Question{SelectType: entity.QuestionTypeMultiple, AnswerOptions: options(-1, 2, 0)},
+			low:      -1, high: 2,
+		},
+		{
+			name:     "несколько ответов с отрицательными весами",
+			question: entity.Question{SelectType: entity.QuestionTypeMultiple, AnswerOptions: options(-1, -2)},
+			low:      -3, high: -1,
+		},
+		{
+			name: "шкала Лайкерта с отрицательным весом",
+			question: entity.Question{
+				SelectType: entity.QuestionTypeLikert,
+				Likert:     &entity.LikertScale{Min: 1, Max: 5},
+				Weights:    map[entity.ScaleID]float64{"s": -0.5},
+			},
+			low: -2.5, high: -0.5,
+		},
+		{
+			name:     "ранжирование",
+			question: entity.Question{SelectType: entity.QuestionTypeRanking, AnswerOptions: options(0, 1, 0)},
+			low:      1, high: 3,
+		},
+		{
+			name: "матрица",
+			question: entity.Question{
+				SelectType:    entity.QuestionTypeMatrix,
+				AnswerOptions: options(0, 1),
+				Rows:          []entity.MatrixRow{{ID: 1}, {ID: 2}},
+			},
+			low: 0, high: 2,
+		},
+		{
+			name:     "свободный ответ",
+			question: entity.Question{SelectType: entity.QuestionTypeText},
+			low:      0, high: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		low, high := itemRange(tt.question, "s")
+		if math.Abs(low-tt.low) > tolerance || math.Abs(high-tt.high) > tolerance {
+			t.Errorf("%s: itemRange = %v, %v; want %v, %v", tt.name, low, high, tt.low, tt.high)
+		}
+	}
+}
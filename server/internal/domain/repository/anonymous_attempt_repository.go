@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"server/internal/domain/entity"
+)
+
+// AnonymousAttemptRepository описывает контракт хранилища попыток без входа в аккаунт
+type AnonymousAttemptRepository interface {
+	// Insert сохраняет попытку и удаляет попытки с истекшим сроком хранения
+	Insert(ctx context.Context, attempt entity.AnonymousAttempt) (entity.AnonymousAttemptID, error)
+
+	// TakeByTokenHash атомарно находит и удаляет попытку по хешу токена привязки,
+	// чтобы попытку нельзя было привязать дважды. Неизвестный токен - ErrInvalidToken
+	TakeByTokenHash(ctx context.Context, tokenHash string) (entity.AnonymousAttempt, error)
+}
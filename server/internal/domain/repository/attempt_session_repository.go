@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"server/internal/domain/entity"
+)
+
+// AttemptSessionRepository описывает контракт хранилища незавершенных попыток
+type AttemptSessionRepository interface {
+	// Insert создает новую попытку и возвращает ее ID
+	Insert(ctx context.Context, session entity.AttemptSession) (entity.AttemptSessionID, error)
+
+	// FindByID находит попытку по ID
+	FindByID(ctx context.Context, id entity.AttemptSessionID) (entity.AttemptSession, error)
+
+	// FindActiveByUser находит неотправленные и неистекшие попытки пользователя
+	FindActiveByUser(ctx context.Context, userID entity.UserID, now time.Time) ([]entity.AttemptSession, error)
+
+	// SaveAnswer сохраняет ответ на вопрос, сбрасывает текущий вопрос и продлевает срок попытки.
+	// Для отправленной или истекшей попытки возвращает ErrNotFound
+	SaveAnswer(ctx context.Context, id entity.AttemptSessionID, answer entity.QuestionAnswer, updatedAt, expiresAt time.Time) error
+
+	// OpenQuestion запоминает открытый вопрос и момент открытия, продлевая срок попытки.
+	// Для отправленной или истекшей попытки возвращает ErrNotFound
+	OpenQuestion(ctx context.Context, id entity.AttemptSessionID, questionID int, openedAt, expiresAt time.Time) error
+
+	// MarkSubmitted атомарно помечает попытку отправленной; повторная пометка возвращает ErrNotFound
+	MarkSubmitted(ctx context.Context, id entity.AttemptSessionID, submittedAt time.Time) error
+
+	// Reopen снимает пометку об отправке, если результат попытки не удалось сохранить
+	Reopen(ctx context.Context, id entity.AttemptSessionID) error
+}
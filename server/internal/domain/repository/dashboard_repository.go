@@ -21,15 +21,24 @@ type DashboardRepository interface {
 	// FindUserAnswersByTest находит ответы пользователя на конкретный тест
 	FindUserAnswersByTest(ctx context.Context, testID entity.TestID) ([]entity.UserAnswer, error)
 
+	// FindAttemptsByUserAndTest находит попытки пользователя по тесту в порядке прохождения
+	FindAttemptsByUserAndTest(ctx context.Context, userID entity.UserID, testID entity.TestID) ([]entity.UserAnswer, error)
+
 	// FindAnswerDetailsByAnswerID находит детали ответа по ID
 	FindAnswerDetailsByAnswerID(ctx context.Context, answerID entity.UserAnswerID) (entity.UserAnswerDetails, error)
 
-	// FindQuestionsByTestID находит вопросы теста
-	FindQuestionsByTestID(ctx context.Context, testID entity.TestID) ([]entity.Question, error)
+	// FindCompletedTestByID находит прохождение теста по ID
+	FindCompletedTestByID(ctx context.Context, answerID entity.UserAnswerID) (entity.UserAnswer, error)
+
+	// FindQuestionsVersion находит вопросы указанной версии теста
+	FindQuestionsVersion(ctx context.Context, testID entity.TestID, version int) ([]entity.Question, error)
 
 	// UpdateUserStatus обновляет статус пользователя
 	UpdateUserStatus(ctx context.Context, userID entity.UserID, status entity.UserStatus) error
 
+	// UpdateUserRole обновляет роль пользователя и связанный с ней статус
+	UpdateUserRole(ctx context.Context, userID entity.UserID, role entity.Role, status entity.UserStatus) error
+
 	// UpdateUserData обновляет данные пользователя
 	UpdateUserData(ctx context.Context, userID entity.UserID, firstName, lastName string) error
 
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"server/internal/domain/entity"
+)
+
+// ItemAnalysisRepository описывает контракт хранилища рассчитанных анализов вопросов теста
+type ItemAnalysisRepository interface {
+	// FindByTestVersion находит сохраненный анализ версии теста
+	FindByTestVersion(ctx context.Context, testID entity.TestID, version int) (entity.ItemAnalysis, error)
+
+	// Save сохраняет анализ версии теста, заменяя предыдущий
+	Save(ctx context.Context, analysis entity.ItemAnalysis) error
+}
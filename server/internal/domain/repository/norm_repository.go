@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"server/internal/domain/entity"
+)
+
+// NormRepository описывает контракт хранилища таблиц норм
+type NormRepository interface {
+	// Insert сохраняет черновик таблицы норм и возвращает его ID
+	Insert(ctx context.Context, table entity.NormTable) (entity.NormTableID, error)
+
+	// FindByID находит таблицу норм по ID
+	FindByID(ctx context.Context, id entity.NormTableID) (entity.NormTable, error)
+
+	// FindByTestID находит таблицы норм теста: закрепленные от новых версий к старым,
+	// затем черновики
+	FindByTestID(ctx context.Context, testID entity.TestID) ([]entity.NormTable, error)
+
+	// FindActive находит закрепленную таблицу норм с наибольшей версией среди построенных
+	// по версии теста testVersion. Если закрепленных таблиц нет - ErrNotFound
+	FindActive(ctx context.Context, testID entity.TestID, testVersion int) (entity.NormTable, error)
+
+	// Freeze закрепляет черновик под следующим номером версии норм теста и возвращает
+	// закрепленную таблицу. Если таблица уже закреплена - ErrNormFrozen
+	Freeze(ctx context.Context, id entity.NormTableID, frozenAt time.Time) (entity.NormTable, error)
+}
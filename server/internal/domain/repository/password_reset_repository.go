@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"server/internal/domain/entity"
+)
+
+// PasswordResetRepository описывает контракт хранилища запросов на сброс пароля
+type PasswordResetRepository interface {
+	// Insert сохраняет новый запрос на сброс пароля
+	Insert(ctx context.Context, reset entity.PasswordReset) error
+
+	// FindByTokenHash находит запрос на сброс по хешу токена
+	FindByTokenHash(ctx context.Context, tokenHash string) (entity.PasswordReset, error)
+
+	// MarkUsed атомарно помечает запрос использованным; повторная пометка возвращает ErrInvalidToken
+	MarkUsed(ctx context.Context, id entity.PasswordResetID, usedAt time.Time) error
+
+	// DeleteByUser удаляет все запросы на сброс пароля пользователя
+	DeleteByUser(ctx context.Context, userID entity.UserID) error
+}
@@ -10,24 +10,48 @@ type TestRepository interface {
 	// FindByStatus находит тесты по статусу
 	FindByStatus(ctx context.Context, status entity.TestStatus) ([]entity.Test, error)
 
+	// Search находит опубликованные тесты, доступные в момент search.Now, по условиям
+	// поиска в каталоге и возвращает страницу не длиннее search.Limit
+	Search(ctx context.Context, search entity.TestSearch) (entity.TestSearchPage, error)
+
+	// FindByUserID находит тесты, созданные пользователем, кроме удаленных
+	FindByUserID(ctx context.Context, userID entity.UserID) ([]entity.Test, error)
+
+	// FindByCollaborator находит тесты, в которых пользователь - соавтор, кроме удаленных
+	FindByCollaborator(ctx context.Context, userID entity.UserID) ([]entity.Test, error)
+
 	// FindByID находит тест по ID
 	FindByID(ctx context.Context, id entity.TestID) (entity.Test, error)
 
-	// FindQuestionsByTestID находит вопросы теста
+	// FindQuestionsByTestID находит вопросы текущей версии теста
 	FindQuestionsByTestID(ctx context.Context, testID entity.TestID) (entity.QuestionsDocument, error)
 
+	// FindQuestionsVersion находит вопросы указанной версии теста
+	FindQuestionsVersion(ctx context.Context, testID entity.TestID, version int) (entity.QuestionsDocument, error)
+
 	// Insert создает новый тест и возвращает его ID
 	Insert(ctx context.Context, test entity.Test) (entity.TestID, error)
 
-	// InsertQuestions создает вопросы для теста
+	// LastQuestionsVersion возвращает наибольший номер сохраненной версии вопросов теста
+	LastQuestionsVersion(ctx context.Context, testID entity.TestID) (int, error)
+
+	// InsertQuestions сохраняет версию вопросов теста; версия должна быть задана.
+	// Если такая версия уже сохранена, возвращает ErrVersionExists
 	InsertQuestions(ctx context.Context, doc entity.QuestionsDocument) error
 
 	// UpdateStatus обновляет статус теста
 	UpdateStatus(ctx context.Context, id entity.TestID, status entity.TestStatus) error
 
-	// UpdateTest обновляет данные теста
-	UpdateTest(ctx context.Context, test entity.Test) error
+	// UpdateLifecycle переводит тест из статуса from в статус test.Status, сохраняя
+	// период показа и комментарий проверки. Если статус теста уже не from, возвращает ErrNotFound
+	UpdateLifecycle(ctx context.Context, test entity.Test, from entity.TestStatus) error
+
+	// UpdateAccess сохраняет владельца test.UserID и соавторов теста. Если владелец
+	// теста уже не owner, возвращает ErrNotFound
+	UpdateAccess(ctx context.Context, test entity.Test, owner entity.UserID) error
 
-	// UpsertQuestions обновляет или создает вопросы теста
-	UpsertQuestions(ctx context.Context, doc entity.QuestionsDocument) error
+	// UpdateTest обновляет данные теста и переключает его на версию test.Version.
+	// Если статус теста уже не test.Status или его версия уже не previousVersion,
+	// возвращает ErrNotFound
+	UpdateTest(ctx context.Context, test entity.Test, previousVersion int) error
 }
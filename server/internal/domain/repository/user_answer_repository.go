@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"server/internal/domain/entity"
 )
 
@@ -13,15 +15,38 @@ type UserAnswerRepository interface {
 	// FindByUserAndTest находит ответы пользователя на конкретный тест
 	FindByUserAndTest(ctx context.Context, userID entity.UserID, testID entity.TestID) ([]entity.UserAnswer, error)
 
-	// Insert создает новый ответ пользователя и возвращает его ID
+	// FindByTestID находит все ответы на тест
+	FindByTestID(ctx context.Context, testID entity.TestID) ([]entity.UserAnswer, error)
+
+	// UpdateResult сохраняет пересчитанный результат ответа (текст, баллы, ведущие шкалы и правила)
+	UpdateResult(ctx context.Context, answer entity.UserAnswer) error
+
+	// Insert создает новый ответ пользователя и возвращает его ID. Ответ с заданным ID
+	// сохраняется под ним; если такой ответ уже есть, возвращается его ID без изменений
 	Insert(ctx context.Context, answer entity.UserAnswer) (entity.UserAnswerID, error)
 
-	// InsertDetails создает детальные ответы пользователя
+	// InsertDetails создает детальные ответы пользователя. Если детальные ответы
+	// на этот ответ уже есть, они не меняются
 	InsertDetails(ctx context.Context, details entity.UserAnswerDetails) error
 
 	// FindDetailsByAnswerID находит детальные ответы по ID ответа
 	FindDetailsByAnswerID(ctx context.Context, answerID entity.UserAnswerID) (entity.UserAnswerDetails, error)
 
+	// FindDetailsByAnswerIDs находит детальные ответы по списку ID ответов.
+	// Ответы без детальных ответов пропускаются
+	FindDetailsByAnswerIDs(ctx context.Context, answerIDs []entity.UserAnswerID) ([]entity.UserAnswerDetails, error)
+
+	// SummarizeByTestVersion возвращает число ответов на версию теста и ID последнего из них
+	SummarizeByTestVersion(ctx context.Context, testID entity.TestID, version int) (entity.AnswerSummary, error)
+
+	// ReserveCompletion атомарно отмечает прохождение теста пользователем в момент at,
+	// если предыдущее отмеченное прохождение было не позже lastBefore. Иначе
+	// возвращает ErrRetakeDenied
+	ReserveCompletion(ctx context.Context, userID entity.UserID, testID entity.TestID, at, lastBefore time.Time) error
+
+	// ReleaseCompletion снимает отметку прохождения в момент at, если результат не сохранен
+	ReleaseCompletion(ctx context.Context, userID entity.UserID, testID entity.TestID, at time.Time) error
+
 	// DeleteByUserID удаляет все ответы пользователя
 	DeleteByUserID(ctx context.Context, userID entity.UserID) error
 }
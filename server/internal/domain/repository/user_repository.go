@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"server/internal/domain/entity"
 )
 
@@ -19,6 +21,9 @@ type UserRepository interface {
 	// UpdateStatus обновляет статус пользователя
 	UpdateStatus(ctx context.Context, id entity.UserID, status entity.UserStatus) error
 
+	// UpdatePassword заменяет сохраненный хеш пароля пользователя
+	UpdatePassword(ctx context.Context, id entity.UserID, passwordHash string) error
+
 	// UpdateData обновляет данные пользователя
 	UpdateData(ctx context.Context, id entity.UserID, firstName, lastName string) error
 
@@ -27,4 +32,30 @@ type UserRepository interface {
 
 	// FindAllExcept находит всех пользователей кроме указанного
 	FindAllExcept(ctx context.Context, excludeID entity.UserID) ([]entity.User, error)
+
+	// AddSession добавляет сессию пользователю
+	AddSession(ctx context.Context, id entity.UserID, session entity.Session) error
+
+	// RemoveSession удаляет сессию пользователя
+	RemoveSession(ctx context.Context, id entity.UserID, sessionID entity.SessionID) error
+
+	// FindByExternalAccount находит пользователя по привязанной учетной записи провайдера
+	FindByExternalAccount(ctx context.Context, provider entity.OAuthProvider, subject string) (entity.User, error)
+
+	// LinkExternalAccount привязывает учетную запись провайдера, заменяя прежнюю привязку этого провайдера
+	LinkExternalAccount(ctx context.Context, id entity.UserID, account entity.ExternalAccount) error
+
+	// UnlinkExternalAccount отвязывает учетную запись провайдера
+	UnlinkExternalAccount(ctx context.Context, id entity.UserID, provider entity.OAuthProvider) error
+
+	// RemoveExpiredSessions удаляет сессии пользователя, истекшие к моменту now
+	RemoveExpiredSessions(ctx context.Context, id entity.UserID, now time.Time) error
+
+	// RemoveAllSessions удаляет все сессии пользователя
+	RemoveAllSessions(ctx context.Context, id entity.UserID) error
+
+	// AssignPsychoType добавляет запись в историю психотипов по времени назначения и
+	// устанавливает психотип, если запись в истории самая новая. Запись о попытке,
+	// уже учтенной в истории, повторно не добавляется
+	AssignPsychoType(ctx context.Context, id entity.UserID, assignment entity.PsychoTypeAssignment) error
 }
@@ -0,0 +1,304 @@
+// Package scoring подсчитывает баллы теста по шкалам на основе ответов пользователя.
+// Подсчет детерминирован, поэтому результат можно пересчитать по сохраненным ответам
+package scoring
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"server/internal/domain/entity"
+)
+
+// Result - результат подсчета баллов и их интерпретации
+type Result struct {
+	Scores       []entity.ScaleScore
+	Dominant     []entity.ScaleID
+	MatchedRules []string
+	Text         string
+}
+
+// Evaluate подсчитывает баллы по шкалам. Вклад ответа зависит от типа вопроса:
+//   - one, multiple: сумма весов выбранных вариантов;
+//   - likert: выбранное значение, умноженное на веса вопроса;
+//   - ranking: вес варианта, умноженный на число очков за позицию (первая позиция дает
+//     столько очков, сколько вариантов в вопросе, последняя - одно);
+//   - matrix: сумма весов выбранных в строках столбцов;
+//   - text: не учитывается.
+//
+// Неизвестные вопросы, варианты и строки, а также повторы не учитываются
+func Evaluate(doc entity.QuestionsDocument, answers []entity.QuestionAnswer) Result {
+	logic := doc.ResultsLogic
+	if logic.IsEmpty() {
+		return Result{
+			Scores:       []entity.ScaleScore{},
+			Dominant:     []entity.ScaleID{},
+			MatchedRules: []string{},
+		}
+	}
+
+	totals := make(map[entity.ScaleID]float64, len(logic.Scales))
+	answered := make(map[int]struct{}, len(answers))
+
+	for _, answer := range answers {
+		question, ok := doc.Question(answer.QuestionID)
+		if !ok {
+			continue
+		}
+		if _, seen := answered[question.ID]; seen {
+			continue
+		}
+		answered[question.ID] = struct{}{}
+
+		addQuestionScores(totals, question, answer)
+	}
+
+	// Шкалы возвращаются в порядке, заданном автором теста
+	scores := make([]entity.ScaleScore, 0, len(logic.Scales))
+	for _, scale := range logic.Scales {
+		score := entity.ScaleScore{
+			ScaleID: scale.ID,
+			Name:    scale.Name,
+			Score:   totals[scale.ID],
+		}
+		if band, ok := scale.Band(score.Score); ok {
+			score.Band = band.Label
+			score.BandText = band.Text
+		}
+		scores = append(scores, score)
+	}
+
+	dominant := dominantScales(scores, logic.TieBreak)
+	matched, texts := matchRules(logic, scores, dominant)
+
+	return Result{
+		Scores:       scores,
+		Dominant:     dominant,
+		MatchedRules: matched,
+		Text:         narrative(texts, scores),
+	}
+}
+
+// QuestionScores возвращает баллы шкал за ответ на один вопрос по тем же правилам, что Evaluate
+func QuestionScores(question entity.Question, answer entity.QuestionAnswer) map[entity.ScaleID]float64 {
+	totals := make(map[entity.ScaleID]float64)
+	addQuestionScores(totals, question, answer)
+	return totals
+}
+
+// addQuestionScores добавляет к суммам баллы за ответ на вопрос
+func addQuestionScores(totals map[entity.ScaleID]float64, question entity.Question, answer entity.QuestionAnswer) {
+	switch question.SelectType {
+	case entity.QuestionTypeLikert:
+		if answer.Value == nil || question.Likert == nil || !question.Likert.Contains(*answer.Value) {
+			return
+		}
+		addWeights(totals, question.Weights, float64(*answer.Value))
+
+	case entity.QuestionTypeText:
+		return
+
+	case entity.QuestionTypeRanking:
+		points := len(question.AnswerOptions)
+		for _, optionID := range uniqueIDs(answer.OptionIDs) {
+			option, ok := question.Option(optionID)
+			if !ok {
+				continue
+			}
+			addWeights(totals, option.Weights, float64(points))
+			points--
+		}
+
+	case entity.QuestionTypeMatrix:
+		rows := make(map[int]struct{}, len(answer.Cells))
+		for _, cell := range answer.Cells {
+			if _, ok := question.Row(cell.RowID); !ok {
+				continue
+			}
+			if _, seen := rows[cell.RowID]; seen {
+				continue
+			}
+			rows[cell.RowID] = struct{}{}
+
+			if option, ok := question.Option(cell.OptionID); ok {
+				addWeights(totals, option.Weights, 1)
+			}
+		}
+
+	default:
+		for _, optionID := range uniqueIDs(answer.OptionIDs) {
+			if option, ok := question.Option(optionID); ok {
+				addWeights(totals, option.Weights, 1)
+			}
+		}
+	}
+}
+
+// addWeights добавляет к суммам веса шкал, умноженные на множитель
+func addWeights(totals map[entity.ScaleID]float64, weights map[entity.ScaleID]float64, factor float64) {
+	for scaleID, weight := range weights {
+		totals[scaleID] += weight * factor
+	}
+}
+
+// uniqueIDs возвращает идентификаторы без повторов, сохраняя порядок
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]struct{}, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+// dominantScales определяет ведущие шкалы - шкалы с максимальным количеством баллов
+func dominantScales(scores []entity.ScaleScore, tieBreak entity.TieBreak) []entity.ScaleID {
+	if len(scores) == 0 {
+		return []entity.ScaleID{}
+	}
+
+	top := scores[0].Score
+	for _, score := range scores[1:] {
+		if score.Score > top {
+			top = score.Score
+		}
+	}
+
+	leaders := make([]entity.ScaleID, 0, 1)
+	for _, score := range scores {
+		if score.Score == top {
+			leaders = append(leaders, score.ScaleID)
+		}
+	}
+
+	if len(leaders) > 1 {
+		switch tieBreak {
+		case entity.TieBreakAll:
+		case entity.TieBreakNone:
+			return []entity.ScaleID{}
+		default:
+			return leaders[:1]
+		}
+	}
+	return leaders
+}
+
+// matchRules проверяет правила в порядке убывания приоритета и возвращает
+// идентификаторы и тексты сработавших правил
+func matchRules(logic entity.ResultsLogic, scores []entity.ScaleScore, dominant []entity.ScaleID) ([]string, []string) {
+	rules := make([]entity.ResultRule, len(logic.Rules))
+	copy(rules, logic.Rules)
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority > rules[j].Priority
+	})
+
+	byScale := make(map[entity.ScaleID]entity.ScaleScore, len(scores))
+	for _, score := range scores {
+		byScale[score.ScaleID] = score
+	}
+	leading := make(map[entity.ScaleID]struct{}, len(dominant))
+	for _, id := range dominant {
+		leading[id] = struct{}{}
+	}
+
+	matched := []string{}
+	texts := []string{}
+	for _, rule := range rules {
+		if !ruleMatches(rule, byScale, leading) {
+			continue
+		}
+		matched = append(matched, rule.ID)
+		texts = append(texts, rule.Text)
+		if logic.RuleMode != entity.RuleModeAll {
+			break
+		}
+	}
+	return matched, texts
+}
+
+// ruleMatches проверяет, выполнены ли все условия правила
+func ruleMatches(rule entity.ResultRule, byScale map[entity.ScaleID]entity.ScaleScore, leading map[entity.ScaleID]struct{}) bool {
+	if len(rule.Conditions) == 0 {
+		return false
+	}
+
+	for _, condition := range rule.Conditions {
+		score, ok := byScale[condition.ScaleID]
+		if !ok {
+			return false
+		}
+		if condition.Band != "" && score.Band != condition.Band {
+			return false
+		}
+		if condition.Min != nil && score.Score < *condition.Min {
+			return false
+		}
+		if condition.Max != nil && score.Score > *condition.Max {
+			return false
+		}
+		if condition.Above != "" {
+			other, ok := byScale[condition.Above]
+			if !ok || score.Score <= other.Score {
+				return false
+			}
+		}
+		if condition.Dominant {
+			if _, ok := leading[condition.ScaleID]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// PsychoType определяет психотип по результату теста определения психотипа: это название
+// единственной ведущей шкалы. Если ведущей шкалы нет или их несколько, психотип не определен
+func PsychoType(logic entity.ResultsLogic, result Result) (string, bool) {
+	if len(result.Dominant) != 1 {
+		return "", false
+	}
+
+	scale, ok := logic.Scale(result.Dominant[0])
+	if !ok {
+		return "", false
+	}
+	return scale.Name, true
+}
+
+// narrative собирает текст результата: тексты сработавших правил, иначе тексты
+// диапазонов шкал, иначе перечень баллов
+func narrative(ruleTexts []string, scores []entity.ScaleScore) string {
+	if len(ruleTexts) > 0 {
+		return strings.Join(ruleTexts, "\n\n")
+	}
+
+	bandTexts := make([]string, 0, len(scores))
+	for _, score := range scores {
+		if score.BandText != "" {
+			bandTexts = append(bandTexts, score.Name+": "+score.BandText)
+		}
+	}
+	if len(bandTexts) > 0 {
+		return strings.Join(bandTexts, "\n")
+	}
+
+	return FormatScores(scores)
+}
+
+// FormatScores формирует текстовое описание баллов вида "Шкала: 10; Шкала 2: 4"
+func FormatScores(scores []entity.ScaleScore) string {
+	parts := make([]string, 0, len(scores))
+	for _, score := range scores {
+		parts = append(parts, score.Name+": "+FormatScore(score.Score))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FormatScore форматирует количество баллов без лишних нулей
+func FormatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
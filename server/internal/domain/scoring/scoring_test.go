@@ -0,0 +1,345 @@
+package scoring
+
+import (
+	"reflect"
+	"testing"
+
+	"server/internal/domain/entity"
+)
+
+func intPtr(v int) *int { return &v }
+
+func floatPtr(v float64) *float64 { return &v }
+
+// testDocument - тест с вопросами всех типов и двумя шкалами
+func testDocument() entity.QuestionsDocument {
+	return entity.QuestionsDocument{
+		Questions: []entity.Question{
+			{
+				ID:         1,
+				SelectType: entity.QuestionTypeOne,
+				AnswerOptions: []entity.AnswerOption{
+					{ID: 1, Weights: map[entity.ScaleID]float64{"e": 2}},
+					{ID: 2, Weights: map[entity.ScaleID]float64{"i": 2}},
+				},
+			},
+			{
+				ID:         2,
+				SelectType: entity.QuestionTypeMultiple,
+				AnswerOptions: []entity.AnswerOption{
+					{ID: 1, Weights: map[entity.ScaleID]float64{"e": 1}},
+					{ID: 2, Weights: map[entity.ScaleID]float64{"e": 1, "i": 1}},
+					{ID: 3, Weights: map[entity.ScaleID]float64{"i": 3}},
+				},
+			},
+			{
+				ID:         3,
+				SelectType: entity.QuestionTypeLikert,
+				Likert:     &entity.LikertScale{Min: 1, Max: 5},
+				Weights:    map[entity.ScaleID]float64{"e": 1, "i": -0.5},
+			},
+			{
+				ID:         4,
+				SelectType: entity.QuestionTypeRanking,
+				AnswerOptions: []entity.AnswerOption{
+					{ID: 1, Weights: map[entity.ScaleID]float64{"e": 1}},
+					{ID: 2, Weights: map[entity.ScaleID]float64{"i": 1}},
+					{ID: 3, Weights: map[entity.ScaleID]float64{"e": 2}},
+				},
+			},
+			{
+				ID:         5,
+				SelectType: entity.QuestionTypeMatrix,
+				AnswerOptions: []entity.AnswerOption{
+					{ID: 1, Weights: map[entity.ScaleID]float64{"e": 1}},
+					{ID: 2, Weights: map[entity.ScaleID]float64{"i": 1}},
+				},
+				Rows: []entity.MatrixRow{{ID: 1}, {ID: 2}},
+			},
+			{
+				ID:         6,
+				SelectType: entity.QuestionTypeText,
+			},
+		},
+		ResultsLogic: entity.ResultsLogic{
+			Scales: []entity.Scale{
+				{
+					ID:   "e",
+					Name: "Экстраверсия",
+					Bands: []entity.ScoreBand{
+						{Min: 0, Max: 5, Label: "low", Text: "Низкий"},
+						{Min: 5, Max: 100, Label: "high", Text: "Высокий"},
+					},
+				},
+				{ID: "i", Name: "Интроверсия"},
+			},
+		},
+	}
+}
+
+func TestQuestionScores(t *testing.T) {
+	doc := testDocument()
+
+	tests := []struct {
+		name     string
+		question int
+		answer   entity.QuestionAnswer
+		want     map[entity.ScaleID]float64
+	}{
+		{
+			name:     "один вариант",
+			question: 1,
+			answer:   entity.QuestionAnswer{OptionIDs: []int{2}},
+			want:     map[entity.ScaleID]float64{"i": 2},
+		},
+		{
+			name:     "несколько вариантов суммируются",
+			question: 2,
+			answer:   entity.QuestionAnswer{OptionIDs: []int{1, 2}},
+			want:     map[entity.ScaleID]float64{"e": 2, "i": 1},
+		},
+		{
+			name:     "повторы и неизвестные варианты не учитываются",
+			question: 2,
+			answer:   entity.QuestionAnswer{OptionIDs: []int{3, 3, 9}},
+			want:     map[entity.ScaleID]float64{"i": 3},
+		},
+		{
+			name:     "шкала Лайкерта умножает веса на значение",
+			question: 3,
+			answer:   entity.QuestionAnswer{Value: intPtr(4)},
+			want:     map[entity.ScaleID]float64{"e": 4, "i": -2},
+		},
+		{
+			name:     "значение вне шкалы Лайкерта",
+			question: 3,
+			answer:   entity.QuestionAnswer{Value: intPtr(6)},
+			want:     map[entity.ScaleID]float64{},
+		},
+		{
+			name:     "шкала Лайкерта без значения",
+			question: 3,
+			answer:   entity.QuestionAnswer{},
+			want:     map[entity.ScaleID]float64{},
+		},
+		{
+			name:     "ранжирование начисляет баллы по позиции",
+			question: 4,
+			answer:   entity.QuestionAnswer{OptionIDs: []int{3, 2, 1}},
+			want:     map[entity.ScaleID]float64{"e": 7, "i": 2},
+		},
+		{
+			name:     "ранжирование пропускает неизвестные варианты без потери баллов",
+			question: 4,
+			answer:   entity.QuestionAnswer{OptionIDs: []int{9, 2}},
+			want:     map[entity.ScaleID]float64{"i": 3},
+		},
+		{
+			name:     "матрица суммирует один столбец в строке",
+			question: 5,
+			answer: entity.QuestionAnswer{Cells: []entity.MatrixCell{
+				{RowID: 1, OptionID: 1},
+				{RowID: 1, OptionID: 2},
+				{RowID: 2, OptionID: 2},
+				{RowID: 3, OptionID: 1},
+			}},
+			want: map[entity.ScaleID]float64{"e": 1, "i": 1},
+		},
+		{
+			name:     "текстовый ответ не оценивается",
+			question: 6,
+			answer:   entity.QuestionAnswer{Text: "ответ"},
+			want:     map[entity.ScaleID]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			question, ok := doc.Question(tt.question)
+			if !ok {
+				t.Fatalf("question %d not found", tt.question)
+			}
+			tt.answer.QuestionID = tt.question
+
+			got := QuestionScores(question, tt.answer)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QuestionScores() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name         string
+		logic        func(*entity.ResultsLogic)
+		answers      []entity.QuestionAnswer
+		wantScores   map[entity.ScaleID]float64
+		wantBands    map[entity.ScaleID]string
+		wantDominant []entity.ScaleID
+		wantRules    []string
+		wantText     string
+	}{
+		{
+			name: "суммируются все типы вопросов",
+			answers: []entity.QuestionAnswer{
+				{QuestionID: 1, OptionIDs: []int{1}},
+				{QuestionID: 2, OptionIDs: []int{2}},
+				{QuestionID: 3, Value: intPtr(2)},
+				{QuestionID: 4, OptionIDs: []int{1, 2, 3}},
+				{QuestionID: 5, Cells: []entity.MatrixCell{{RowID: 1, OptionID: 2}}},
+				{QuestionID: 6, Text: "ответ"},
+			},
+			wantScores:   map[entity.ScaleID]float64{"e": 10, "i": 3},
+			wantBands:    map[entity.ScaleID]string{"e": "high", "i": ""},
+			wantDominant: []entity.ScaleID{"e"},
+			wantRules:    []string{},
+			wantText:     "Экстраверсия: Высокий",
+		},
+		{
+			name: "повторные и неизвестные вопросы не учитываются",
+			answers: []entity.QuestionAnswer{
+				{QuestionID: 1, OptionIDs: []int{2}},
+				{QuestionID: 1, OptionIDs: []int{1}},
+				{QuestionID: 42, OptionIDs: []int{1}},
+			},
+			wantScores:   map[entity.ScaleID]float64{"e": 0, "i": 2},
+			wantBands:    map[entity.ScaleID]string{"e": "low", "i": ""},
+			wantDominant: []entity.ScaleID{"i"},
+			wantRules:    []string{},
+			wantText:     "Экстраверсия: Низкий",
+		},
+		{
+			name: "при равенстве по умолчанию ведет первая шкала",
+			answers: []entity.QuestionAnswer{
+				{QuestionID: 2, OptionIDs: []int{2}},
+			},
+			wantScores:   map[entity.ScaleID]float64{"e": 1, "i": 1},
+			wantBands:    map[entity.ScaleID]string{"e": "low", "i": ""},
+			wantDominant: []entity.ScaleID{"e"},
+			wantRules:    []string{},
+			wantText:     "Экстраверсия: Низкий",
+		},
+		{
+			name:  "равенство без ведущей шкалы",
+			logic: func(l *entity.ResultsLogic) { l.TieBreak = entity.TieBreakNone },
+			answers: []entity.QuestionAnswer{
+				{QuestionID: 2, OptionIDs: []int{2}},
+			},
+			wantScores:   map[entity.ScaleID]float64{"e": 1, "i": 1},
+			wantBands:    map[entity.ScaleID]string{"e": "low", "i": ""},
+			wantDominant: []entity.ScaleID{},
+			wantRules:    []string{},
+			wantText:     "Экстраверсия: Низкий",
+		},
+		{
+			name:  "равенство со всеми ведущими шкалами",
+			logic: func(l *entity.ResultsLogic) { l.TieBreak = entity.TieBreakAll },
+			answers: []entity.QuestionAnswer{
+				{QuestionID: 2, OptionIDs: []int{2}},
+			},
+			wantScores:   map[entity.ScaleID]float64{"e": 1, "i": 1},
+			wantBands:    map[entity.ScaleID]string{"e": "low", "i": ""},
+			wantDominant: []entity.ScaleID{"e", "i"},
+			wantRules:    []string{},
+			wantText:     "Экстраверсия: Низкий",
+		},
+		{
+			name: "первое подходящее правило по приоритету",
+			logic: func(l *entity.ResultsLogic) {
+				l.Rules = []entity.ResultRule{
+					{ID: "low", Priority: 1, Text: "Мало", Conditions: []entity.RuleCondition{{ScaleID: "e", Band: "low"}}},
+					{ID: "lead", Priority: 5, Text: "Ведет", Conditions: []entity.RuleCondition{{ScaleID: "i", Dominant: true}}},
+					{ID: "above", Priority: 3, Text: "Больше", Conditions: []entity.RuleCondition{{ScaleID: "i", Above: "e"}}},
+				}
+			},
+			answers: []entity.QuestionAnswer{
+				{QuestionID: 1, OptionIDs: []int{2}},
+			},
+			wantScores:   map[entity.ScaleID]float64{"e": 0, "i": 2},
+			wantBands:    map[entity.ScaleID]string{"e": "low", "i": ""},
+			wantDominant: []entity.ScaleID{"i"},
+			wantRules:    []string{"lead"},
+			wantText:     "Ведет",
+		},
+		{
+			name: "все подходящие правила",
+			logic: func(l *entity.ResultsLogic) {
+				l.RuleMode = entity.RuleModeAll
+				l.Rules = []entity.ResultRule{
+					{ID: "min", Priority: 1, Text: "Не меньше двух", Conditions: []entity.RuleCondition{{ScaleID: "i", Min: floatPtr(2)}}},
+					{ID: "max", Priority: 2, Text: "Не больше одного", Conditions: []entity.RuleCondition{{ScaleID: "i", Max: floatPtr(1)}}},
+					{ID: "unknown", Priority: 3, Text: "Нет шкалы", Conditions: []entity.RuleCondition{{ScaleID: "x"}}},
+					{ID: "empty", Priority: 4, Text: "Без условий"},
+				}
+			},
+			answers: []entity.QuestionAnswer{
+				{QuestionID: 1, OptionIDs: []int{2}},
+			},
+			wantScores:   map[entity.ScaleID]float64{"e": 0, "i": 2},
+			wantBands:    map[entity.ScaleID]string{"e": "low", "i": ""},
+			wantDominant: []entity.ScaleID{"i"},
+			wantRules:    []string{"min"},
+			wantText:     "Не меньше двух",
+		},
+		{
+			name: "текст по баллам без диапазонов и правил",
+			logic: func(l *entity.ResultsLogic) {
+				l.Scales[0].Bands = nil
+			},
+			answers: []entity.QuestionAnswer{
+				{QuestionID: 3, Value: intPtr(3)},
+			},
+			wantScores:   map[entity.ScaleID]float64{"e": 3, "i": -1.5},
+			wantBands:    map[entity.ScaleID]string{"e": "", "i": ""},
+			wantDominant: []entity.ScaleID{"e"},
+			wantRules:    []string{},
+			wantText:     "Экстраверсия: 3; Интроверсия: -1.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := testDocument()
+			if tt.logic != nil {
+				tt.logic(&doc.ResultsLogic)
+			}
+
+			result := Evaluate(doc, tt.answers)
+
+			if len(result.Scores) != len(doc.ResultsLogic.Scales) {
+				t.Fatalf("len(Scores) = %d, want %d", len(result.Scores), len(doc.ResultsLogic.Scales))
+			}
+			for i, score := range result.Scores {
+				if score.ScaleID != doc.ResultsLogic.Scales[i].ID {
+					t.Errorf("Scores[%d].ScaleID = %q, want %q", i, score.ScaleID, doc.ResultsLogic.Scales[i].ID)
+				}
+				if score.Score != tt.wantScores[score.ScaleID] {
+					t.Errorf("score of %q = %v, want %v", score.ScaleID, score.Score, tt.wantScores[score.ScaleID])
+				}
+				if score.Band != tt.wantBands[score.ScaleID] {
+					t.Errorf("band of %q = %q, want %q", score.ScaleID, score.Band, tt.wantBands[score.ScaleID])
+				}
+			}
+			if !reflect.DeepEqual(result.Dominant, tt.wantDominant) {
+				t.Errorf("Dominant = %v, want %v", result.Dominant, tt.wantDominant)
+			}
+			if !reflect.DeepEqual(result.MatchedRules, tt.wantRules) {
+				t.Errorf("MatchedRules = %v, want %v", result.MatchedRules, tt.wantRules)
+			}
+			if result.Text != tt.wantText {
+				t.Errorf("Text = %q, want %q", result.Text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestEvaluateWithoutLogic(t *testing.T) {
+	doc := testDocument()
+	doc.ResultsLogic = entity.ResultsLogic{}
+
+	result := Evaluate(doc, []entity.QuestionAnswer{{QuestionID: 1, OptionIDs: []int{1}}})
+	if len(result.Scores) != 0 || len(result.Dominant) != 0 || len(result.MatchedRules) != 0 || result.Text != "" {
+		t.Errorf("Evaluate() = %+v, want empty result", result)
+	}
+}
@@ -0,0 +1,16 @@
+package service
+
+import "context"
+
+// MailMessage - письмо, отправляемое пользователю
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer описывает контракт отправки писем
+type Mailer interface {
+	// Send отправляет письмо получателю
+	Send(ctx context.Context, message MailMessage) error
+}
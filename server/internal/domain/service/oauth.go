@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"server/internal/domain/entity"
+)
+
+// OAuthClient описывает клиента OAuth2 authorization code flow одного провайдера
+type OAuthClient interface {
+	// AuthorizationURL возвращает адрес страницы авторизации провайдера
+	AuthorizationURL(state string) string
+
+	// Exchange обменивает код авторизации на профиль пользователя
+	Exchange(ctx context.Context, code string) (entity.ExternalProfile, error)
+}
+
+// OAuthState - параметры, передаваемые провайдеру в state и возвращаемые им без изменений
+type OAuthState struct {
+	Provider  entity.OAuthProvider
+	LinkTo    entity.UserID
+	Nonce     string
+	ExpiresAt time.Time
+}
+
+// OAuthStateManager подписывает и проверяет параметр state для защиты от CSRF
+type OAuthStateManager interface {
+	// Issue сериализует и подписывает state
+	Issue(state OAuthState) (string, error)
+
+	// Parse проверяет подпись и срок действия state
+	Parse(value string) (OAuthState, error)
+}
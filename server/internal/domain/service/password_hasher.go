@@ -0,0 +1,14 @@
+package service
+
+// PasswordHasher описывает контракт хеширования и проверки паролей
+type PasswordHasher interface {
+	// Hash возвращает хеш пароля для хранения в базе
+	Hash(password string) (string, error)
+
+	// Verify проверяет пароль по сохраненному значению (хешу или устаревшему открытому тексту)
+	Verify(stored, password string) bool
+
+	// NeedsRehash сообщает, что сохраненное значение нужно перехешировать
+	// (открытый текст или хеш с устаревшими параметрами)
+	NeedsRehash(stored string) bool
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"time"
+
+	"server/internal/domain/entity"
+)
+
+// TokenClaims - данные, которые подписываются в токене доступа
+type TokenClaims struct {
+	UserID    entity.UserID
+	SessionID entity.SessionID
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// TokenManager описывает контракт выпуска и проверки подписанных токенов доступа
+type TokenManager interface {
+	// Issue подписывает данные и возвращает токен
+	Issue(claims TokenClaims) (string, error)
+
+	// Parse проверяет подпись и срок действия токена и возвращает его данные
+	Parse(token string) (TokenClaims, error)
+}
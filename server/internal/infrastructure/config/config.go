@@ -2,12 +2,18 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
+	Auth     AuthConfig
+	Mail     MailConfig
+	OAuth    OAuthConfig
+	Attempts AttemptsConfig
 }
 
 type ServerConfig struct {
@@ -22,6 +28,52 @@ type DatabaseConfig struct {
 	Timeout  time.Duration
 }
 
+type AuthConfig struct {
+	TokenSecret    string
+	AccessTokenTTL time.Duration
+	BcryptCost     int
+
+	PasswordResetTTL time.Duration
+	PasswordResetURL string
+}
+
+// MailConfig - настройки отправки писем. Driver: "log" (письма пишутся в лог и OutputDir) или "smtp"
+type MailConfig struct {
+	Driver       string
+	From         string
+	OutputDir    string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// OAuthConfig - настройки входа через внешних провайдеров
+type OAuthConfig struct {
+	StateTTL time.Duration
+	Google   OAuthProviderConfig
+	Yandex   OAuthProviderConfig
+}
+
+// OAuthProviderConfig - параметры OAuth2-провайдера. Провайдер без ClientID считается отключенным
+type OAuthProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// AttemptsConfig - настройки прохождения тестов. SessionTTL - срок, после которого
+// незавершенная попытка без новых ответов истекает; AnonymousTTL - срок хранения
+// попытки без входа в аккаунт до ее привязки
+type AttemptsConfig struct {
+	SessionTTL   time.Duration
+	AnonymousTTL time.Duration
+}
+
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -34,6 +86,48 @@ func Load() *Config {
 			Database: getEnv("MONGO_DATABASE", "psychologyApp"),
 			Timeout:  10 * time.Second,
 		},
+		Auth: AuthConfig{
+			TokenSecret:    getEnv("AUTH_TOKEN_SECRET", ""),
+			AccessTokenTTL: getDurationEnv("AUTH_TOKEN_TTL", 24*time.Hour),
+			BcryptCost:     getIntEnv("AUTH_BCRYPT_COST", 12),
+
+			PasswordResetTTL: getDurationEnv("AUTH_PASSWORD_RESET_TTL", time.Hour),
+			PasswordResetURL: getEnv("AUTH_PASSWORD_RESET_URL", "http://localhost:5173/reset-password"),
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "no-reply@localhost"),
+			OutputDir:    getEnv("MAIL_OUTPUT_DIR", "tmp/mail"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		},
+		OAuth: OAuthConfig{
+			StateTTL: getDurationEnv("OAUTH_STATE_TTL", 10*time.Minute),
+			Google: OAuthProviderConfig{
+				ClientID:     getEnv("OAUTH_GOOGLE_CLIENT_ID", ""),
+				ClientSecret: getEnv("OAUTH_GOOGLE_CLIENT_SECRET", ""),
+				AuthURL:      getEnv("OAUTH_GOOGLE_AUTH_URL", "https://accounts.google.com/o/oauth2/v2/auth"),
+				TokenURL:     getEnv("OAUTH_GOOGLE_TOKEN_URL", "https://oauth2.googleapis.com/token"),
+				UserInfoURL:  getEnv("OAUTH_GOOGLE_USERINFO_URL", "https://openidconnect.googleapis.com/v1/userinfo"),
+				RedirectURL:  getEnv("OAUTH_GOOGLE_REDIRECT_URL", "http://localhost:5173/oauth/google"),
+				Scopes:       strings.Fields(getEnv("OAUTH_GOOGLE_SCOPES", "openid email profile")),
+			},
+			Yandex: OAuthProviderConfig{
+				ClientID:     getEnv("OAUTH_YANDEX_CLIENT_ID", ""),
+				ClientSecret: getEnv("OAUTH_YANDEX_CLIENT_SECRET", ""),
+				AuthURL:      getEnv("OAUTH_YANDEX_AUTH_URL", "https://oauth.yandex.ru/authorize"),
+				TokenURL:     getEnv("OAUTH_YANDEX_TOKEN_URL", "https://oauth.yandex.ru/token"),
+				UserInfoURL:  getEnv("OAUTH_YANDEX_USERINFO_URL", "https://login.yandex.ru/info?format=json"),
+				RedirectURL:  getEnv("OAUTH_YANDEX_REDIRECT_URL", "http://localhost:5173/oauth/yandex"),
+				Scopes:       strings.Fields(getEnv("OAUTH_YANDEX_SCOPES", "login:email login:info")),
+			},
+		},
+		Attempts: AttemptsConfig{
+			SessionTTL:   getDurationEnv("ATTEMPT_SESSION_TTL", 7*24*time.Hour),
+			AnonymousTTL: getDurationEnv("ANONYMOUS_ATTEMPT_TTL", 30*24*time.Hour),
+		},
 	}
 }
 
@@ -43,3 +137,21 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if number, err := strconv.Atoi(value); err == nil {
+			return number
+		}
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil && duration > 0 {
+			return duration
+		}
+	}
+	return defaultValue
+}
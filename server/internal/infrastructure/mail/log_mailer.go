@@ -0,0 +1,63 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"server/internal/domain/service"
+)
+
+// LogMailer - отправитель писем для разработки: пишет письма в лог
+// и, если задан каталог, сохраняет их в .eml-файлы
+type LogMailer struct {
+	from string
+	dir  string
+	now  func() time.Time
+}
+
+// NewLogMailer создает отправителя, сохраняющего письма в каталог dir (пустой - только лог)
+func NewLogMailer(from, dir string) *LogMailer {
+	return &LogMailer{
+		from: from,
+		dir:  dir,
+		now:  time.Now,
+	}
+}
+
+// Send реализует интерфейс service.Mailer
+func (m *LogMailer) Send(ctx context.Context, message service.MailMessage) error {
+	if err := validateRecipient(message.To); err != nil {
+		return err
+	}
+
+	now := m.now()
+	log.Printf("✉ Письмо для %s: %s\n%s", message.To, message.Subject, message.Body)
+
+	if m.dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405.000000000"), sanitizeFileName(message.To))
+	return os.WriteFile(filepath.Join(m.dir, name), buildMessage(m.from, message, now), 0o600)
+}
+
+// sanitizeFileName оставляет в имени файла только безопасные символы
+func sanitizeFileName(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, value)
+}
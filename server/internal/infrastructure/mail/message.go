@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"server/internal/domain/service"
+)
+
+// buildMessage формирует письмо в формате RFC 5322 с телом в UTF-8
+func buildMessage(from string, message service.MailMessage, now time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", message.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// validateRecipient отсекает адреса с переводами строк, чтобы исключить подмену заголовков
+func validateRecipient(address string) error {
+	if address == "" || strings.ContainsAny(address, "\r\n") {
+		return fmt.Errorf("mail: invalid recipient %q", address)
+	}
+	return nil
+}
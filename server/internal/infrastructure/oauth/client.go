@@ -0,0 +1,154 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+// ProviderConfig - параметры OAuth2-провайдера. Адреса настраиваются, чтобы
+// поток можно было проверить на локальном mock-сервере
+type ProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// IsConfigured проверяет, заданы ли обязательные параметры провайдера
+func (c ProviderConfig) IsConfigured() bool {
+	return c.ClientID != "" && c.AuthURL != "" && c.TokenURL != "" && c.UserInfoURL != ""
+}
+
+// profileDecoder разбирает ответ userinfo конкретного провайдера
+type profileDecoder func(body []byte) (entity.ExternalProfile, error)
+
+// Client реализует OAuth2 authorization code flow
+type Client struct {
+	provider      entity.OAuthProvider
+	config        ProviderConfig
+	decodeProfile profileDecoder
+	httpClient    *http.Client
+}
+
+func newClient(provider entity.OAuthProvider, config ProviderConfig, decode profileDecoder) *Client {
+	return &Client{
+		provider:      provider,
+		config:        config,
+		decodeProfile: decode,
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// tokenResponse - ответ token endpoint
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+}
+
+// AuthorizationURL реализует интерфейс service.OAuthClient
+func (c *Client) AuthorizationURL(state string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", c.config.ClientID)
+	query.Set("redirect_uri", c.config.RedirectURL)
+	query.Set("state", state)
+	if len(c.config.Scopes) > 0 {
+		query.Set("scope", strings.Join(c.config.Scopes, " "))
+	}
+
+	separator := "?"
+	if strings.Contains(c.config.AuthURL, "?") {
+		separator = "&"
+	}
+	return c.config.AuthURL + separator + query.Encode()
+}
+
+// Exchange реализует интерфейс service.OAuthClient
+func (c *Client) Exchange(ctx context.Context, code string) (entity.ExternalProfile, error) {
+	accessToken, err := c.exchangeCode(ctx, code)
+	if err != nil {
+		return entity.ExternalProfile{}, err
+	}
+
+	body, err := c.fetchUserInfo(ctx, accessToken)
+	if err != nil {
+		return entity.ExternalProfile{}, err
+	}
+
+	profile, err := c.decodeProfile(body)
+	if err != nil || profile.Subject == "" {
+		return entity.ExternalProfile{}, domainErrors.ErrProviderExchange
+	}
+
+	profile.Provider = c.provider
+	profile.Email = strings.TrimSpace(strings.ToLower(profile.Email))
+	return profile, nil
+}
+
+// exchangeCode обменивает код авторизации на токен доступа провайдера
+func (c *Client) exchangeCode(ctx context.Context, code string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.config.RedirectURL)
+	form.Set("client_id", c.config.ClientID)
+	form.Set("client_secret", c.config.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", domainErrors.ErrProviderExchange
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	body, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil || token.AccessToken == "" {
+		return "", domainErrors.ErrProviderExchange
+	}
+
+	return token.AccessToken, nil
+}
+
+// fetchUserInfo запрашивает профиль пользователя у провайдера
+func (c *Client) fetchUserInfo(ctx context.Context, accessToken string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.UserInfoURL, nil)
+	if err != nil {
+		return nil, domainErrors.ErrProviderExchange
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	return c.do(req)
+}
+
+// do выполняет запрос и возвращает тело успешного ответа
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, domainErrors.ErrProviderExchange
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return nil, domainErrors.ErrProviderExchange
+	}
+
+	return body, nil
+}
@@ -0,0 +1,59 @@
+package oauth
+
+import (
+	"encoding/json"
+
+	"server/internal/domain/entity"
+)
+
+// googleUserInfo - ответ OpenID Connect userinfo endpoint Google
+type googleUserInfo struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+}
+
+// yandexUserInfo - ответ Яндекс ID (login.yandex.ru/info)
+type yandexUserInfo struct {
+	ID           string `json:"id"`
+	DefaultEmail string `json:"default_email"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+}
+
+// NewGoogleClient создает OAuth2-клиента Google
+func NewGoogleClient(config ProviderConfig) *Client {
+	return newClient(entity.OAuthProviderGoogle, config, func(body []byte) (entity.ExternalProfile, error) {
+		var info googleUserInfo
+		if err := json.Unmarshal(body, &info); err != nil {
+			return entity.ExternalProfile{}, err
+		}
+		return entity.ExternalProfile{
+			Subject:       info.Subject,
+			Email:         info.Email,
+			EmailVerified: info.EmailVerified,
+			FirstName:     info.GivenName,
+			LastName:      info.FamilyName,
+		}, nil
+	})
+}
+
+// NewYandexClient создает OAuth2-клиента Яндекса.
+// Яндекс отдает только подтвержденный адрес, поэтому он считается проверенным
+func NewYandexClient(config ProviderConfig) *Client {
+	return newClient(entity.OAuthProviderYandex, config, func(body []byte) (entity.ExternalProfile, error) {
+		var info yandexUserInfo
+		if err := json.Unmarshal(body, &info); err != nil {
+			return entity.ExternalProfile{}, err
+		}
+		return entity.ExternalProfile{
+			Subject:       info.ID,
+			Email:         info.DefaultEmail,
+			EmailVerified: info.DefaultEmail != "",
+			FirstName:     info.FirstName,
+			LastName:      info.LastName,
+		}, nil
+	})
+}
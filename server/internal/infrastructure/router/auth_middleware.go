@@ -0,0 +1,72 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"server/internal/adapter/controller/dto"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	userUseCase "server/internal/usecase/user"
+)
+
+// RequireAuth пропускает только запросы с действительным токеном доступа
+// и помещает вызывающего пользователя в контекст запроса
+func RequireAuth(authUC *userUseCase.AuthenticateUseCase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := bearerToken(ctx.GetHeader("Authorization"))
+		if token == "" {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Требуется авторизация"})
+			return
+		}
+
+		if !authenticate(ctx, authUC, token) {
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// OptionalAuth определяет пользователя, если токен передан, но не требует его наличия
+func OptionalAuth(authUC *userUseCase.AuthenticateUseCase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := bearerToken(ctx.GetHeader("Authorization"))
+		if token != "" && !authenticate(ctx, authUC, token) {
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// authenticate проверяет токен и сохраняет пользователя в контекст; при ошибке прерывает запрос
+func authenticate(ctx *gin.Context, authUC *userUseCase.AuthenticateUseCase, token string) bool {
+	output, err := authUC.Execute(ctx.Request.Context(), userUseCase.AuthenticateInput{Token: token})
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrDatabase) {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Ошибка базы данных"})
+			return false
+		}
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Сессия недействительна, войдите заново"})
+		return false
+	}
+
+	ctx.Request = ctx.Request.WithContext(identity.WithCaller(ctx.Request.Context(), identity.Caller{
+		User:      output.User,
+		SessionID: output.SessionID,
+	}))
+	return true
+}
+
+// bearerToken извлекает токен из заголовка "Authorization: Bearer <token>"
+func bearerToken(header string) string {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
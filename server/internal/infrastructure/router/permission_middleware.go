@@ -0,0 +1,53 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"server/internal/adapter/controller/dto"
+	"server/internal/domain/entity"
+	"server/internal/domain/identity"
+)
+
+// RequirePermission пропускает только пользователей, роль которых дает все перечисленные права.
+// Должен подключаться после RequireAuth
+func RequirePermission(permissions ...entity.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		caller, ok := identity.CallerFromContext(ctx.Request.Context())
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Требуется авторизация"})
+			return
+		}
+
+		for _, permission := range permissions {
+			if !caller.User.HasPermission(permission) {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Error: "Доступ запрещен"})
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequirePermissionIfAuthenticated пропускает запросы без входа в аккаунт, а вошедших
+// пользователей - только с перечисленными правами. Должен подключаться после OptionalAuth
+func RequirePermissionIfAuthenticated(permissions ...entity.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		caller, ok := identity.CallerFromContext(ctx.Request.Context())
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		for _, permission := range permissions {
+			if !caller.User.HasPermission(permission) {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Error: "Доступ запрещен"})
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
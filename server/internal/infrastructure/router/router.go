@@ -5,6 +5,8 @@ import (
 	"github.com/gin-gonic/gin"
 
 	httpController "server/internal/adapter/controller/http"
+	"server/internal/domain/entity"
+	userUseCase "server/internal/usecase/user"
 )
 
 type Controllers struct {
@@ -15,71 +17,121 @@ type Controllers struct {
 	Dashboard      *httpController.DashboardController
 }
 
-func NewRouter(controllers Controllers) *gin.Engine {
+func NewRouter(controllers Controllers, authUC *userUseCase.AuthenticateUseCase) *gin.Engine {
 	router := gin.Default()
 
 	// CORS
 	router.Use(cors.New(cors.Config{
 		AllowOrigins: []string{"http://localhost:3000", "http://localhost:5173"},
 		AllowMethods: []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders: []string{"Origin", "Content-Type"},
+		AllowHeaders: []string{"Origin", "Content-Type", "Authorization"},
+		// Cookie с nonce входа через провайдера передается между запросами клиента
+		AllowCredentials: true,
 	}))
 
+	requireAuth := RequireAuth(authUC)
+	optionalAuth := OptionalAuth(authUC)
+
 	api := router.Group("/api")
 
 	// Auth routes
 	login := api.Group("/login")
 	{
 		login.POST("/password", controllers.Auth.LoginWithPassword)
-		login.POST("/google", controllers.Auth.LoginWithGoogle)
-		login.POST("/yandex", controllers.Auth.LoginWithYandex)
+		login.POST("/google/url", optionalAuth, controllers.Auth.GoogleAuthURL)
+		login.POST("/google", optionalAuth, controllers.Auth.LoginWithGoogle)
+		login.POST("/yandex/url", optionalAuth, controllers.Auth.YandexAuthURL)
+		login.POST("/yandex", optionalAuth, controllers.Auth.LoginWithYandex)
+		login.POST("/unlink", requireAuth, controllers.Auth.UnlinkProvider)
 		login.POST("/lostPassword", controllers.Auth.LostPassword)
+		login.POST("/resetPassword", controllers.Auth.ResetPassword)
 	}
 	api.POST("/createAccount", controllers.Auth.Register)
+	api.POST("/logout", requireAuth, controllers.Auth.Logout)
 
 	// Tests routes
 	tests := api.Group("/tests")
 	{
-		tests.POST("/getTests", controllers.Test.GetTests)
-		tests.POST("/getQuestions", controllers.Test.GetQuestions)
-		tests.POST("/attemptTest", controllers.Test.AttemptTest)
-		tests.POST("/deleteTest", controllers.Test.DeleteTest)
-		tests.POST("/changeTest", controllers.Test.ChangeTest)
-		tests.POST("/addTest", controllers.Test.AddTest)
+		tests.POST("/getTests", optionalAuth, controllers.Test.GetTests)
+		tests.POST("/searchTests", optionalAuth, controllers.Test.SearchTests)
+		tests.POST("/getQuestions", optionalAuth, controllers.Test.GetQuestions)
+		tests.POST("/attemptTest", optionalAuth, RequirePermissionIfAuthenticated(entity.PermissionTestsTake), controllers.Test.AttemptTest)
+
+		attempts := tests.Group("", requireAuth, RequirePermission(entity.PermissionTestsTake))
+		attempts.POST("/startAttempt", controllers.Test.StartAttempt)
+		attempts.POST("/openQuestion", controllers.Test.OpenQuestion)
+		attempts.POST("/saveAttemptAnswer", controllers.Test.SaveAttemptAnswer)
+		attempts.POST("/submitAttempt", controllers.Test.SubmitAttempt)
+		attempts.POST("/activeAttempts", controllers.Test.GetActiveAttempts)
+		attempts.POST("/claimAttempt", controllers.Test.ClaimAttempt)
+
+		tests.POST("/importCsv", requireAuth, RequirePermission(entity.PermissionTestsImport), controllers.Test.ImportCSV)
+		tests.POST("/reviewQueue", requireAuth, RequirePermission(entity.PermissionTestsReview), controllers.Test.GetReviewQueue)
+
+		authoring := tests.Group("", requireAuth, RequirePermission(entity.PermissionTestsWrite))
+		authoring.POST("/deleteTest", controllers.Test.DeleteTest)
+		authoring.POST("/changeTest", controllers.Test.ChangeTest)
+		authoring.POST("/addTest", controllers.Test.AddTest)
+		authoring.POST("/clone", controllers.Test.CloneTest)
+		authoring.POST("/recomputeResults", controllers.Test.RecomputeResults)
+		authoring.POST("/changeStatus", controllers.Test.ChangeStatus)
+		authoring.POST("/myTests", controllers.Test.GetMyTests)
+		authoring.POST("/exportTest", controllers.Test.ExportTest)
+		authoring.POST("/importTest", controllers.Test.ImportTest)
+		authoring.POST("/addCollaborator", controllers.Test.AddCollaborator)
+		authoring.POST("/removeCollaborator", controllers.Test.RemoveCollaborator)
+		authoring.POST("/transferOwnership", controllers.Test.TransferOwnership)
 	}
 
 	// Reviews routes
 	reviews := api.Group("/reviews")
 	{
 		reviews.GET("/getReviews", controllers.Review.GetReviews)
-		reviews.POST("/createReview", controllers.Review.CreateReview)
-		reviews.POST("/updateReview", controllers.Review.UpdateReview)
-		reviews.POST("/deleteReview", controllers.Review.DeleteReview)
-		reviews.POST("/approveOrDeny", controllers.Review.ApproveOrDeny)
+
+		writing := reviews.Group("", requireAuth, RequirePermission(entity.PermissionReviewsWrite))
+		writing.POST("/createReview", controllers.Review.CreateReview)
+		writing.POST("/updateReview", controllers.Review.UpdateReview)
+		writing.POST("/deleteReview", controllers.Review.DeleteReview)
+
+		reviews.POST("/approveOrDeny", requireAuth, RequirePermission(entity.PermissionReviewsModerate), controllers.Review.ApproveOrDeny)
 	}
 
 	// Recommendations routes
 	recommendations := api.Group("/recommendations")
 	{
 		recommendations.GET("/list", controllers.Recommendation.List)
-		recommendations.POST("/addBlock", controllers.Recommendation.AddBlock)
-		recommendations.POST("/updateBlock", controllers.Recommendation.UpdateBlock)
-		recommendations.POST("/deleteBlock", controllers.Recommendation.DeleteBlock)
-		recommendations.POST("/addSection", controllers.Recommendation.AddSection)
-		recommendations.POST("/deleteSection", controllers.Recommendation.DeleteSection)
+
+		editing := recommendations.Group("", requireAuth, RequirePermission(entity.PermissionRecommendationsEdit))
+		editing.POST("/addBlock", controllers.Recommendation.AddBlock)
+		editing.POST("/updateBlock", controllers.Recommendation.UpdateBlock)
+		editing.POST("/deleteBlock", controllers.Recommendation.DeleteBlock)
+		editing.POST("/addSection", controllers.Recommendation.AddSection)
+		editing.POST("/deleteSection", controllers.Recommendation.DeleteSection)
 	}
 
 	// Dashboard routes
-	dashboard := api.Group("/dashboard")
+	dashboard := api.Group("/dashboard", requireAuth)
 	{
 		dashboard.POST("/completed-tests", controllers.Dashboard.GetCompletedTests)
-		dashboard.POST("/users", controllers.Dashboard.GetUsersData)
 		dashboard.POST("/user-answers", controllers.Dashboard.GetUserAnswers)
-		dashboard.POST("/block-user", controllers.Dashboard.BlockUser)
-		dashboard.POST("/delete-user", controllers.Dashboard.DeleteUser)
+		dashboard.POST("/psychotype-history", controllers.Dashboard.GetPsychoTypeHistory)
+		dashboard.POST("/attempt-history", controllers.Dashboard.GetAttemptHistory)
 		dashboard.POST("/delete-account", controllers.Dashboard.DeleteAccount)
 		dashboard.POST("/change-user-data", controllers.Dashboard.ChangeUserData)
-		dashboard.POST("/terminal", controllers.Dashboard.TerminalCommands)
+
+		users := dashboard.Group("", RequirePermission(entity.PermissionUsersManage))
+		users.POST("/users", controllers.Dashboard.GetUsersData)
+		users.POST("/block-user", controllers.Dashboard.BlockUser)
+		users.POST("/delete-user", controllers.Dashboard.DeleteUser)
+		users.POST("/change-role", controllers.Dashboard.ChangeUserRole)
+
+		analytics := dashboard.Group("", RequirePermission(entity.PermissionAnswersReadAll))
+		analytics.POST("/item-analysis", controllers.Dashboard.GetItemAnalysis)
+		analytics.POST("/norms", controllers.Dashboard.GetNorms)
+		analytics.POST("/norms/build", controllers.Dashboard.BuildNorms)
+		analytics.POST("/norms/freeze", controllers.Dashboard.FreezeNorms)
+
+		dashboard.POST("/terminal", RequirePermission(entity.PermissionTerminal), controllers.Dashboard.TerminalCommands)
 	}
 
 	return router
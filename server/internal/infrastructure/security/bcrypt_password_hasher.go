@@ -0,0 +1,70 @@
+package security
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	domainErrors "server/internal/domain/errors"
+)
+
+// BcryptPasswordHasher хеширует пароли с помощью bcrypt и понимает
+// устаревшие пароли, сохраненные открытым текстом
+type BcryptPasswordHasher struct {
+	cost int
+}
+
+// NewBcryptPasswordHasher создает хешер с указанной стоимостью bcrypt
+func NewBcryptPasswordHasher(cost int) *BcryptPasswordHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptPasswordHasher{cost: cost}
+}
+
+// Hash реализует интерфейс service.PasswordHasher
+func (h *BcryptPasswordHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+			return "", domainErrors.ErrInvalidInput
+		}
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify реализует интерфейс service.PasswordHasher
+func (h *BcryptPasswordHasher) Verify(stored, password string) bool {
+	if stored == "" {
+		return false
+	}
+
+	if !isBcryptHash(stored) {
+		// Устаревший документ: пароль хранится открытым текстом
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) == nil
+}
+
+// NeedsRehash реализует интерфейс service.PasswordHasher
+func (h *BcryptPasswordHasher) NeedsRehash(stored string) bool {
+	if !isBcryptHash(stored) {
+		return true
+	}
+
+	cost, err := bcrypt.Cost([]byte(stored))
+	if err != nil {
+		return true
+	}
+	return cost != h.cost
+}
+
+// isBcryptHash проверяет, что значение похоже на хеш bcrypt ($2a$, $2b$, $2y$)
+func isBcryptHash(value string) bool {
+	return len(value) == 60 &&
+		(strings.HasPrefix(value, "$2a$") || strings.HasPrefix(value, "$2b$") || strings.HasPrefix(value, "$2y$"))
+}
@@ -0,0 +1,92 @@
+package security
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/service"
+)
+
+// oauthStatePayload - параметр state в сериализованном виде
+type oauthStatePayload struct {
+	Provider  string `json:"p"`
+	LinkTo    string `json:"l,omitempty"`
+	Nonce     string `json:"n"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// HMACOAuthStateManager подписывает параметр state OAuth2 тем же способом, что и токены доступа.
+// Для state используется отдельный ключ, выводимый из секрета, чтобы state нельзя было
+// выдать за токен доступа и наоборот
+type HMACOAuthStateManager struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewHMACOAuthStateManager создает менеджер state с указанным секретом
+func NewHMACOAuthStateManager(secret []byte) *HMACOAuthStateManager {
+	return &HMACOAuthStateManager{
+		secret: []byte(signHMAC(secret, "oauth-state")),
+		now:    time.Now,
+	}
+}
+
+// Issue реализует интерфейс service.OAuthStateManager
+func (m *HMACOAuthStateManager) Issue(state service.OAuthState) (string, error) {
+	payload, err := json.Marshal(oauthStatePayload{
+		Provider:  string(state.Provider),
+		LinkTo:    state.LinkTo.String(),
+		Nonce:     state.Nonce,
+		ExpiresAt: state.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + signHMAC(m.secret, encodedPayload), nil
+}
+
+// Parse реализует интерфейс service.OAuthStateManager
+func (m *HMACOAuthStateManager) Parse(value string) (service.OAuthState, error) {
+	encodedPayload, signature, found := strings.Cut(strings.TrimSpace(value), ".")
+	if !found || encodedPayload == "" || signature == "" {
+		return service.OAuthState{}, domainErrors.ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(signHMAC(m.secret, encodedPayload))) {
+		return service.OAuthState{}, domainErrors.ErrInvalidToken
+	}
+
+	rawPayload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return service.OAuthState{}, domainErrors.ErrInvalidToken
+	}
+
+	var payload oauthStatePayload
+	if err := json.Unmarshal(rawPayload, &payload); err != nil {
+		return service.OAuthState{}, domainErrors.ErrInvalidToken
+	}
+
+	state := service.OAuthState{
+		Provider:  entity.OAuthProvider(payload.Provider),
+		LinkTo:    entity.UserID(payload.LinkTo),
+		Nonce:     payload.Nonce,
+		ExpiresAt: time.Unix(payload.ExpiresAt, 0),
+	}
+
+	if !state.Provider.IsValid() || state.Nonce == "" {
+		return service.OAuthState{}, domainErrors.ErrInvalidToken
+	}
+
+	if !m.now().Before(state.ExpiresAt) {
+		return service.OAuthState{}, domainErrors.ErrInvalidToken
+	}
+
+	return state, nil
+}
@@ -0,0 +1,102 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/service"
+)
+
+// tokenPayload - полезная нагрузка токена в сериализованном виде
+type tokenPayload struct {
+	UserID    string `json:"uid"`
+	SessionID string `json:"sid"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// HMACTokenManager выпускает токены вида payload.signature, подписанные HMAC-SHA256
+type HMACTokenManager struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewHMACTokenManager создает менеджер токенов с указанным секретом
+func NewHMACTokenManager(secret []byte) *HMACTokenManager {
+	return &HMACTokenManager{
+		secret: secret,
+		now:    time.Now,
+	}
+}
+
+// Issue реализует интерфейс service.TokenManager
+func (m *HMACTokenManager) Issue(claims service.TokenClaims) (string, error) {
+	payload, err := json.Marshal(tokenPayload{
+		UserID:    claims.UserID.String(),
+		SessionID: claims.SessionID.String(),
+		IssuedAt:  claims.IssuedAt.Unix(),
+		ExpiresAt: claims.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + m.sign(encodedPayload), nil
+}
+
+// Parse реализует интерфейс service.TokenManager
+func (m *HMACTokenManager) Parse(token string) (service.TokenClaims, error) {
+	encodedPayload, signature, found := strings.Cut(strings.TrimSpace(token), ".")
+	if !found || encodedPayload == "" || signature == "" {
+		return service.TokenClaims{}, domainErrors.ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(m.sign(encodedPayload))) {
+		return service.TokenClaims{}, domainErrors.ErrInvalidToken
+	}
+
+	rawPayload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return service.TokenClaims{}, domainErrors.ErrInvalidToken
+	}
+
+	var payload tokenPayload
+	if err := json.Unmarshal(rawPayload, &payload); err != nil {
+		return service.TokenClaims{}, domainErrors.ErrInvalidToken
+	}
+
+	claims := service.TokenClaims{
+		UserID:    entity.UserID(payload.UserID),
+		SessionID: entity.SessionID(payload.SessionID),
+		IssuedAt:  time.Unix(payload.IssuedAt, 0),
+		ExpiresAt: time.Unix(payload.ExpiresAt, 0),
+	}
+
+	if claims.UserID.IsEmpty() || claims.SessionID.IsEmpty() {
+		return service.TokenClaims{}, domainErrors.ErrInvalidToken
+	}
+
+	if !m.now().Before(claims.ExpiresAt) {
+		return service.TokenClaims{}, domainErrors.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func (m *HMACTokenManager) sign(encodedPayload string) string {
+	return signHMAC(m.secret, encodedPayload)
+}
+
+// signHMAC возвращает подпись HMAC-SHA256 в кодировке base64url
+func signHMAC(secret []byte, encodedPayload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encodedPayload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -24,28 +24,16 @@ func NewBlockUserUseCase(dashboardRepo repository.DashboardRepository) *BlockUse
 	}
 }
 
-// Execute блокирует пользователя по запросу администратора
+// Execute блокирует пользователя (право users:manage проверяется на уровне маршрута)
 func (uc *BlockUserUseCase) Execute(ctx context.Context, input BlockUserInput) (BlockUserOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
 	defer cancel()
 
-	adminID := strings.TrimSpace(input.AdminID)
 	targetID := strings.TrimSpace(input.TargetID)
-
-	if adminID == "" || targetID == "" {
+	if targetID == "" {
 		return BlockUserOutput{}, domainErrors.ErrInvalidInput
 	}
 
-	// Проверяем права администратора
-	admin, err := uc.dashboardRepo.FindUserByID(ctx, entity.UserID(adminID))
-	if err != nil {
-		return BlockUserOutput{}, err
-	}
-
-	if !admin.IsAdmin() {
-		return BlockUserOutput{}, domainErrors.ErrForbidden
-	}
-
 	// Блокируем пользователя
 	if err := uc.dashboardRepo.UpdateUserStatus(ctx, entity.UserID(targetID), entity.UserStatusBlocked); err != nil {
 		return BlockUserOutput{}, err
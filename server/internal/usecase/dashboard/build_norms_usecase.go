@@ -0,0 +1,165 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/norms"
+	"server/internal/domain/repository"
+)
+
+// BuildNormsUseCase - use case для построения таблицы норм теста по сохраненным попыткам
+type BuildNormsUseCase struct {
+	testRepo       repository.TestRepository
+	userAnswerRepo repository.UserAnswerRepository
+	normRepo       repository.NormRepository
+	timeout        time.Duration
+}
+
+// NewBuildNormsUseCase создает новый экземпляр BuildNormsUseCase
+func NewBuildNormsUseCase(
+	testRepo repository.TestRepository,
+	userAnswerRepo repository.UserAnswerRepository,
+	normRepo repository.NormRepository,
+) *BuildNormsUseCase {
+	return &BuildNormsUseCase{
+		testRepo:       testRepo,
+		userAnswerRepo: userAnswerRepo,
+		normRepo:       normRepo,
+		timeout:        30 * time.Second,
+	}
+}
+
+// Execute строит таблицу норм по попыткам версии теста, попавшим в отбор, и сохраняет ее
+// черновиком. К результатам этой версии теста таблица применяется после закрепления
+func (uc *BuildNormsUseCase) Execute(ctx context.Context, input BuildNormsInput) (entity.NormTable, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return entity.NormTable{}, err
+	}
+	if !caller.User.HasPermission(entity.PermissionAnswersReadAll) {
+		return entity.NormTable{}, domainErrors.ErrForbidden
+	}
+
+	testID := entity.TestID(strings.TrimSpace(input.TestID))
+	if testID.IsEmpty() {
+		return entity.NormTable{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Не передан идентификатор теста",
+			[]domainErrors.FieldError{{Field: "testId", Message: "Обязательное поле"}})
+	}
+
+	if input.TestVersion < 0 {
+		return entity.NormTable{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Некорректная версия теста",
+			[]domainErrors.FieldError{{Field: "testVersion", Message: "Номер версии не может быть отрицательным"}})
+	}
+
+	filter, err := normFilter(input)
+	if err != nil {
+		return entity.NormTable{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	test, err := uc.testRepo.FindByID(ctx, testID)
+	if err != nil {
+		return entity.NormTable{}, err
+	}
+
+	var questionsDoc entity.QuestionsDocument
+	if input.TestVersion > 0 {
+		questionsDoc, err = uc.testRepo.FindQuestionsVersion(ctx, testID, input.TestVersion)
+	} else {
+		questionsDoc, err = uc.testRepo.FindQuestionsByTestID(ctx, testID)
+	}
+	if err != nil {
+		return entity.NormTable{}, err
+	}
+	testVersion := questionsDoc.Version
+
+	answers, err := uc.userAnswerRepo.FindByTestID(ctx, testID)
+	if err != nil {
+		return entity.NormTable{}, err
+	}
+
+	sample := make([]entity.UserAnswer, 0, len(answers))
+	for _, answer := range answers {
+		if answer.TestVersion == testVersion && len(answer.Scores) > 0 && norms.Includes(filter, answer) {
+			sample = append(sample, answer)
+		}
+	}
+	if len(sample) == 0 {
+		return entity.NormTable{}, domainErrors.NewValidationError(domainErrors.ErrEmptyNormSample,
+			fmt.Sprintf("Нет попыток версии %d с баллами по шкалам, подходящих под условия отбора", testVersion))
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		name = fmt.Sprintf("Нормы теста «%s»", test.TestName)
+		if filter.Cohort != "" {
+			name += ": " + filter.Cohort
+		}
+	}
+
+	table := entity.NormTable{
+		TestID:      testID,
+		TestVersion: testVersion,
+		Name:        name,
+		Filter:      filter,
+		Status:      entity.NormStatusDraft,
+		Sample:      len(sample),
+		Scales:      norms.Build(testVersion, sample),
+		CreatedBy:   caller.User.ID,
+		CreatedAt:   time.Now(),
+	}
+	table.ID, err = uc.normRepo.Insert(ctx, table)
+	if err != nil {
+		return entity.NormTable{}, err
+	}
+	return table, nil
+}
+
+// normFilter проверяет условия отбора попыток
+func normFilter(input BuildNormsInput) (entity.NormFilter, error) {
+	filter := entity.NormFilter{
+		From:   input.From,
+		To:     input.To,
+		Cohort: strings.TrimSpace(input.Cohort),
+	}
+
+	var fields []domainErrors.FieldError
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		fields = append(fields, domainErrors.FieldError{Field: "to", Message: "Конец периода раньше начала"})
+	}
+
+	seen := make(map[entity.UserID]struct{}, len(input.UserIDs))
+	for index, raw := range input.UserIDs {
+		userID := entity.UserID(strings.TrimSpace(raw))
+		if userID.IsEmpty() {
+			fields = append(fields, domainErrors.FieldError{
+				Field:   fmt.Sprintf("userIds[%d]", index),
+				Message: "Пустой идентификатор пользователя",
+			})
+			continue
+		}
+		if _, duplicate := seen[userID]; !duplicate {
+			seen[userID] = struct{}{}
+			filter.UserIDs = append(filter.UserIDs, userID)
+		}
+	}
+	if filter.Cohort != "" && len(filter.UserIDs) == 0 {
+		fields = append(fields, domainErrors.FieldError{Field: "userIds", Message: "Укажите участников выборки"})
+	}
+
+	if len(fields) > 0 {
+		return entity.NormFilter{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Некорректные условия отбора попыток", fields)
+	}
+	return filter, nil
+}
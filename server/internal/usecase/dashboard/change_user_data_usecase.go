@@ -5,8 +5,8 @@ import (
 	"strings"
 	"time"
 
-	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
@@ -24,26 +24,30 @@ func NewChangeUserDataUseCase(dashboardRepo repository.DashboardRepository) *Cha
 	}
 }
 
-// Execute обновляет данные пользователя
+// Execute обновляет данные вызывающего пользователя
 func (uc *ChangeUserDataUseCase) Execute(ctx context.Context, input ChangeUserDataInput) (ChangeUserDataOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
 	defer cancel()
 
-	userID := strings.TrimSpace(input.UserID)
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return ChangeUserDataOutput{}, err
+	}
+
 	firstName := strings.TrimSpace(input.FirstName)
 	lastName := strings.TrimSpace(input.LastName)
 
-	if userID == "" || firstName == "" {
+	if firstName == "" {
 		return ChangeUserDataOutput{}, domainErrors.ErrInvalidInput
 	}
 
 	// Обновляем данные пользователя
-	if err := uc.dashboardRepo.UpdateUserData(ctx, entity.UserID(userID), firstName, lastName); err != nil {
+	if err := uc.dashboardRepo.UpdateUserData(ctx, caller.User.ID, firstName, lastName); err != nil {
 		return ChangeUserDataOutput{}, err
 	}
 
 	// Получаем обновленного пользователя
-	updated, err := uc.dashboardRepo.FindUserByID(ctx, entity.UserID(userID))
+	updated, err := uc.dashboardRepo.FindUserByID(ctx, caller.User.ID)
 	if err != nil {
 		return ChangeUserDataOutput{}, err
 	}
@@ -0,0 +1,76 @@
+package dashboard
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// ChangeUserRoleUseCase - use case для назначения роли пользователю
+type ChangeUserRoleUseCase struct {
+	dashboardRepo repository.DashboardRepository
+	timeout       time.Duration
+}
+
+// NewChangeUserRoleUseCase создает новый экземпляр ChangeUserRoleUseCase
+func NewChangeUserRoleUseCase(dashboardRepo repository.DashboardRepository) *ChangeUserRoleUseCase {
+	return &ChangeUserRoleUseCase{
+		dashboardRepo: dashboardRepo,
+		timeout:       5 * time.Second,
+	}
+}
+
+// Execute назначает пользователю роль (право users:manage проверяется на уровне маршрута)
+func (uc *ChangeUserRoleUseCase) Execute(ctx context.Context, input ChangeUserRoleInput) (ChangeUserRoleOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return ChangeUserRoleOutput{}, err
+	}
+
+	targetID := strings.TrimSpace(input.TargetID)
+	role := entity.Role(strings.TrimSpace(strings.ToLower(input.Role)))
+
+	if targetID == "" || !role.IsValid() {
+		return ChangeUserRoleOutput{}, domainErrors.ErrInvalidInput
+	}
+
+	// Администратор не может изменить собственную роль и потерять доступ
+	if entity.UserID(targetID) == caller.User.ID {
+		return ChangeUserRoleOutput{}, domainErrors.ErrForbidden
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	target, err := uc.dashboardRepo.FindUserByID(ctx, entity.UserID(targetID))
+	if err != nil {
+		return ChangeUserRoleOutput{}, err
+	}
+
+	// Статус активного пользователя синхронизируется с ролью для совместимости
+	// с клиентами, которые определяют администратора по статусу
+	status := target.Status
+	if target.IsActive() {
+		status = entity.UserStatusUser
+		if role == entity.RoleAdmin {
+			status = entity.UserStatusAdmin
+		}
+	}
+
+	if err := uc.dashboardRepo.UpdateUserRole(ctx, target.ID, role, status); err != nil {
+		return ChangeUserRoleOutput{}, err
+	}
+
+	// Получаем обновленного пользователя
+	updated, err := uc.dashboardRepo.FindUserByID(ctx, target.ID)
+	if err != nil {
+		return ChangeUserRoleOutput{}, err
+	}
+
+	return ChangeUserRoleOutput{User: updated}, nil
+}
@@ -2,11 +2,10 @@ package dashboard
 
 import (
 	"context"
-	"strings"
 	"time"
 
 	"server/internal/domain/entity"
-	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
@@ -24,24 +23,18 @@ func NewDeleteAccountUseCase(dashboardRepo repository.DashboardRepository) *Dele
 	}
 }
 
-// Execute помечает аккаунт пользователя как удаленный
-func (uc *DeleteAccountUseCase) Execute(ctx context.Context, input DeleteAccountInput) error {
-	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
-	defer cancel()
-
-	userID := strings.TrimSpace(input.UserID)
-	if userID == "" {
-		return domainErrors.ErrInvalidInput
-	}
-
-	// Проверяем существование пользователя
-	_, err := uc.dashboardRepo.FindUserByID(ctx, entity.UserID(userID))
+// Execute помечает аккаунт вызывающего пользователя как удаленный
+func (uc *DeleteAccountUseCase) Execute(ctx context.Context) error {
+	caller, err := identity.RequireCaller(ctx)
 	if err != nil {
 		return err
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
 	// Удаляем аккаунт (помечаем как удаленный)
-	if err := uc.dashboardRepo.UpdateUserStatus(ctx, entity.UserID(userID), entity.UserStatusDeleted); err != nil {
+	if err := uc.dashboardRepo.UpdateUserStatus(ctx, caller.User.ID, entity.UserStatusDeleted); err != nil {
 		return err
 	}
 
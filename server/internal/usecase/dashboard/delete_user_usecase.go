@@ -24,28 +24,16 @@ func NewDeleteUserUseCase(dashboardRepo repository.DashboardRepository) *DeleteU
 	}
 }
 
-// Execute помечает пользователя как удаленного по запросу администратора
+// Execute помечает пользователя как удаленного (право users:manage проверяется на уровне маршрута)
 func (uc *DeleteUserUseCase) Execute(ctx context.Context, input DeleteUserInput) (DeleteUserOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
 	defer cancel()
 
-	adminID := strings.TrimSpace(input.AdminID)
 	targetID := strings.TrimSpace(input.TargetID)
-
-	if adminID == "" || targetID == "" {
+	if targetID == "" {
 		return DeleteUserOutput{}, domainErrors.ErrInvalidInput
 	}
 
-	// Проверяем права администратора
-	admin, err := uc.dashboardRepo.FindUserByID(ctx, entity.UserID(adminID))
-	if err != nil {
-		return DeleteUserOutput{}, err
-	}
-
-	if !admin.IsAdmin() {
-		return DeleteUserOutput{}, domainErrors.ErrForbidden
-	}
-
 	// Удаляем пользователя (помечаем как удаленного)
 	if err := uc.dashboardRepo.UpdateUserStatus(ctx, entity.UserID(targetID), entity.UserStatusDeleted); err != nil {
 		return DeleteUserOutput{}, err
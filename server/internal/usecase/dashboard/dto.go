@@ -1,12 +1,10 @@
 package dashboard
 
-import "server/internal/domain/entity"
+import (
+	"time"
 
-// GetUsersInput - входные данные для получения списка пользователей
-type GetUsersInput struct {
-	AdminID string
-	Status  string
-}
+	"server/internal/domain/entity"
+)
 
 // GetUsersOutput - результат получения списка пользователей
 type GetUsersOutput struct {
@@ -15,7 +13,6 @@ type GetUsersOutput struct {
 
 // BlockUserInput - входные данные для блокировки пользователя
 type BlockUserInput struct {
-	AdminID  string
 	TargetID string
 }
 
@@ -26,7 +23,6 @@ type BlockUserOutput struct {
 
 // DeleteUserInput - входные данные для удаления пользователя
 type DeleteUserInput struct {
-	AdminID  string
 	TargetID string
 }
 
@@ -35,14 +31,19 @@ type DeleteUserOutput struct {
 	User entity.User
 }
 
-// DeleteAccountInput - входные данные для удаления аккаунта
-type DeleteAccountInput struct {
-	UserID string
+// ChangeUserRoleInput - входные данные для изменения роли пользователя
+type ChangeUserRoleInput struct {
+	TargetID string
+	Role     string
+}
+
+// ChangeUserRoleOutput - результат изменения роли пользователя
+type ChangeUserRoleOutput struct {
+	User entity.User
 }
 
 // ChangeUserDataInput - входные данные для изменения данных пользователя
 type ChangeUserDataInput struct {
-	UserID    string
 	FirstName string
 	LastName  string
 }
@@ -52,18 +53,20 @@ type ChangeUserDataOutput struct {
 	User entity.User
 }
 
-// GetCompletedTestsInput - входные данные для получения пройденных тестов
-type GetCompletedTestsInput struct {
-	UserID string
-}
-
-// CompletedTest - информация о пройденном тесте
+// CompletedTest - информация о пройденном тесте. Norms - положение баллов относительно
+// действующих норм версии теста попытки (версия норм NormVersion); пусто, если нормы
+// не закреплены
 type CompletedTest struct {
-	ID       string
-	TestID   string
-	TestName string
-	Result   string
-	Date     string
+	ID          string
+	TestID      string
+	TestName    string
+	Version     int // версия теста, по которой пройдена попытка
+	Result      string
+	Scores      []entity.ScaleScore
+	Dominant    []entity.ScaleID
+	Date        string
+	Norms       []entity.NormScore
+	NormVersion int
 }
 
 // GetCompletedTestsOutput - результат получения пройденных тестов
@@ -71,18 +74,69 @@ type GetCompletedTestsOutput struct {
 	Tests []CompletedTest
 }
 
-// GetUserAnswersInput - входные данные для получения ответов пользователя
+// PsychoTypeHistoryEntry - запись истории психотипа с названием теста
+type PsychoTypeHistoryEntry struct {
+	Assignment entity.PsychoTypeAssignment
+	TestName   string
+}
+
+// GetPsychoTypeHistoryOutput - результат получения истории психотипа
+type GetPsychoTypeHistoryOutput struct {
+	PsychoType string
+	History    []PsychoTypeHistoryEntry
+}
+
+// GetUserAnswersInput - входные данные для получения ответов пользователя.
+// TestID необязателен: тест определяется по прохождению
 type GetUserAnswersInput struct {
 	CompletedTestID string
 	TestID          string
 }
 
-// GetUserAnswersOutput - результат получения ответов пользователя
+// GetUserAnswersOutput - результат получения ответов пользователя.
+// Questions - вопросы той версии теста, по которой пройдена попытка
 type GetUserAnswersOutput struct {
-	Answers   [][]int
+	TestID    entity.TestID
+	Version   int
+	Answers   []entity.QuestionAnswer
 	Questions []entity.Question
 }
 
+// GetAttemptHistoryInput - входные данные для сравнения попыток прохождения теста.
+// UserID необязателен: по умолчанию сравниваются попытки вызывающего пользователя
+type GetAttemptHistoryInput struct {
+	TestID string
+	UserID string
+}
+
+// ScoreChange - баллы шкалы в попытке и их изменение по сравнению с предыдущей.
+// Previous равен nil, если в предыдущей попытке баллов по шкале не было
+type ScoreChange struct {
+	ScaleID      entity.ScaleID
+	Name         string
+	Score        float64
+	Band         string
+	Previous     *float64
+	PreviousBand string
+	Delta        float64
+}
+
+// AttemptHistoryEntry - попытка прохождения теста с изменением баллов
+type AttemptHistoryEntry struct {
+	Answer  entity.UserAnswer
+	Changes []ScoreChange
+}
+
+// GetAttemptHistoryOutput - результат сравнения попыток: попытки от ранних к поздним
+// и Overall - изменение баллов от первой попытки к последней
+type GetAttemptHistoryOutput struct {
+	TestID   entity.TestID
+	TestName string
+	UserID   entity.UserID
+	Attempts []AttemptHistoryEntry
+	Overall  []ScoreChange
+}
+
 // TerminalCommandInput - входные данные для терминальной команды
 type TerminalCommandInput struct {
 	Command string
@@ -101,3 +155,54 @@ type TerminalCommandOutput struct {
 	Command  string
 	Commands []CommandDescription
 }
+
+// GetItemAnalysisInput - входные данные для анализа вопросов теста. Version необязателен:
+// по умолчанию анализируется текущая версия. Refresh пересчитывает анализ, даже если
+// сохраненный актуален
+type GetItemAnalysisInput struct {
+	TestID  string
+	Version int
+	Refresh bool
+}
+
+// GetItemAnalysisOutput - результат анализа вопросов теста. Cached - анализ взят
+// из сохраненного без пересчета
+type GetItemAnalysisOutput struct {
+	TestName  string
+	Questions []entity.Question
+	Analysis  entity.ItemAnalysis
+	Cached    bool
+}
+
+// BuildNormsInput - входные данные для построения таблицы норм. TestVersion - версия
+// теста, по попыткам которой строятся нормы (по умолчанию текущая). From и To ограничивают
+// дату прохождения, UserIDs - участники выборки Cohort; пустые поля не ограничивают отбор
+type BuildNormsInput struct {
+	TestID      string
+	TestVersion int
+	Name        string
+	From        *time.Time
+	To          *time.Time
+	Cohort      string
+	UserIDs     []string
+}
+
+// FreezeNormsInput - входные данные для закрепления таблицы норм
+type FreezeNormsInput struct {
+	NormID string
+}
+
+// GetNormsInput - входные данные для получения таблиц норм теста
+type GetNormsInput struct {
+	TestID string
+}
+
+// GetNormsOutput - таблицы норм теста: закрепленные от новых версий к старым, затем
+// черновики. Active - версия норм, применяемая к результатам текущей версии теста
+// TestVersion; 0 - нормы для нее не закреплены
+type GetNormsOutput struct {
+	TestName    string
+	TestVersion int
+	Tables      []entity.NormTable
+	Active      int
+}
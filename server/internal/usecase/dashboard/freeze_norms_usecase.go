@@ -0,0 +1,51 @@
+package dashboard
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// FreezeNormsUseCase - use case для закрепления таблицы норм как версии норм теста
+type FreezeNormsUseCase struct {
+	normRepo repository.NormRepository
+	timeout  time.Duration
+}
+
+// NewFreezeNormsUseCase создает новый экземпляр FreezeNormsUseCase
+func NewFreezeNormsUseCase(normRepo repository.NormRepository) *FreezeNormsUseCase {
+	return &FreezeNormsUseCase{
+		normRepo: normRepo,
+		timeout:  5 * time.Second,
+	}
+}
+
+// Execute закрепляет черновик таблицы норм под следующим номером версии. С этого
+// момента процентили результатов теста считаются по этой таблице, а сама таблица
+// больше не меняется
+func (uc *FreezeNormsUseCase) Execute(ctx context.Context, input FreezeNormsInput) (entity.NormTable, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return entity.NormTable{}, err
+	}
+	if !caller.User.HasPermission(entity.PermissionAnswersReadAll) {
+		return entity.NormTable{}, domainErrors.ErrForbidden
+	}
+
+	normID := entity.NormTableID(strings.TrimSpace(input.NormID))
+	if normID.IsEmpty() {
+		return entity.NormTable{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Не передан идентификатор таблицы норм",
+			[]domainErrors.FieldError{{Field: "normId", Message: "Обязательное поле"}})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	return uc.normRepo.Freeze(ctx, normID, time.Now())
+}
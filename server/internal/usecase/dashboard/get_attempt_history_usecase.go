@@ -0,0 +1,120 @@
+package dashboard
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// GetAttemptHistoryUseCase - use case для сравнения последовательных попыток
+// прохождения теста одним пользователем
+type GetAttemptHistoryUseCase struct {
+	dashboardRepo repository.DashboardRepository
+	testRepo      repository.TestRepository
+	timeout       time.Duration
+}
+
+// NewGetAttemptHistoryUseCase создает новый экземпляр GetAttemptHistoryUseCase
+func NewGetAttemptHistoryUseCase(
+	dashboardRepo repository.DashboardRepository,
+	testRepo repository.TestRepository,
+) *GetAttemptHistoryUseCase {
+	return &GetAttemptHistoryUseCase{
+		dashboardRepo: dashboardRepo,
+		testRepo:      testRepo,
+		timeout:       5 * time.Second,
+	}
+}
+
+// Execute возвращает попытки пользователя по тесту от ранних к поздним с изменением
+// баллов по шкалам относительно предыдущей попытки
+func (uc *GetAttemptHistoryUseCase) Execute(ctx context.Context, input GetAttemptHistoryInput) (GetAttemptHistoryOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return GetAttemptHistoryOutput{}, err
+	}
+
+	testID := entity.TestID(strings.TrimSpace(input.TestID))
+	if testID.IsEmpty() {
+		return GetAttemptHistoryOutput{}, domainErrors.ErrInvalidInput
+	}
+
+	// Чужие попытки доступны только с правом answers:read-all
+	userID := entity.UserID(strings.TrimSpace(input.UserID))
+	if userID.IsEmpty() {
+		userID = caller.User.ID
+	}
+	if userID != caller.User.ID && !caller.User.HasPermission(entity.PermissionAnswersReadAll) {
+		return GetAttemptHistoryOutput{}, domainErrors.ErrForbidden
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	answers, err := uc.dashboardRepo.FindAttemptsByUserAndTest(ctx, userID, testID)
+	if err != nil {
+		return GetAttemptHistoryOutput{}, err
+	}
+
+	testName := "Неизвестный тест"
+	if test, err := uc.testRepo.FindByID(ctx, testID); err == nil {
+		testName = test.TestName
+	}
+
+	attempts := make([]AttemptHistoryEntry, 0, len(answers))
+	for i, answer := range answers {
+		var previous []entity.ScaleScore
+		if i > 0 {
+			previous = answers[i-1].Scores
+		}
+		attempts = append(attempts, AttemptHistoryEntry{
+			Answer:  answer,
+			Changes: scoreChanges(previous, answer.Scores),
+		})
+	}
+
+	overall := []ScoreChange{}
+	if len(answers) > 1 {
+		overall = scoreChanges(answers[0].Scores, answers[len(answers)-1].Scores)
+	}
+
+	return GetAttemptHistoryOutput{
+		TestID:   testID,
+		TestName: testName,
+		UserID:   userID,
+		Attempts: attempts,
+		Overall:  overall,
+	}, nil
+}
+
+// scoreChanges сравнивает баллы попытки current с баллами более ранней попытки previous.
+// Шкалы сопоставляются по идентификатору: между версиями теста набор шкал может меняться
+func scoreChanges(previous, current []entity.ScaleScore) []ScoreChange {
+	byScale := make(map[entity.ScaleID]entity.ScaleScore, len(previous))
+	for _, score := range previous {
+		byScale[score.ScaleID] = score
+	}
+
+	changes := make([]ScoreChange, 0, len(current))
+	for _, score := range current {
+		change := ScoreChange{
+			ScaleID: score.ScaleID,
+			Name:    score.Name,
+			Score:   score.Score,
+			Band:    score.Band,
+		}
+		if before, ok := byScale[score.ScaleID]; ok {
+			value := before.Score
+			change.Previous = &value
+			change.PreviousBand = before.Band
+			change.Delta = score.Score - before.Score
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
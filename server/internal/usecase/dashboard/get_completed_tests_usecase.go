@@ -2,11 +2,12 @@ package dashboard
 
 import (
 	"context"
-	"strings"
 	"time"
 
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/norms"
 	"server/internal/domain/repository"
 )
 
@@ -14,6 +15,7 @@ import (
 type GetCompletedTestsUseCase struct {
 	dashboardRepo repository.DashboardRepository
 	testRepo      repository.TestRepository
+	normRepo      repository.NormRepository
 	timeout       time.Duration
 }
 
@@ -21,34 +23,43 @@ type GetCompletedTestsUseCase struct {
 func NewGetCompletedTestsUseCase(
 	dashboardRepo repository.DashboardRepository,
 	testRepo repository.TestRepository,
+	normRepo repository.NormRepository,
 ) *GetCompletedTestsUseCase {
 	return &GetCompletedTestsUseCase{
 		dashboardRepo: dashboardRepo,
 		testRepo:      testRepo,
+		normRepo:      normRepo,
 		timeout:       5 * time.Second,
 	}
 }
 
-// Execute возвращает список пройденных тестов пользователя
-func (uc *GetCompletedTestsUseCase) Execute(ctx context.Context, input GetCompletedTestsInput) (GetCompletedTestsOutput, error) {
+// Execute возвращает список пройденных тестов вызывающего пользователя с положением
+// баллов относительно действующих норм тестов
+func (uc *GetCompletedTestsUseCase) Execute(ctx context.Context) (GetCompletedTestsOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return GetCompletedTestsOutput{}, err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
 	defer cancel()
 
-	userID := strings.TrimSpace(input.UserID)
-	if userID == "" {
-		return GetCompletedTestsOutput{}, domainErrors.ErrInvalidInput
-	}
-
 	// Получаем ответы пользователя
-	answers, err := uc.dashboardRepo.FindCompletedTests(ctx, entity.UserID(userID))
+	answers, err := uc.dashboardRepo.FindCompletedTests(ctx, caller.User.ID)
 	if err != nil {
 		return GetCompletedTestsOutput{}, domainErrors.ErrDatabase
 	}
 
-	// Собираем уникальные ID тестов
+	// Собираем уникальные ID тестов и версии, по которым пройдены попытки
+	type testVersion struct {
+		testID  entity.TestID
+		version int
+	}
 	testIDs := make(map[entity.TestID]bool)
+	versions := make(map[testVersion]bool)
 	for _, answer := range answers {
 		testIDs[answer.TestID] = true
+		versions[testVersion{answer.TestID, answer.TestVersion}] = true
 	}
 
 	// Получаем информацию о тестах
@@ -60,6 +71,15 @@ func (uc *GetCompletedTestsUseCase) Execute(ctx context.Context, input GetComple
 		}
 	}
 
+	// Нормы применяются по версии теста попытки; без закрепленных норм результат
+	// показывается без процентилей
+	activeNorms := make(map[testVersion]entity.NormTable)
+	for key := range versions {
+		if table, err := uc.normRepo.FindActive(ctx, key.testID, key.version); err == nil {
+			activeNorms[key] = table
+		}
+	}
+
 	// Формируем результат
 	completed := make([]CompletedTest, 0, len(answers))
 	for _, answer := range answers {
@@ -67,13 +87,21 @@ func (uc *GetCompletedTestsUseCase) Execute(ctx context.Context, input GetComple
 		if testName == "" {
 			testName = "Неизвестный тест"
 		}
-		completed = append(completed, CompletedTest{
+		entry := CompletedTest{
 			ID:       answer.ID.String(),
 			TestID:   answer.TestID.String(),
 			TestName: testName,
+			Version:  answer.TestVersion,
 			Result:   answer.Result,
+			Scores:   answer.Scores,
+			Dominant: answer.Dominant,
 			Date:     answer.Date,
-		})
+		}
+		if table, ok := activeNorms[testVersion{answer.TestID, answer.TestVersion}]; ok {
+			entry.Norms = norms.Apply(table, answer.Scores)
+			entry.NormVersion = table.Version
+		}
+		completed = append(completed, entry)
 	}
 
 	return GetCompletedTestsOutput{Tests: completed}, nil
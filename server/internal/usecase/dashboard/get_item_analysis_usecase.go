@@ -0,0 +1,154 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/psychometrics"
+	"server/internal/domain/repository"
+)
+
+// GetItemAnalysisUseCase - use case для психометрического анализа вопросов теста:
+// распределения ответов, трудности и дискриминативности вопросов и надежности шкал
+type GetItemAnalysisUseCase struct {
+	testRepo       repository.TestRepository
+	userAnswerRepo repository.UserAnswerRepository
+	analysisRepo   repository.ItemAnalysisRepository
+	timeout        time.Duration
+}
+
+// NewGetItemAnalysisUseCase создает новый экземпляр GetItemAnalysisUseCase
+func NewGetItemAnalysisUseCase(
+	testRepo repository.TestRepository,
+	userAnswerRepo repository.UserAnswerRepository,
+	analysisRepo repository.ItemAnalysisRepository,
+) *GetItemAnalysisUseCase {
+	return &GetItemAnalysisUseCase{
+		testRepo:       testRepo,
+		userAnswerRepo: userAnswerRepo,
+		analysisRepo:   analysisRepo,
+		timeout:        30 * time.Second,
+	}
+}
+
+// Execute возвращает анализ вопросов версии теста. Анализ сохраняется и пересчитывается
+// только после появления новых попыток по версии, чтобы не перечитывать все ответы
+// при каждом запросе
+func (uc *GetItemAnalysisUseCase) Execute(ctx context.Context, input GetItemAnalysisInput) (GetItemAnalysisOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return GetItemAnalysisOutput{}, err
+	}
+	if !caller.User.HasPermission(entity.PermissionAnswersReadAll) {
+		return GetItemAnalysisOutput{}, domainErrors.ErrForbidden
+	}
+
+	testID := entity.TestID(strings.TrimSpace(input.TestID))
+	if testID.IsEmpty() || input.Version < 0 {
+		return GetItemAnalysisOutput{}, domainErrors.ErrInvalidInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	test, err := uc.testRepo.FindByID(ctx, testID)
+	if err != nil {
+		return GetItemAnalysisOutput{}, err
+	}
+
+	version := input.Version
+	if version == 0 {
+		version = test.Version
+	}
+
+	doc, err := uc.testRepo.FindQuestionsVersion(ctx, testID, version)
+	if err != nil {
+		return GetItemAnalysisOutput{}, err
+	}
+
+	summary, err := uc.userAnswerRepo.SummarizeByTestVersion(ctx, testID, version)
+	if err != nil {
+		return GetItemAnalysisOutput{}, err
+	}
+
+	output := GetItemAnalysisOutput{TestName: test.TestName, Questions: doc.Questions}
+
+	if !input.Refresh {
+		cached, err := uc.analysisRepo.FindByTestVersion(ctx, testID, version)
+		switch {
+		case err == nil && cached.Source == summary:
+			output.Analysis = cached
+			output.Cached = true
+			return output, nil
+		case err != nil && !errors.Is(err, domainErrors.ErrNotFound):
+			return GetItemAnalysisOutput{}, err
+		}
+	}
+
+	analysis, err := uc.analyze(ctx, doc, testID, version)
+	if err != nil {
+		return GetItemAnalysisOutput{}, err
+	}
+	if err := uc.analysisRepo.Save(ctx, analysis); err != nil {
+		return GetItemAnalysisOutput{}, err
+	}
+
+	output.Analysis = analysis
+	return output, nil
+}
+
+// analyze загружает попытки по версии теста и вычисляет анализ. Сводка попыток
+// определяется по фактически загруженным ответам, чтобы попытка, сохраненная во время
+// расчета, привела к пересчету при следующем запросе
+func (uc *GetItemAnalysisUseCase) analyze(
+	ctx context.Context,
+	doc entity.QuestionsDocument,
+	testID entity.TestID,
+	version int,
+) (entity.ItemAnalysis, error) {
+	answers, err := uc.userAnswerRepo.FindByTestID(ctx, testID)
+	if err != nil {
+		return entity.ItemAnalysis{}, err
+	}
+
+	var source entity.AnswerSummary
+	answerIDs := make([]entity.UserAnswerID, 0, len(answers))
+	for _, answer := range answers {
+		if answer.TestVersion != version {
+			continue
+		}
+		answerIDs = append(answerIDs, answer.ID)
+		source.Count++
+		// ObjectID возрастает со временем создания, и их шестнадцатеричные строки
+		// одной длины сравниваются в том же порядке
+		if answer.ID.String() > source.LastAnswerID.String() {
+			source.LastAnswerID = answer.ID
+		}
+	}
+
+	details, err := uc.userAnswerRepo.FindDetailsByAnswerIDs(ctx, answerIDs)
+	if err != nil {
+		return entity.ItemAnalysis{}, err
+	}
+
+	attempts := make([][]entity.QuestionAnswer, 0, len(details))
+	for _, detail := range details {
+		attempts = append(attempts, detail.Answers)
+	}
+
+	result := psychometrics.Analyze(doc, attempts)
+	return entity.ItemAnalysis{
+		TestID:      testID,
+		Version:     version,
+		Respondents: len(attempts),
+		Questions:   result.Questions,
+		Scales:      result.Scales,
+		Source:      source,
+		ComputedAt:  time.Now(),
+	}, nil
+}
@@ -0,0 +1,71 @@
+package dashboard
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// GetNormsUseCase - use case для получения таблиц норм теста
+type GetNormsUseCase struct {
+	testRepo repository.TestRepository
+	normRepo repository.NormRepository
+	timeout  time.Duration
+}
+
+// NewGetNormsUseCase создает новый экземпляр GetNormsUseCase
+func NewGetNormsUseCase(testRepo repository.TestRepository, normRepo repository.NormRepository) *GetNormsUseCase {
+	return &GetNormsUseCase{
+		testRepo: testRepo,
+		normRepo: normRepo,
+		timeout:  5 * time.Second,
+	}
+}
+
+// Execute возвращает закрепленные таблицы норм теста и черновики с версией норм,
+// применяемой к результатам текущей версии теста
+func (uc *GetNormsUseCase) Execute(ctx context.Context, input GetNormsInput) (GetNormsOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return GetNormsOutput{}, err
+	}
+	if !caller.User.HasPermission(entity.PermissionAnswersReadAll) {
+		return GetNormsOutput{}, domainErrors.ErrForbidden
+	}
+
+	testID := entity.TestID(strings.TrimSpace(input.TestID))
+	if testID.IsEmpty() {
+		return GetNormsOutput{}, domainErrors.ErrInvalidInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	test, err := uc.testRepo.FindByID(ctx, testID)
+	if err != nil {
+		return GetNormsOutput{}, err
+	}
+
+	questionsDoc, err := uc.testRepo.FindQuestionsByTestID(ctx, testID)
+	if err != nil {
+		return GetNormsOutput{}, err
+	}
+
+	tables, err := uc.normRepo.FindByTestID(ctx, testID)
+	if err != nil {
+		return GetNormsOutput{}, err
+	}
+
+	output := GetNormsOutput{TestName: test.TestName, TestVersion: questionsDoc.Version, Tables: tables}
+	for _, table := range tables {
+		if table.IsFrozen() && table.TestVersion == questionsDoc.Version && table.Version > output.Active {
+			output.Active = table.Version
+		}
+	}
+	return output, nil
+}
@@ -0,0 +1,72 @@
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	"server/internal/domain/entity"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// GetPsychoTypeHistoryUseCase - use case для получения истории психотипа пользователя
+type GetPsychoTypeHistoryUseCase struct {
+	dashboardRepo repository.DashboardRepository
+	testRepo      repository.TestRepository
+	timeout       time.Duration
+}
+
+// NewGetPsychoTypeHistoryUseCase создает новый экземпляр GetPsychoTypeHistoryUseCase
+func NewGetPsychoTypeHistoryUseCase(
+	dashboardRepo repository.DashboardRepository,
+	testRepo repository.TestRepository,
+) *GetPsychoTypeHistoryUseCase {
+	return &GetPsychoTypeHistoryUseCase{
+		dashboardRepo: dashboardRepo,
+		testRepo:      testRepo,
+		timeout:       5 * time.Second,
+	}
+}
+
+// Execute возвращает текущий психотип вызывающего пользователя и историю его изменений
+// от новых записей к старым
+func (uc *GetPsychoTypeHistoryUseCase) Execute(ctx context.Context) (GetPsychoTypeHistoryOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return GetPsychoTypeHistoryOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	user, err := uc.dashboardRepo.FindUserByID(ctx, caller.User.ID)
+	if err != nil {
+		return GetPsychoTypeHistoryOutput{}, err
+	}
+
+	// Названия тестов запрашиваются один раз на тест
+	testNames := make(map[entity.TestID]string)
+	history := make([]PsychoTypeHistoryEntry, 0, len(user.PsychoTypeHistory))
+	for i := len(user.PsychoTypeHistory) - 1; i >= 0; i-- {
+		assignment := user.PsychoTypeHistory[i]
+
+		testName, ok := testNames[assignment.TestID]
+		if !ok {
+			testName = "Неизвестный тест"
+			if test, err := uc.testRepo.FindByID(ctx, assignment.TestID); err == nil {
+				testName = test.TestName
+			}
+			testNames[assignment.TestID] = testName
+		}
+
+		history = append(history, PsychoTypeHistoryEntry{
+			Assignment: assignment,
+			TestName:   testName,
+		})
+	}
+
+	return GetPsychoTypeHistoryOutput{
+		PsychoType: user.PsychoType,
+		History:    history,
+	}, nil
+}
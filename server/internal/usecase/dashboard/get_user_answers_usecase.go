@@ -7,6 +7,7 @@ import (
 
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
@@ -34,26 +35,47 @@ func (uc *GetUserAnswersUseCase) Execute(ctx context.Context, input GetUserAnswe
 	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
 	defer cancel()
 
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return GetUserAnswersOutput{}, err
+	}
+
 	completedTestID := strings.TrimSpace(input.CompletedTestID)
-	testID := strings.TrimSpace(input.TestID)
+	testID := entity.TestID(strings.TrimSpace(input.TestID))
+
+	if completedTestID == "" {
+		return GetUserAnswersOutput{}, domainErrors.ErrInvalidInput
+	}
+
+	answer, err := uc.dashboardRepo.FindCompletedTestByID(ctx, entity.UserAnswerID(completedTestID))
+	if err != nil {
+		return GetUserAnswersOutput{}, err
+	}
+
+	// Чужие ответы доступны только с правом answers:read-all
+	if answer.UserID != caller.User.ID && !caller.User.HasPermission(entity.PermissionAnswersReadAll) {
+		return GetUserAnswersOutput{}, domainErrors.ErrForbidden
+	}
 
-	if completedTestID == "" || testID == "" {
+	if !testID.IsEmpty() && testID != answer.TestID {
 		return GetUserAnswersOutput{}, domainErrors.ErrInvalidInput
 	}
 
 	// Получаем детали ответов
-	details, err := uc.dashboardRepo.FindAnswerDetailsByAnswerID(ctx, entity.UserAnswerID(completedTestID))
+	details, err := uc.dashboardRepo.FindAnswerDetailsByAnswerID(ctx, answer.ID)
 	if err != nil {
 		return GetUserAnswersOutput{}, err
 	}
 
-	// Получаем вопросы теста
-	questions, err := uc.dashboardRepo.FindQuestionsByTestID(ctx, entity.TestID(testID))
+	// Ответы показываются по той версии вопросов, на которую они давались
+	questions, err := uc.dashboardRepo.FindQuestionsVersion(ctx, answer.TestID, answer.TestVersion)
 	if err != nil {
 		return GetUserAnswersOutput{}, err
 	}
 
 	return GetUserAnswersOutput{
+		TestID:    answer.TestID,
+		Version:   answer.TestVersion,
 		Answers:   details.Answers,
 		Questions: questions,
 	}, nil
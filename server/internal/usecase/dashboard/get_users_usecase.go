@@ -2,11 +2,10 @@ package dashboard
 
 import (
 	"context"
-	"strings"
 	"time"
 
-	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
@@ -24,34 +23,18 @@ func NewGetUsersUseCase(dashboardRepo repository.DashboardRepository) *GetUsersU
 	}
 }
 
-// Execute возвращает список пользователей для администратора
-func (uc *GetUsersUseCase) Execute(ctx context.Context, input GetUsersInput) (GetUsersOutput, error) {
-	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
-	defer cancel()
-
-	adminID := strings.TrimSpace(input.AdminID)
-	status := strings.TrimSpace(input.Status)
-
-	if adminID == "" || status == "" {
-		return GetUsersOutput{}, domainErrors.ErrInvalidInput
-	}
-
-	if status != string(entity.UserStatusAdmin) {
-		return GetUsersOutput{}, domainErrors.ErrForbidden
-	}
-
-	// Проверяем, что пользователь - администратор
-	admin, err := uc.dashboardRepo.FindUserByID(ctx, entity.UserID(adminID))
+// Execute возвращает список пользователей (право users:manage проверяется на уровне маршрута)
+func (uc *GetUsersUseCase) Execute(ctx context.Context) (GetUsersOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
 	if err != nil {
 		return GetUsersOutput{}, err
 	}
 
-	if !admin.IsAdmin() {
-		return GetUsersOutput{}, domainErrors.ErrForbidden
-	}
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
 
 	// Получаем всех пользователей кроме текущего админа
-	users, err := uc.dashboardRepo.FindUsersExcluding(ctx, entity.UserID(adminID))
+	users, err := uc.dashboardRepo.FindUsersExcluding(ctx, caller.User.ID)
 	if err != nil {
 		return GetUsersOutput{}, domainErrors.ErrDatabase
 	}
@@ -7,6 +7,7 @@ import (
 
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
@@ -29,18 +30,22 @@ func (uc *CreateReviewUseCase) Execute(ctx context.Context, input CreateReviewIn
 	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
 	defer cancel()
 
+	// Автором отзыва становится вызывающий пользователь
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return CreateReviewOutput{}, err
+	}
+
 	// Валидация входных данных
-	userID := strings.TrimSpace(input.UserID)
 	body := strings.TrimSpace(input.ReviewBody)
-
-	if userID == "" || body == "" {
+	if body == "" {
 		return CreateReviewOutput{}, domainErrors.ErrInvalidInput
 	}
 
 	// Создание доменной сущности отзыва
 	review := entity.Review{
 		ID:         entity.ReviewID(""), // Будет установлен репозиторием
-		UserID:     entity.UserID(userID),
+		UserID:     caller.User.ID,
 		ReviewBody: body,
 		Date:       time.Now().Format("02.01.2006"),
 		Status:     entity.ReviewStatusModeration,
@@ -52,10 +57,9 @@ func (uc *CreateReviewUseCase) Execute(ctx context.Context, input CreateReviewIn
 	}
 
 	// Возвращаем созданный отзыв
-	// Примечание: в реальной реализации нужно получить имя автора
 	result := entity.ReviewWithAuthor{
 		Review:     review,
-		AuthorName: "Пользователь", // Получить из базы
+		AuthorName: caller.User.FirstName,
 	}
 
 	return CreateReviewOutput{Review: result}, nil
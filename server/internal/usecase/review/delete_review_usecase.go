@@ -7,6 +7,7 @@ import (
 
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
@@ -29,12 +30,27 @@ func (uc *DeleteReviewUseCase) Execute(ctx context.Context, input DeleteReviewIn
 	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
 	defer cancel()
 
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Валидация
 	reviewID := strings.TrimSpace(input.ReviewID)
 	if reviewID == "" {
 		return domainErrors.ErrInvalidInput
 	}
 
+	// Удалить отзыв может его автор или модератор
+	existing, err := uc.reviewRepo.FindByID(ctx, entity.ReviewID(reviewID))
+	if err != nil {
+		return err
+	}
+
+	if !existing.IsOwnedBy(caller.User.ID) && !caller.User.HasPermission(entity.PermissionReviewsModerate) {
+		return domainErrors.ErrForbidden
+	}
+
 	// Удаление отзыва
 	if err := uc.reviewRepo.Delete(ctx, entity.ReviewID(reviewID)); err != nil {
 		return err
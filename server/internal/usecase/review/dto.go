@@ -9,7 +9,6 @@ type GetReviewsOutput struct {
 
 // CreateReviewInput - входные данные для создания отзыва
 type CreateReviewInput struct {
-	UserID     string
 	ReviewBody string
 }
 
@@ -21,7 +20,6 @@ type CreateReviewOutput struct {
 // UpdateReviewInput - входные данные для обновления отзыва
 type UpdateReviewInput struct {
 	ReviewID   string
-	UserID     string
 	ReviewBody string
 }
 
@@ -33,14 +31,11 @@ type UpdateReviewOutput struct {
 // DeleteReviewInput - входные данные для удаления отзыва
 type DeleteReviewInput struct {
 	ReviewID string
-	UserID   string
-	IsAdmin  bool
 }
 
 // ModerateReviewInput - входные данные для модерации отзыва
 type ModerateReviewInput struct {
 	ReviewID string
-	AdminID  string
 	Decision string // "approve" или "deny"
 }
 
@@ -24,7 +24,7 @@ func NewModerateReviewUseCase(reviewRepo repository.ReviewRepository) *ModerateR
 	}
 }
 
-// Execute одобряет или отклоняет отзыв
+// Execute одобряет или отклоняет отзыв (право reviews:moderate проверяется на уровне маршрута)
 func (uc *ModerateReviewUseCase) Execute(ctx context.Context, input ModerateReviewInput) (ModerateReviewOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
 	defer cancel()
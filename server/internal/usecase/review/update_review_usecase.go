@@ -7,6 +7,7 @@ import (
 
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
@@ -29,15 +30,29 @@ func (uc *UpdateReviewUseCase) Execute(ctx context.Context, input UpdateReviewIn
 	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
 	defer cancel()
 
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return UpdateReviewOutput{}, err
+	}
+
 	// Валидация
 	reviewID := strings.TrimSpace(input.ReviewID)
-	userID := strings.TrimSpace(input.UserID)
 	body := strings.TrimSpace(input.ReviewBody)
 
-	if reviewID == "" || userID == "" || body == "" {
+	if reviewID == "" || body == "" {
 		return UpdateReviewOutput{}, domainErrors.ErrInvalidInput
 	}
 
+	// Редактировать отзыв может только его автор
+	existing, err := uc.reviewRepo.FindByID(ctx, entity.ReviewID(reviewID))
+	if err != nil {
+		return UpdateReviewOutput{}, err
+	}
+
+	if !existing.IsOwnedBy(caller.User.ID) {
+		return UpdateReviewOutput{}, domainErrors.ErrForbidden
+	}
+
 	// Обновление текста отзыва
 	if err := uc.reviewRepo.UpdateText(ctx, entity.ReviewID(reviewID), body); err != nil {
 		return UpdateReviewOutput{}, err
@@ -51,7 +66,7 @@ func (uc *UpdateReviewUseCase) Execute(ctx context.Context, input UpdateReviewIn
 
 	result := entity.ReviewWithAuthor{
 		Review:     review,
-		AuthorName: "Пользователь",
+		AuthorName: caller.User.FirstName,
 	}
 
 	return UpdateReviewOutput{Review: result}, nil
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// canManageTest проверяет, может ли пользователь видеть неопубликованный тест:
+// владелец, соавтор или проверяющий
+func canManageTest(caller identity.Caller, test entity.Test) bool {
+	return test.AccessFor(caller.User) >= entity.TestAccessView ||
+		caller.User.HasPermission(entity.PermissionTestsReview)
+}
+
+// requireTestAccess проверяет, что у пользователя есть доступ к тесту не ниже level
+func requireTestAccess(caller identity.Caller, test entity.Test, level entity.TestAccess) error {
+	if test.AccessFor(caller.User) < level {
+		return domainErrors.ErrForbidden
+	}
+	return nil
+}
+
+// findManagedTest находит тест и проверяет, что доступ пользователя к нему не ниже level.
+// Удаленный тест считается не найденным
+func findManagedTest(
+	ctx context.Context,
+	testRepo repository.TestRepository,
+	caller identity.Caller,
+	testID entity.TestID,
+	level entity.TestAccess,
+) (entity.Test, error) {
+	test, err := testRepo.FindByID(ctx, testID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return entity.Test{}, err
+		}
+		return entity.Test{}, domainErrors.ErrDatabase
+	}
+	if test.IsDeleted() {
+		return entity.Test{}, domainErrors.ErrNotFound
+	}
+	if err := requireTestAccess(caller, test, level); err != nil {
+		return entity.Test{}, err
+	}
+	return test, nil
+}
+
+// findTestAuthor находит пользователя, которому открывается доступ к тесту: он должен
+// быть активен и иметь право tests:write
+func findTestAuthor(ctx context.Context, userRepo repository.UserRepository, userID entity.UserID) (entity.User, error) {
+	user, err := userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrUserNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return entity.User{}, userFieldError("Пользователь не найден")
+		}
+		return entity.User{}, domainErrors.ErrDatabase
+	}
+	if !user.HasPermission(entity.PermissionTestsWrite) {
+		return entity.User{}, userFieldError("Пользователь не может работать с тестами")
+	}
+	return user, nil
+}
+
+// userFieldError возвращает ошибку валидации поля userId
+func userFieldError(message string) error {
+	return domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput, message,
+		[]domainErrors.FieldError{{Field: "userId", Message: message}})
+}
+
+// saveTestAccess сохраняет владельца и соавторов теста, если владелец не сменился
+// с момента чтения теста
+func saveTestAccess(ctx context.Context, testRepo repository.TestRepository, test entity.Test, owner entity.UserID) error {
+	if err := testRepo.UpdateAccess(ctx, test, owner); err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) {
+			return domainErrors.NewValidationError(domainErrors.ErrOwnerChanged,
+				"Владелец теста изменился, обновите страницу")
+		}
+		return domainErrors.ErrDatabase
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// maxCollaborators - наибольшее число соавторов теста
+const maxCollaborators = 20
+
+// AddCollaboratorUseCase - Use Case для приглашения соавтора теста
+type AddCollaboratorUseCase struct {
+	testRepo repository.TestRepository
+	userRepo repository.UserRepository
+}
+
+// NewAddCollaboratorUseCase создает новый экземпляр AddCollaboratorUseCase
+func NewAddCollaboratorUseCase(testRepo repository.TestRepository, userRepo repository.UserRepository) *AddCollaboratorUseCase {
+	return &AddCollaboratorUseCase{
+		testRepo: testRepo,
+		userRepo: userRepo,
+	}
+}
+
+// AddCollaboratorInput - входные данные для AddCollaboratorUseCase.
+// Role: "editor" (по умолчанию) или "viewer"
+type AddCollaboratorInput struct {
+	TestID string
+	UserID string
+	Role   string
+}
+
+// AddCollaboratorOutput - выходные данные AddCollaboratorUseCase
+type AddCollaboratorOutput struct {
+	Test entity.Test
+}
+
+// Execute добавляет соавтора теста или меняет роль уже приглашенного соавтора.
+// Приглашать соавторов может только владелец теста
+func (uc *AddCollaboratorUseCase) Execute(ctx context.Context, input AddCollaboratorInput) (AddCollaboratorOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return AddCollaboratorOutput{}, err
+	}
+
+	testID := entity.TestID(strings.TrimSpace(input.TestID))
+	userID := entity.UserID(strings.TrimSpace(input.UserID))
+	var problems []domainErrors.FieldError
+	if testID.IsEmpty() {
+		problems = append(problems, domainErrors.FieldError{Field: "testId", Message: "Обязательное поле"})
+	}
+	if userID.IsEmpty() {
+		problems = append(problems, domainErrors.FieldError{Field: "userId", Message: "Обязательное поле"})
+	}
+	role := entity.CollaboratorRole(strings.ToLower(strings.TrimSpace(input.Role)))
+	if role == "" {
+		role = entity.CollaboratorEditor
+	}
+	if !role.IsValid() {
+		problems = append(problems, domainErrors.FieldError{Field: "role", Message: "Допустимые значения: editor, viewer"})
+	}
+	if len(problems) > 0 {
+		return AddCollaboratorOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			problems[0].Message, problems)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	test, err := findManagedTest(ctx, uc.testRepo, caller, testID, entity.TestAccessOwner)
+	if err != nil {
+		return AddCollaboratorOutput{}, err
+	}
+	if test.IsOwnedBy(userID) {
+		return AddCollaboratorOutput{}, userFieldError("Пользователь - владелец теста")
+	}
+	if _, err := findTestAuthor(ctx, uc.userRepo, userID); err != nil {
+		return AddCollaboratorOutput{}, err
+	}
+
+	updated := test
+	updated.Collaborators = make([]entity.Collaborator, 0, len(test.Collaborators)+1)
+	invited := false
+	for _, collaborator := range test.Collaborators {
+		if collaborator.UserID == userID {
+			collaborator.Role = role
+			invited = true
+		}
+		updated.Collaborators = append(updated.Collaborators, collaborator)
+	}
+	if !invited {
+		if len(test.Collaborators) >= maxCollaborators {
+			return AddCollaboratorOutput{}, userFieldError(fmt.Sprintf("У теста не может быть больше %d соавторов", maxCollaborators))
+		}
+		updated.Collaborators = append(updated.Collaborators, entity.Collaborator{
+			UserID:  userID,
+			Role:    role,
+			AddedAt: time.Now(),
+		})
+	}
+
+	if err := saveTestAccess(ctx, uc.testRepo, updated, test.UserID); err != nil {
+		return AddCollaboratorOutput{}, err
+	}
+	return AddCollaboratorOutput{Test: updated}, nil
+}
@@ -2,12 +2,12 @@ package test
 
 import (
 	"context"
-	"errors"
 	"strings"
 	"time"
 
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
@@ -25,11 +25,17 @@ func NewAddTestUseCase(testRepo repository.TestRepository) *AddTestUseCase {
 
 // AddTestInput - входные данные для AddTestUseCase
 type AddTestInput struct {
-	TestName    string
-	AuthorsName []string
-	Description string
-	Questions   []QuestionInput
-	UserID      string
+	TestName     string
+	AuthorsName  []string
+	Description  string
+	Category     string
+	Tags         []string
+	Questions    []QuestionInput
+	ResultsLogic ResultsLogicInput
+	IsTyping     bool
+	TimeLimits   TimeLimitsInput
+	Retake       RetakePolicyInput
+	Shuffle      ShuffleInput
 }
 
 // AddTestOutput - выходные данные AddTestUseCase
@@ -37,73 +43,109 @@ type AddTestOutput struct {
 	Test entity.Test
 }
 
-// Execute выполняет Use Case создания нового теста
+// Execute выполняет Use Case создания нового теста. Тест создается черновиком
+// и становится доступен для прохождения после проверки и публикации
 func (uc *AddTestUseCase) Execute(ctx context.Context, input AddTestInput) (AddTestOutput, error) {
+	// Автором теста становится вызывающий пользователь
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return AddTestOutput{}, err
+	}
+
 	// Валидация и нормализация базовых данных
 	testName := strings.TrimSpace(input.TestName)
 	description := strings.TrimSpace(input.Description)
-	userIDStr := strings.TrimSpace(input.UserID)
 	authors := normalizeAuthors(input.AuthorsName)
 
 	if testName == "" || description == "" || len(authors) == 0 {
 		return AddTestOutput{}, domainErrors.ErrInvalidInput
 	}
 
-	if userIDStr == "" {
-		return AddTestOutput{}, errors.New("Не указан идентификатор пользователя")
-	}
-
 	if len(input.Questions) == 0 {
 		return AddTestOutput{}, domainErrors.ErrNoQuestions
 	}
 
-	// Преобразуем строковый ID в доменный тип
-	userID := entity.UserID(userIDStr)
-	if userID.IsEmpty() {
-		return AddTestOutput{}, domainErrors.ErrInvalidID
+	// Нормализация вопросов перед сохранением
+	normalizedQuestions, problems := normalizeQuestionInputs(input.Questions)
+	if len(problems) > 0 {
+		return AddTestOutput{}, questionsError(problems)
 	}
 
-	// Нормализация вопросов перед сохранением
-	normalizedQuestions, errMessage := normalizeQuestionInputs(input.Questions)
-	if errMessage != "" {
-		return AddTestOutput{}, errors.New(errMessage)
+	// Проверка шкал и весов вариантов
+	resultsLogic, err := normalizeResultsLogic(input.ResultsLogic, normalizedQuestions)
+	if err != nil {
+		return AddTestOutput{}, err
+	}
+	if err := validateTyping(input.IsTyping, resultsLogic); err != nil {
+		return AddTestOutput{}, err
+	}
+	settings, settingProblems := normalizeTestSettings(testSettingsInput{
+		TimeLimits: input.TimeLimits,
+		Retake:     input.Retake,
+		Shuffle:    input.Shuffle,
+		Category:   input.Category,
+		Tags:       input.Tags,
+	}, normalizedQuestions)
+	if len(settingProblems) > 0 {
+		return AddTestOutput{}, settingsError(settingProblems)
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Создаем новый тест
-	currentDate := time.Now().Format("02.01.2006")
-	newTest := entity.Test{
-		TestName:      testName,
-		AuthorsName:   authors,
-		QuestionCount: len(normalizedQuestions),
-		Description:   description,
-		Date:          currentDate,
-		Status:        entity.TestStatusPublished,
-		UserID:        userID,
+	newTest, err := insertDraftTest(ctx, uc.testRepo, entity.Test{
+		TestName:    testName,
+		AuthorsName: authors,
+		Description: description,
+		Category:    settings.Category,
+		Tags:        settings.Tags,
+		UserID:      caller.User.ID,
+		IsTyping:    input.IsTyping,
+		Retake:      settings.Retake,
+	}, entity.QuestionsDocument{
+		Questions:    normalizedQuestions,
+		ResultsLogic: resultsLogic,
+		TimeLimits:   settings.TimeLimits,
+		Shuffle:      settings.Shuffle,
+	})
+	if err != nil {
+		return AddTestOutput{}, err
 	}
 
+	return AddTestOutput{Test: newTest}, nil
+}
+
+// insertDraftTest сохраняет новый тест черновиком вместе с первой версией вопросов.
+// В questionsDoc заполняются вопросы, правила подсчета и настройки прохождения
+func insertDraftTest(
+	ctx context.Context,
+	testRepo repository.TestRepository,
+	newTest entity.Test,
+	questionsDoc entity.QuestionsDocument,
+) (entity.Test, error) {
+	newTest.QuestionCount = len(questionsDoc.Questions)
+	newTest.Date = time.Now().Format("02.01.2006")
+	newTest.Status = entity.TestStatusDraft
+	newTest.Version = entity.FirstTestVersion
+
 	// Сохраняем тест
-	newTestID, err := uc.testRepo.Insert(ctx, newTest)
+	newTestID, err := testRepo.Insert(ctx, newTest)
 	if err != nil {
-		return AddTestOutput{}, domainErrors.ErrDatabase
+		return entity.Test{}, domainErrors.ErrDatabase
 	}
 
 	// Сохраняем вопросы теста
-	questionsDoc := entity.QuestionsDocument{
-		ID:           newTestID,
-		TestingID:    newTestID,
-		Questions:    normalizedQuestions,
-		ResultsLogic: "",
-	}
+	questionsDoc.ID = newTestID
+	questionsDoc.TestingID = newTestID
+	questionsDoc.Version = entity.FirstTestVersion
+	questionsDoc.CreatedAt = time.Now()
 
-	if err := uc.testRepo.InsertQuestions(ctx, questionsDoc); err != nil {
-		return AddTestOutput{}, domainErrors.ErrDatabase
+	if err := testRepo.InsertQuestions(ctx, questionsDoc); err != nil {
+		return entity.Test{}, domainErrors.ErrDatabase
 	}
 
 	// Устанавливаем ID созданного теста
 	newTest.ID = newTestID
 
-	return AddTestOutput{Test: newTest}, nil
+	return newTest, nil
 }
@@ -0,0 +1,255 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+// maxTextAnswerLength ограничивает длину свободного ответа, если вопрос не задает свою
+const maxTextAnswerLength = 5000
+
+// answerErrors собирает ошибки валидации ответов по полям
+type answerErrors []domainErrors.FieldError
+
+func (e *answerErrors) add(field, format string, args ...any) {
+	*e = append(*e, domainErrors.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// validateAnswers сверяет ответы с вопросами теста: на каждый вопрос должен быть дан
+// ровно один ответ в формате, соответствующем типу вопроса. Возвращает все найденные
+// ошибки сразу; путь поля указывает на ответ, например "answers[2].optionIds[1]"
+func validateAnswers(doc entity.QuestionsDocument, answers []entity.QuestionAnswer) error {
+	if len(answers) == 0 {
+		return domainErrors.NewValidationError(domainErrors.ErrInvalidAnswers, "Ответы не переданы")
+	}
+	return checkAnswers(doc, answers, true)
+}
+
+// validatePartialAnswers проверяет ответы так же, как validateAnswers, но допускает
+// вопросы без ответа - например, если время на них истекло
+func validatePartialAnswers(doc entity.QuestionsDocument, answers []entity.QuestionAnswer) error {
+	return checkAnswers(doc, answers, false)
+}
+
+// checkAnswers сверяет ответы с вопросами; requireAll - на каждый вопрос нужен ответ.
+// Если в тесте есть переходы, ответ нужен только на вопросы, показанные при данных ответах,
+// а ответы на пропущенные вопросы не принимаются
+func checkAnswers(doc entity.QuestionsDocument, answers []entity.QuestionAnswer, requireAll bool) error {
+	shown := shownQuestions(doc, answers)
+
+	var errs answerErrors
+	answered := make(map[int]struct{}, len(answers))
+	for index, answer := range answers {
+		field := fmt.Sprintf("answers[%d]", index)
+
+		question, ok := doc.Question(answer.QuestionID)
+		if !ok {
+			errs.add(field+".questionId", "Вопрос %d не найден в тесте", answer.QuestionID)
+			continue
+		}
+		if _, duplicate := answered[question.ID]; duplicate {
+			errs.add(field+".questionId", "Ответ на вопрос %d передан несколько раз", question.ID)
+			continue
+		}
+		answered[question.ID] = struct{}{}
+		if !isShown(shown, question.ID) {
+			errs.add(field+".questionId", "Вопрос %d пропущен по условиям перехода", question.ID)
+			continue
+		}
+
+		validateQuestionAnswer(&errs, field, question, answer)
+	}
+
+	for _, question := range doc.Questions {
+		if _, ok := answered[question.ID]; !ok && requireAll && isShown(shown, question.ID) {
+			errs.add("answers", "Нет ответа на вопрос %d", question.ID)
+		}
+	}
+
+	if len(errs) > 0 {
+		return domainErrors.NewFieldValidationError(domainErrors.ErrInvalidAnswers,
+			"Ответы не соответствуют вопросам теста", errs)
+	}
+	return nil
+}
+
+// shownQuestions возвращает вопросы, которые показываются при данных ответах с учетом
+// переходов. Для теста без переходов возвращает nil: показываются все вопросы
+func shownQuestions(doc entity.QuestionsDocument, answers []entity.QuestionAnswer) map[int]struct{} {
+	if !doc.HasBranches() {
+		return nil
+	}
+	route := doc.Route(answers)
+	shown := make(map[int]struct{}, len(route))
+	for _, questionID := range route {
+		shown[questionID] = struct{}{}
+	}
+	return shown
+}
+
+// isShown проверяет, показывается ли вопрос; nil - показываются все вопросы
+func isShown(shown map[int]struct{}, questionID int) bool {
+	if shown == nil {
+		return true
+	}
+	_, ok := shown[questionID]
+	return ok
+}
+
+// routeAnswers отбрасывает ответы на вопросы, пропущенные по условиям перехода. В незавершенной
+// попытке такие ответы остаются, если участник вернулся и изменил ответ, ведущий к переходу
+func routeAnswers(doc entity.QuestionsDocument, answers []entity.QuestionAnswer) []entity.QuestionAnswer {
+	shown := shownQuestions(doc, answers)
+	if shown == nil {
+		return answers
+	}
+	kept := make([]entity.QuestionAnswer, 0, len(answers))
+	for _, answer := range answers {
+		if isShown(shown, answer.QuestionID) {
+			kept = append(kept, answer)
+		}
+	}
+	return kept
+}
+
+// validateAnswer проверяет ответ на один вопрос, например сохраненный в незавершенной
+// попытке. Путь поля указывает на ответ: "answer.optionIds[1]"
+func validateAnswer(doc entity.QuestionsDocument, answer entity.QuestionAnswer) error {
+	var errs answerErrors
+	if question, ok := doc.Question(answer.QuestionID); ok {
+		validateQuestionAnswer(&errs, "answer", question, answer)
+	} else {
+		errs.add("answer.questionId", "Вопрос %d не найден в тесте", answer.QuestionID)
+	}
+
+	if len(errs) > 0 {
+		return domainErrors.NewFieldValidationError(domainErrors.ErrInvalidAnswers,
+			"Ответ не соответствует вопросу теста", errs)
+	}
+	return nil
+}
+
+// validateQuestionAnswer проверяет формат ответа по типу вопроса
+func validateQuestionAnswer(errs *answerErrors, field string, question entity.Question, answer entity.QuestionAnswer) {
+	switch question.SelectType {
+	case entity.QuestionTypeLikert:
+		validateLikertAnswer(errs, field, question, answer)
+	case entity.QuestionTypeText:
+		validateTextAnswer(errs, field, question, answer)
+	case entity.QuestionTypeRanking:
+		validateRankingAnswer(errs, field, question, answer)
+	case entity.QuestionTypeMatrix:
+		validateMatrixAnswer(errs, field, question, answer)
+	default:
+		validateChoiceAnswer(errs, field, question, answer)
+	}
+}
+
+// validateChoiceAnswer проверяет выбор вариантов: в вопросе с выбором одного варианта
+// допускается ровно один вариант, в остальных - хотя бы один
+func validateChoiceAnswer(errs *answerErrors, field string, question entity.Question, answer entity.QuestionAnswer) {
+	if len(answer.OptionIDs) == 0 {
+		errs.add(field+".optionIds", "Не выбран вариант ответа на вопрос %d", question.ID)
+		return
+	}
+	if question.SelectType == entity.QuestionTypeOne && len(answer.OptionIDs) > 1 {
+		errs.add(field+".optionIds", "В вопросе %d можно выбрать только один вариант", question.ID)
+	}
+	validateOptionIDs(errs, field, question, answer.OptionIDs)
+}
+
+// validateRankingAnswer проверяет, что ранжированы все варианты вопроса ровно по одному разу
+func validateRankingAnswer(errs *answerErrors, field string, question entity.Question, answer entity.QuestionAnswer) {
+	if !validateOptionIDs(errs, field, question, answer.OptionIDs) {
+		return
+	}
+	if len(answer.OptionIDs) != len(question.AnswerOptions) {
+		errs.add(field+".optionIds", "В вопросе %d нужно упорядочить все %d вариантов",
+			question.ID, len(question.AnswerOptions))
+	}
+}
+
+// validateOptionIDs проверяет, что варианты существуют и не повторяются
+func validateOptionIDs(errs *answerErrors, field string, question entity.Question, optionIDs []int) bool {
+	valid := true
+	chosen := make(map[int]struct{}, len(optionIDs))
+	for position, optionID := range optionIDs {
+		optionField := fmt.Sprintf("%s.optionIds[%d]", field, position)
+		if _, ok := question.Option(optionID); !ok {
+			errs.add(optionField, "Вариант %d не найден в вопросе %d", optionID, question.ID)
+			valid = false
+			continue
+		}
+		if _, duplicate := chosen[optionID]; duplicate {
+			errs.add(optionField, "Вариант %d выбран несколько раз", optionID)
+			valid = false
+			continue
+		}
+		chosen[optionID] = struct{}{}
+	}
+	return valid
+}
+
+// validateLikertAnswer проверяет, что значение передано и входит в шкалу вопроса
+func validateLikertAnswer(errs *answerErrors, field string, question entity.Question, answer entity.QuestionAnswer) {
+	if answer.Value == nil {
+		errs.add(field+".value", "Не выбрано значение шкалы в вопросе %d", question.ID)
+		return
+	}
+	if question.Likert == nil {
+		errs.add(field+".value", "У вопроса %d не задана шкала", question.ID)
+		return
+	}
+	if !question.Likert.Contains(*answer.Value) {
+		errs.add(field+".value", "Значение в вопросе %d должно быть от %d до %d",
+			question.ID, question.Likert.Min, question.Likert.Max)
+	}
+}
+
+// validateTextAnswer проверяет, что свободный ответ не пустой и не превышает допустимую длину
+func validateTextAnswer(errs *answerErrors, field string, question entity.Question, answer entity.QuestionAnswer) {
+	text := strings.TrimSpace(answer.Text)
+	if text == "" {
+		errs.add(field+".text", "Не заполнен ответ на вопрос %d", question.ID)
+		return
+	}
+
+	limit := question.MaxLength
+	if limit <= 0 {
+		limit = maxTextAnswerLength
+	}
+	if utf8.RuneCountInString(text) > limit {
+		errs.add(field+".text", "Ответ на вопрос %d длиннее %d символов", question.ID, limit)
+	}
+}
+
+// validateMatrixAnswer проверяет, что в каждой строке матрицы выбран ровно один существующий столбец
+func validateMatrixAnswer(errs *answerErrors, field string, question entity.Question, answer entity.QuestionAnswer) {
+	filled := make(map[int]struct{}, len(answer.Cells))
+	for position, cell := range answer.Cells {
+		cellField := fmt.Sprintf("%s.cells[%d]", field, position)
+		if _, ok := question.Row(cell.RowID); !ok {
+			errs.add(cellField, "Строка %d не найдена в вопросе %d", cell.RowID, question.ID)
+			continue
+		}
+		if _, duplicate := filled[cell.RowID]; duplicate {
+			errs.add(cellField, "Строка %d заполнена несколько раз", cell.RowID)
+			continue
+		}
+		filled[cell.RowID] = struct{}{}
+
+		if _, ok := question.Option(cell.OptionID); !ok {
+			errs.add(cellField, "Столбец %d не найден в вопросе %d", cell.OptionID, question.ID)
+		}
+	}
+
+	for _, row := range question.Rows {
+		if _, ok := filled[row.ID]; !ok {
+			errs.add(field+".cells", "Не заполнена строка %d в вопросе %d", row.ID, question.ID)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// defaultAttemptSessionTTL - срок, после которого брошенная попытка истекает, если он не задан
+const defaultAttemptSessionTTL = 7 * 24 * time.Hour
+
+// findActiveSession находит незавершенную попытку вызывающего пользователя
+func findActiveSession(
+	ctx context.Context,
+	sessionRepo repository.AttemptSessionRepository,
+	caller identity.Caller,
+	sessionID string,
+	now time.Time,
+) (entity.AttemptSession, error) {
+	sessionIDStr := strings.TrimSpace(sessionID)
+	if sessionIDStr == "" {
+		return entity.AttemptSession{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Не передан идентификатор попытки",
+			[]domainErrors.FieldError{{Field: "attemptId", Message: "Обязательное поле"}})
+	}
+
+	session, err := sessionRepo.FindByID(ctx, entity.AttemptSessionID(sessionIDStr))
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return entity.AttemptSession{}, err
+		}
+		return entity.AttemptSession{}, domainErrors.ErrDatabase
+	}
+
+	// Продолжить попытку может только начавший ее пользователь
+	if !session.IsOwnedBy(caller.User.ID) {
+		return entity.AttemptSession{}, domainErrors.ErrForbidden
+	}
+	if !session.IsActive(now) {
+		return entity.AttemptSession{}, domainErrors.ErrAttemptClosed
+	}
+
+	return session, nil
+}
+
+// currentAttemptQuestions возвращает вопросы текущей версии теста для новой попытки.
+// Version - версия, которую видел пользователь; новая попытка по прежней версии не
+// принимается, иначе можно обойти ограничения времени и перемешивание текущей версии
+func currentAttemptQuestions(
+	ctx context.Context,
+	testRepo repository.TestRepository,
+	testID entity.TestID,
+	version int,
+) (entity.QuestionsDocument, error) {
+	questionsDoc, err := testRepo.FindQuestionsByTestID(ctx, testID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return entity.QuestionsDocument{}, err
+		}
+		return entity.QuestionsDocument{}, domainErrors.ErrDatabase
+	}
+	if version > 0 && version != questionsDoc.Version {
+		return entity.QuestionsDocument{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Тест изменился, пока вы его проходили: обновите страницу",
+			[]domainErrors.FieldError{{
+				Field:   "version",
+				Message: fmt.Sprintf("Доступна только текущая версия теста %d", questionsDoc.Version),
+			}})
+	}
+	return questionsDoc, nil
+}
+
+// newAttemptLayout выбирает случайный порядок показа вопросов и вариантов ответа
+// для новой попытки по настройкам перемешивания версии теста
+func newAttemptLayout(doc entity.QuestionsDocument) entity.AttemptLayout {
+	var layout entity.AttemptLayout
+
+	if doc.Shuffle.Questions {
+		layout.QuestionOrder = make([]int, 0, len(doc.Questions))
+		for _, question := range doc.Questions {
+			layout.QuestionOrder = append(layout.QuestionOrder, question.ID)
+		}
+		shuffleIDs(layout.QuestionOrder)
+	}
+
+	if doc.Shuffle.Options {
+		layout.OptionOrder = make(map[int][]int)
+		for _, question := range doc.Questions {
+			if !question.SelectType.CanShuffleOptions() || len(question.AnswerOptions) < 2 {
+				continue
+			}
+			order := make([]int, 0, len(question.AnswerOptions))
+			for _, option := range question.AnswerOptions {
+				order = append(order, option.ID)
+			}
+			shuffleIDs(order)
+			layout.OptionOrder[question.ID] = order
+		}
+	}
+
+	return layout
+}
+
+// shuffleIDs перемешивает идентификаторы на месте
+func shuffleIDs(ids []int) {
+	rand.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+}
+
+// sessionTTLOrDefault возвращает срок жизни попытки, подставляя значение по умолчанию
+func sessionTTLOrDefault(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return defaultAttemptSessionTTL
+	}
+	return ttl
+}
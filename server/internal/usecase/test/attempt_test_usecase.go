@@ -8,107 +8,320 @@ import (
 
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/norms"
 	"server/internal/domain/repository"
+	"server/internal/domain/scoring"
 )
 
+// defaultResultText - результат попытки теста, для которого не заданы шкалы
+const defaultResultText = "Результат сохранен"
+
 // AttemptTestUseCase - Use Case для сохранения попытки прохождения теста
 type AttemptTestUseCase struct {
+	testRepo       repository.TestRepository
 	userAnswerRepo repository.UserAnswerRepository
+	userRepo       repository.UserRepository
+	anonymousRepo  repository.AnonymousAttemptRepository
+	normRepo       repository.NormRepository
+	anonymousTTL   time.Duration
 }
 
-// NewAttemptTestUseCase создает новый экземпляр AttemptTestUseCase
-func NewAttemptTestUseCase(userAnswerRepo repository.UserAnswerRepository) *AttemptTestUseCase {
+// NewAttemptTestUseCase создает новый экземпляр AttemptTestUseCase.
+// anonymousTTL - срок хранения попытки без входа в аккаунт до ее привязки
+func NewAttemptTestUseCase(
+	testRepo repository.TestRepository,
+	userAnswerRepo repository.UserAnswerRepository,
+	userRepo repository.UserRepository,
+	anonymousRepo repository.AnonymousAttemptRepository,
+	normRepo repository.NormRepository,
+	anonymousTTL time.Duration,
+) *AttemptTestUseCase {
 	return &AttemptTestUseCase{
+		testRepo:       testRepo,
 		userAnswerRepo: userAnswerRepo,
+		userRepo:       userRepo,
+		anonymousRepo:  anonymousRepo,
+		normRepo:       normRepo,
+		anonymousTTL:   anonymousTTL,
 	}
 }
 
-// AttemptTestInput - входные данные для AttemptTestUseCase
+// AttemptTestInput - входные данные для AttemptTestUseCase.
+// Результат не принимается от клиента: он вычисляется сервером по ответам.
+// Version - версия теста, которую видел пользователь; она должна совпадать
+// с текущей. Если не задана, ответы проверяются по текущей версии
 type AttemptTestInput struct {
 	TestID  string
-	UserID  string
-	Answers [][]int
-	Result  string
+	Version int
+	Answers []entity.QuestionAnswer
 	Date    string
 }
 
-// AttemptTestOutput - выходные данные AttemptTestUseCase
+// AttemptTestOutput - выходные данные AttemptTestUseCase. Для попытки без входа
+// в аккаунт TestingAnswerID пуст, а ClaimToken - токен для ее привязки к аккаунту
+// до ClaimExpiresAt. Norms - положение баллов относительно действующих норм теста
+// версии NormVersion; пусто, если нормы не закреплены
 type AttemptTestOutput struct {
 	TestingAnswerID  entity.UserAnswerID
+	TestVersion      int
 	StoredAnswersLen int
+	Result           string
+	Scores           []entity.ScaleScore
+	Dominant         []entity.ScaleID
+	MatchedRules     []string
+	PsychoType       string // назначенный психотип; пусто, если тест его не определяет
+	ClaimToken       string
+	ClaimExpiresAt   *time.Time
+	Norms            []entity.NormScore
+	NormVersion      int
 }
 
-// Execute выполняет Use Case сохранения попытки прохождения теста
+// Execute выполняет Use Case сохранения попытки прохождения теста. Без входа в аккаунт
+// попытка сохраняется анонимно на ограниченный срок и может быть привязана к аккаунту позже
 func (uc *AttemptTestUseCase) Execute(ctx context.Context, input AttemptTestInput) (AttemptTestOutput, error) {
+	// Ответы сохраняются от имени вызывающего пользователя, если он вошел в аккаунт
+	caller, authenticated := identity.CallerFromContext(ctx)
+
 	// Валидация входных данных
 	testIDStr := strings.TrimSpace(input.TestID)
-	userIDStr := strings.TrimSpace(input.UserID)
-
-	if testIDStr == "" || userIDStr == "" {
-		return AttemptTestOutput{}, errors.New("Не передан идентификатор теста или пользователя")
-	}
-
-	if len(input.Answers) == 0 {
-		return AttemptTestOutput{}, errors.New("Ответы не переданы")
+	if testIDStr == "" {
+		return AttemptTestOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Не передан идентификатор теста",
+			[]domainErrors.FieldError{{Field: "testId", Message: "Обязательное поле"}})
 	}
 
-	// Преобразуем строковые ID в доменные типы
+	// Преобразуем строковый ID в доменный тип
 	testID := entity.TestID(testIDStr)
-	userID := entity.UserID(userIDStr)
-
 	if testID.IsEmpty() {
 		return AttemptTestOutput{}, errors.New("Некорректный идентификатор теста")
 	}
-	if userID.IsEmpty() {
-		return AttemptTestOutput{}, errors.New("Некорректный идентификатор пользователя")
+
+	answerDate := strings.TrimSpace(input.Date)
+	if answerDate == "" {
+		answerDate = time.Now().Format("02.01.2006")
 	}
 
-	// Валидация структуры ответов
-	for _, answer := range input.Answers {
-		if len(answer) < 2 {
-			return AttemptTestOutput{}, errors.New("Каждый ответ должен содержать номер вопроса и выбранные варианты")
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	test, err := uc.testRepo.FindByID(ctx, testID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return AttemptTestOutput{}, err
 		}
+		return AttemptTestOutput{}, domainErrors.ErrDatabase
 	}
 
-	// Подготовка результата и даты
-	resultText := strings.TrimSpace(input.Result)
-	if resultText == "" {
-		resultText = "Результат сохранен"
+	// Проходить можно только опубликованные тесты в период показа
+	now := time.Now()
+	if !test.IsAvailable(now) {
+		return AttemptTestOutput{}, domainErrors.ErrTestUnavailable
+	}
+	// Без аккаунта нельзя проверить прежние попытки, поэтому тест с ограничением
+	// повторного прохождения требует входа
+	if !authenticated && !test.Retake.IsUnlimited() {
+		return AttemptTestOutput{}, domainErrors.NewValidationError(domainErrors.ErrUnauthorized,
+			"Войдите в аккаунт, чтобы пройти тест с ограничением повторного прохождения")
+	}
+	if authenticated {
+		if err := checkRetake(ctx, uc.userAnswerRepo, caller.User.ID, test, now); err != nil {
+			return AttemptTestOutput{}, err
+		}
 	}
 
-	answerDate := strings.TrimSpace(input.Date)
-	if answerDate == "" {
-		answerDate = time.Now().Format("02.01.2006")
+	// Если тест изменился, пока пользователь отвечал, ответы на прежнюю версию
+	// не принимаются: ограничения проверяются по текущей версии
+	questionsDoc, err := currentAttemptQuestions(ctx, uc.testRepo, testID, input.Version)
+	if err != nil {
+		return AttemptTestOutput{}, err
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+	// Время ответов измеряется, а порядок показа сохраняется сервером только в попытке,
+	// начатой через StartAttemptUseCase
+	if questionsDoc.TimeLimits.IsSet() {
+		return AttemptTestOutput{}, domainErrors.NewValidationError(domainErrors.ErrInvalidInput,
+			"Тест с ограничением времени проходится по вопросам: начните попытку через startAttempt")
+	}
+	if questionsDoc.Shuffle.IsSet() {
+		return AttemptTestOutput{}, domainErrors.NewValidationError(domainErrors.ErrInvalidInput,
+			"Вопросы теста перемешиваются для каждой попытки: начните попытку через startAttempt")
+	}
 
-	// Создаем запись о прохождении теста
-	userAnswer := entity.UserAnswer{
-		UserID: userID,
-		TestID: testID,
-		Result: resultText,
-		Date:   answerDate,
+	// Ответы сверяются с вопросами теста до сохранения
+	if err := validateAnswers(questionsDoc, input.Answers); err != nil {
+		return AttemptTestOutput{}, err
+	}
+
+	if !authenticated {
+		return uc.recordAnonymous(ctx, test, questionsDoc, input.Answers, answerDate)
+	}
+	return uc.record(ctx, caller, test, questionsDoc, input.Answers, answerDate)
+}
+
+// record подсчитывает результат проверенных ответов и сохраняет попытку
+func (uc *AttemptTestUseCase) record(
+	ctx context.Context,
+	caller identity.Caller,
+	test entity.Test,
+	questionsDoc entity.QuestionsDocument,
+	answers []entity.QuestionAnswer,
+	answerDate string,
+) (AttemptTestOutput, error) {
+	userAnswer, psychoType := evaluateAttempt(test, questionsDoc, answers, answerDate)
+	userAnswer.UserID = caller.User.ID
+
+	at := userAnswer.CompletedAt
+	if err := reserveRetake(ctx, uc.userAnswerRepo, caller.User.ID, test, at, at); err != nil {
+		return AttemptTestOutput{}, err
+	}
+
+	insertedID, err := uc.save(ctx, userAnswer, answers, psychoType)
+	if err != nil {
+		releaseRetake(ctx, uc.userAnswerRepo, caller.User.ID, test, at)
+		return AttemptTestOutput{}, err
+	}
+
+	output := attemptOutput(userAnswer, len(answers), psychoType)
+	output.TestingAnswerID = insertedID
+	uc.applyNorms(ctx, &output, test.ID)
+	return output, nil
+}
+
+// recordAnonymous подсчитывает результат проверенных ответов и сохраняет попытку без
+// входа в аккаунт. Психотип назначается пользователю при привязке попытки
+func (uc *AttemptTestUseCase) recordAnonymous(
+	ctx context.Context,
+	test entity.Test,
+	questionsDoc entity.QuestionsDocument,
+	answers []entity.QuestionAnswer,
+	answerDate string,
+) (AttemptTestOutput, error) {
+	userAnswer, psychoType := evaluateAttempt(test, questionsDoc, answers, answerDate)
+
+	token, err := newClaimToken()
+	if err != nil {
+		return AttemptTestOutput{}, err
+	}
+
+	now := time.Now()
+	attempt := entity.AnonymousAttempt{
+		ClaimTokenHash: hashClaimToken(token),
+		Answer:         userAnswer,
+		Answers:        answers,
+		PsychoType:     psychoType,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(uc.anonymousTTL),
 	}
+	if _, err := uc.anonymousRepo.Insert(ctx, attempt); err != nil {
+		return AttemptTestOutput{}, err
+	}
+
+	output := attemptOutput(userAnswer, len(answers), psychoType)
+	output.ClaimToken = token
+	output.ClaimExpiresAt = &attempt.ExpiresAt
+	uc.applyNorms(ctx, &output, test.ID)
+	return output, nil
+}
 
+// save сохраняет результат попытки пользователя с ответами на вопросы и назначает
+// ему психотип, если он определен
+func (uc *AttemptTestUseCase) save(
+	ctx context.Context,
+	userAnswer entity.UserAnswer,
+	answers []entity.QuestionAnswer,
+	psychoType string,
+) (entity.UserAnswerID, error) {
 	insertedID, err := uc.userAnswerRepo.Insert(ctx, userAnswer)
 	if err != nil {
-		return AttemptTestOutput{}, domainErrors.ErrDatabase
+		return "", domainErrors.ErrDatabase
 	}
 
 	// Сохраняем детальные ответы пользователя
 	userAnswerDetails := entity.UserAnswerDetails{
 		TestingAnswerID: insertedID,
-		Answers:         input.Answers,
+		Answers:         answers,
 	}
 
 	if err := uc.userAnswerRepo.InsertDetails(ctx, userAnswerDetails); err != nil {
-		return AttemptTestOutput{}, domainErrors.ErrDatabase
+		return "", domainErrors.ErrDatabase
+	}
+
+	// Тест определения психотипа обновляет психотип пользователя
+	if psychoType != "" {
+		assignment := entity.PsychoTypeAssignment{
+			Value:      psychoType,
+			TestID:     userAnswer.TestID,
+			AttemptID:  insertedID,
+			AssignedAt: userAnswer.CompletedAt,
+		}
+		if err := uc.userRepo.AssignPsychoType(ctx, userAnswer.UserID, assignment); err != nil {
+			return "", domainErrors.ErrDatabase
+		}
 	}
 
+	return insertedID, nil
+}
+
+// evaluateAttempt подсчитывает результат проверенных ответов. Возвращает запись
+// о прохождении теста без пользователя и психотип для теста определения психотипа
+func evaluateAttempt(
+	test entity.Test,
+	questionsDoc entity.QuestionsDocument,
+	answers []entity.QuestionAnswer,
+	answerDate string,
+) (entity.UserAnswer, string) {
+	userAnswer := entity.UserAnswer{
+		TestID:      test.ID,
+		TestVersion: questionsDoc.Version,
+		Date:        answerDate,
+		CompletedAt: time.Now(),
+	}
+	scored := scoring.Evaluate(questionsDoc, answers)
+	applyResult(&userAnswer, scored)
+
+	psychoType := ""
+	if test.IsTyping {
+		if value, ok := scoring.PsychoType(questionsDoc.ResultsLogic, scored); ok {
+			psychoType = value
+		}
+	}
+	return userAnswer, psychoType
+}
+
+// attemptOutput переводит результат попытки в выходные данные
+func attemptOutput(userAnswer entity.UserAnswer, storedAnswers int, psychoType string) AttemptTestOutput {
 	return AttemptTestOutput{
-		TestingAnswerID:  insertedID,
-		StoredAnswersLen: len(input.Answers),
-	}, nil
+		TestVersion:      userAnswer.TestVersion,
+		StoredAnswersLen: storedAnswers,
+		Result:           userAnswer.Result,
+		Scores:           userAnswer.Scores,
+		Dominant:         userAnswer.Dominant,
+		MatchedRules:     userAnswer.MatchedRules,
+		PsychoType:       psychoType,
+	}
+}
+
+// applyNorms дополняет результат положением баллов относительно действующих норм
+// версии теста, по которой пройдена попытка.
+// Результат уже сохранен, поэтому без норм или при ошибке их чтения он возвращается
+// без процентилей
+func (uc *AttemptTestUseCase) applyNorms(ctx context.Context, output *AttemptTestOutput, testID entity.TestID) {
+	table, err := uc.normRepo.FindActive(ctx, testID, output.TestVersion)
+	if err != nil {
+		return
+	}
+	output.Norms = norms.Apply(table, output.Scores)
+	output.NormVersion = table.Version
+}
+
+// applyResult переносит результат подсчета в ответ пользователя
+func applyResult(answer *entity.UserAnswer, scored scoring.Result) {
+	answer.Result = scored.Text
+	if answer.Result == "" {
+		answer.Result = defaultResultText
+	}
+	answer.Scores = scored.Scores
+	answer.Dominant = scored.Dominant
+	answer.MatchedRules = scored.MatchedRules
 }
@@ -0,0 +1,177 @@
+package test
+
+import (
+	"fmt"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+// normalizeBranches проверяет правила перехода вопроса: тип вопроса допускает переходы,
+// варианты существуют, задан ровно один исход, а правило без вариантов - последнее.
+// Существование вопросов, к которым ведут переходы, проверяет validateQuestionFlow
+func normalizeBranches(question entity.Question, raw []BranchRuleInput) ([]entity.BranchRule, *questionProblem) {
+	fail := func(field, message string) ([]entity.BranchRule, *questionProblem) {
+		return nil, &questionProblem{Field: field, Message: message}
+	}
+
+	if !question.SelectType.CanBranch() {
+		return fail("branches", fmt.Sprintf("В вопросе %d переходы доступны только для типов one и multiple", question.ID))
+	}
+
+	branches := make([]entity.BranchRule, 0, len(raw))
+	for index, rule := range raw {
+		field := fmt.Sprintf("branches[%d]", index)
+
+		switch {
+		case rule.Finish && rule.GoTo != 0:
+			return fail(field+".goTo", "Нельзя одновременно указать переход и завершение теста")
+		case !rule.Finish && rule.GoTo <= 0:
+			return fail(field+".goTo", "Укажите вопрос для перехода или завершение теста")
+		case rule.GoTo == question.ID:
+			return fail(field+".goTo", fmt.Sprintf("Вопрос %d не может переходить к самому себе", question.ID))
+		}
+
+		optionIDs := make([]int, 0, len(rule.OptionIDs))
+		seen := make(map[int]struct{}, len(rule.OptionIDs))
+		for _, optionID := range rule.OptionIDs {
+			if _, ok := question.Option(optionID); !ok {
+				return fail(field+".optionIds", fmt.Sprintf("Вариант %d не найден в вопросе %d", optionID, question.ID))
+			}
+			if _, duplicate := seen[optionID]; !duplicate {
+				seen[optionID] = struct{}{}
+				optionIDs = append(optionIDs, optionID)
+			}
+		}
+
+		// Правило без вариантов срабатывает при любом ответе, и следующие за ним не проверяются
+		if len(optionIDs) == 0 && index < len(raw)-1 {
+			return fail(field+".optionIds", "Правило без вариантов срабатывает при любом ответе и должно быть последним")
+		}
+
+		branches = append(branches, entity.BranchRule{
+			OptionIDs: optionIDs,
+			GoTo:      rule.GoTo,
+			Finish:    rule.Finish,
+		})
+	}
+	return branches, nil
+}
+
+// flowEdge - возможный переход от вопроса к следующему показанному. Rule - номер правила
+// перехода; -1 - переход к следующему по порядку вопросу
+type flowEdge struct {
+	To   int
+	Rule int
+}
+
+// validateQuestionFlow проверяет переходы между вопросами теста: вопросы для перехода
+// существуют, переходы не образуют циклов и каждый вопрос может быть показан
+func validateQuestionFlow(questions []entity.Question) []questionProblem {
+	positions := make(map[int]int, len(questions))
+	for position, question := range questions {
+		positions[question.ID] = position
+	}
+
+	var problems []questionProblem
+	edges := make([][]flowEdge, len(questions))
+	for position, question := range questions {
+		for index, rule := range question.Branches {
+			if rule.Finish {
+				continue
+			}
+			target, ok := positions[rule.GoTo]
+			if !ok {
+				problems = append(problems, questionProblem{
+					Index:   position,
+					Field:   fmt.Sprintf("branches[%d].goTo", index),
+					Message: fmt.Sprintf("Вопрос %d для перехода не найден в тесте", rule.GoTo),
+				})
+				continue
+			}
+			edges[position] = append(edges[position], flowEdge{To: target, Rule: index})
+		}
+		if fallsThrough(question) && position+1 < len(questions) {
+			edges[position] = append(edges[position], flowEdge{To: position + 1, Rule: -1})
+		}
+	}
+	if len(problems) > 0 || len(questions) == 0 {
+		return problems
+	}
+
+	// Обход в глубину от первого вопроса: ребро к вопросу, который еще обходится,
+	// замыкает цикл; вопросы, до которых обход не дошел, никогда не показываются
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make([]int, len(questions))
+	var visit func(position int)
+	visit = func(position int) {
+		state[position] = inProgress
+		for _, edge := range edges[position] {
+			switch state[edge.To] {
+			case unvisited:
+				visit(edge.To)
+			case inProgress:
+				field := "branches"
+				if edge.Rule >= 0 {
+					field = fmt.Sprintf("branches[%d].goTo", edge.Rule)
+				}
+				problems = append(problems, questionProblem{
+					Index: position,
+					Field: field,
+					Message: fmt.Sprintf("Переход от вопроса %d к вопросу %d образует цикл",
+						questions[position].ID, questions[edge.To].ID),
+				})
+			}
+		}
+		state[position] = done
+	}
+	visit(0)
+
+	for position, question := range questions {
+		if state[position] == unvisited {
+			problems = append(problems, questionProblem{
+				Index:   position,
+				Field:   "id",
+				Message: fmt.Sprintf("Вопрос %d никогда не будет показан: к нему не ведет ни один переход", question.ID),
+			})
+		}
+	}
+	return problems
+}
+
+// fallsThrough проверяет, может ли после вопроса показываться следующий по порядку:
+// это так, если ни одно правило не срабатывает при любом ответе и правила покрывают
+// не все варианты вопроса
+func fallsThrough(question entity.Question) bool {
+	covered := make(map[int]struct{}, len(question.AnswerOptions))
+	for _, rule := range question.Branches {
+		if rule.IsUnconditional() {
+			return false
+		}
+		for _, optionID := range rule.OptionIDs {
+			covered[optionID] = struct{}{}
+		}
+	}
+	return len(question.Branches) == 0 || len(covered) < len(question.AnswerOptions)
+}
+
+// validateShuffle проверяет, что вопросы с переходами не перемешиваются: переходы
+// опираются на порядок вопросов теста
+func validateShuffle(input ShuffleInput, questions []entity.Question) []domainErrors.FieldError {
+	if !input.Questions {
+		return nil
+	}
+	for _, question := range questions {
+		if len(question.Branches) > 0 {
+			return []domainErrors.FieldError{{
+				Field:   "shuffle.questions",
+				Message: "Нельзя перемешивать вопросы теста с переходами между вопросами",
+			}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"testing"
+
+	"server/internal/domain/entity"
+)
+
+// branchQuestion - вопрос с одним ответом, вариантами 1 и 2 и правилами перехода
+func branchQuestion(id int, branches ...entity.BranchRule) entity.Question {
+	return entity.Question{
+		ID:            id,
+		SelectType:    entity.QuestionTypeOne,
+		AnswerOptions: []entity.AnswerOption{{ID: 1}, {ID: 2}},
+		Branches:      branches,
+	}
+}
+
+func TestValidateQuestionFlow(t *testing.T) {
+	tests := []struct {
+		name       string
+		questions  []entity.Question
+		wantFields []string
+	}{
+		{
+			name:      "без переходов",
+			questions: []entity.Question{branchQuestion(1), branchQuestion(2), branchQuestion(3)},
+		},
+		{
+			name: "переход вперед с продолжением по порядку",
+			questions: []entity.Question{
+				branchQuestion(1, entity.BranchRule{OptionIDs: []int{1}, GoTo: 3}),
+				branchQuestion(2),
+				branchQuestion(3),
+			},
+		},
+		{
+			name: "вопрос для перехода не найден",
+			questions: []entity.Question{
+				branchQuestion(1, entity.BranchRule{OptionIDs: []int{1}, GoTo: 7}),
+				branchQuestion(2),
+			},
+			wantFields: []string{"questions[0].branches[0].goTo"},
+		},
+		{
+			name: "цикл через переход назад",
+			questions: []entity.Question{
+				branchQuestion(1),
+				branchQuestion(2),
+				branchQuestion(3, entity.BranchRule{GoTo: 2}),
+			},
+			wantFields: []string{"questions[2].branches[0].goTo"},
+		},
+		{
+			name: "цикл через продолжение по порядку",
+			questions: []entity.Question{
+				branchQuestion(1),
+				branchQuestion(2, entity.BranchRule{OptionIDs: []int{1}, GoTo: 3}),
+				branchQuestion(3, entity.BranchRule{OptionIDs: []int{1}, Finish: true}, entity.BranchRule{OptionIDs: []int{2}, GoTo: 2}),
+			},
+			wantFields: []string{"questions[2].branches[1].goTo"},
+		},
+		{
+			name: "правила покрывают все варианты - следующий вопрос не показывается",
+			questions: []entity.Question{
+				branchQuestion(1, entity.BranchRule{OptionIDs: []int{1, 2}, GoTo: 3}),
+				branchQuestion(2),
+				branchQuestion(3),
+			},
+			wantFields: []string{"questions[1].id"},
+		},
+		{
+			name: "безусловное завершение",
+			questions: []entity.Question{
+				branchQuestion(1, entity.BranchRule{Finish: true}),
+				branchQuestion(2),
+			},
+			wantFields: []string{"questions[1].id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := questionFieldErrors(validateQuestionFlow(tt.questions))
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("problems = %v; want %v", fields, tt.wantFields)
+			}
+			for i, field := range fields {
+				if field.Field != tt.wantFields[i] {
+					t.Errorf("problem %d in %s; want %s", i, field.Field, tt.wantFields[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFallsThrough(t *testing.T) {
+	tests := []struct {
+		name     string
+		question entity.Question
+		want     bool
+	}{
+		{name: "без правил", question: branchQuestion(1), want: true},
+		{name: "часть вариантов", question: branchQuestion(1, entity.BranchRule{OptionIDs: []int{1}, GoTo: 3}), want: true},
+		{
+			name: "все варианты",
+			question: branchQuestion(1,
+				entity.BranchRule{OptionIDs: []int{1}, GoTo: 3},
+				entity.BranchRule{OptionIDs: []int{2}, Finish: true}),
+			want: false,
+		},
+		{name: "безусловное правило", question: branchQuestion(1, entity.BranchRule{GoTo: 3}), want: false},
+	}
+
+	for _, tt := range tests {
+		if got := fallsThrough(tt.question); got != tt.want {
+			t.Errorf("%s: fallsThrough = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// ChangeTestStatusUseCase - Use Case для перевода теста по жизненному циклу:
+// черновик -> проверка -> публикация -> архив
+type ChangeTestStatusUseCase struct {
+	testRepo repository.TestRepository
+}
+
+// NewChangeTestStatusUseCase создает новый экземпляр ChangeTestStatusUseCase
+func NewChangeTestStatusUseCase(testRepo repository.TestRepository) *ChangeTestStatusUseCase {
+	return &ChangeTestStatusUseCase{
+		testRepo: testRepo,
+	}
+}
+
+// ChangeTestStatusInput - входные данные для ChangeTestStatusUseCase.
+// PublishAt и UnpublishAt учитываются при публикации (approve) и смене периода
+// показа (schedule); Comment обязателен при отклонении (reject)
+type ChangeTestStatusInput struct {
+	TestID      string
+	Action      string
+	PublishAt   *time.Time
+	UnpublishAt *time.Time
+	Comment     string
+}
+
+// ChangeTestStatusOutput - выходные данные ChangeTestStatusUseCase
+type ChangeTestStatusOutput struct {
+	Test entity.Test
+}
+
+// Execute выполняет переход теста в новый статус. Действия проверки доступны
+// только с правом tests:review, остальные - также владельцу теста и соавторам-редакторам
+func (uc *ChangeTestStatusUseCase) Execute(ctx context.Context, input ChangeTestStatusInput) (ChangeTestStatusOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return ChangeTestStatusOutput{}, err
+	}
+
+	testIDStr := strings.TrimSpace(input.TestID)
+	if testIDStr == "" {
+		return ChangeTestStatusOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Не передан идентификатор теста",
+			[]domainErrors.FieldError{{Field: "testId", Message: "Обязательное поле"}})
+	}
+	testID := entity.TestID(testIDStr)
+
+	action := entity.TestAction(strings.ToLower(strings.TrimSpace(input.Action)))
+	if !action.IsValid() {
+		return ChangeTestStatusOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Неизвестное действие",
+			[]domainErrors.FieldError{{Field: "action", Message: "Допустимые значения: submit, withdraw, approve, reject, schedule, archive, restore"}})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	test, err := uc.testRepo.FindByID(ctx, testID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return ChangeTestStatusOutput{}, err
+		}
+		return ChangeTestStatusOutput{}, domainErrors.ErrDatabase
+	}
+	if test.IsDeleted() {
+		return ChangeTestStatusOutput{}, domainErrors.ErrNotFound
+	}
+
+	isReviewer := caller.User.HasPermission(entity.PermissionTestsReview)
+	if action.ReviewersOnly() && !isReviewer {
+		return ChangeTestStatusOutput{}, domainErrors.ErrForbidden
+	}
+	if !isReviewer && test.AccessFor(caller.User) < entity.TestAccessEdit {
+		return ChangeTestStatusOutput{}, domainErrors.ErrForbidden
+	}
+
+	status, ok := action.Apply(test.Status)
+	if !ok {
+		return ChangeTestStatusOutput{}, domainErrors.NewValidationError(domainErrors.ErrTestTransition,
+			"Действие недоступно для теста в статусе «"+string(test.Status)+"»")
+	}
+
+	updated := test
+	updated.Status = status
+	switch action {
+	case entity.TestActionApprove, entity.TestActionSchedule:
+		if err := validateSchedule(input.PublishAt, input.UnpublishAt, time.Now()); err != nil {
+			return ChangeTestStatusOutput{}, err
+		}
+		updated.PublishAt = input.PublishAt
+		updated.UnpublishAt = input.UnpublishAt
+		updated.ReviewComment = ""
+	case entity.TestActionReject:
+		comment := strings.TrimSpace(input.Comment)
+		if comment == "" {
+			return ChangeTestStatusOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+				"Укажите причину отклонения",
+				[]domainErrors.FieldError{{Field: "comment", Message: "Обязательное поле"}})
+		}
+		updated.ReviewComment = comment
+	case entity.TestActionRestore:
+		// Восстановленный из архива тест публикуется заново через проверку
+		updated.PublishAt = nil
+		updated.UnpublishAt = nil
+	}
+
+	if err := uc.testRepo.UpdateLifecycle(ctx, updated, test.Status); err != nil {
+		// Статус теста успел измениться параллельным запросом
+		if errors.Is(err, domainErrors.ErrNotFound) {
+			return ChangeTestStatusOutput{}, domainErrors.NewValidationError(domainErrors.ErrTestTransition,
+				"Статус теста изменился, обновите страницу")
+		}
+		return ChangeTestStatusOutput{}, domainErrors.ErrDatabase
+	}
+
+	return ChangeTestStatusOutput{Test: updated}, nil
+}
+
+// validateSchedule проверяет период показа: окончание позже начала и еще не наступило
+func validateSchedule(publishAt, unpublishAt *time.Time, now time.Time) error {
+	if unpublishAt == nil {
+		return nil
+	}
+	if publishAt != nil && !unpublishAt.After(*publishAt) {
+		return domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Окончание показа должно быть позже начала",
+			[]domainErrors.FieldError{{Field: "unpublishAt", Message: "Должно быть позже publishAt"}})
+	}
+	if !unpublishAt.After(now) {
+		return domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Окончание показа уже наступило",
+			[]domainErrors.FieldError{{Field: "unpublishAt", Message: "Должно быть в будущем"}})
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
@@ -30,12 +31,20 @@ type ChangeTestLoadInput struct {
 
 // ChangeTestLoadOutput - выходные данные загрузки теста
 type ChangeTestLoadOutput struct {
-	Test      entity.Test
-	Questions []entity.Question
+	Test       entity.Test
+	Questions  []entity.Question
+	TimeLimits entity.TimeLimits
+	Shuffle    entity.ShuffleSettings
 }
 
-// LoadForEdit загружает данные теста и его вопросы для редактирования
+// LoadForEdit загружает данные теста и его вопросы для редактирования.
+// Неопубликованный тест доступен владельцу, соавторам и проверяющим
 func (uc *ChangeTestUseCase) LoadForEdit(ctx context.Context, input ChangeTestLoadInput) (ChangeTestLoadOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return ChangeTestLoadOutput{}, err
+	}
+
 	// Валидация входных данных
 	testIDStr := strings.TrimSpace(input.TestID)
 	if testIDStr == "" {
@@ -58,6 +67,12 @@ func (uc *ChangeTestUseCase) LoadForEdit(ctx context.Context, input ChangeTestLo
 		}
 		return ChangeTestLoadOutput{}, domainErrors.ErrDatabase
 	}
+	if test.IsDeleted() {
+		return ChangeTestLoadOutput{}, domainErrors.ErrNotFound
+	}
+	if !canManageTest(caller, test) {
+		return ChangeTestLoadOutput{}, domainErrors.ErrForbidden
+	}
 
 	// Получаем вопросы теста
 	questionsDoc, err := uc.testRepo.FindQuestionsByTestID(ctx, testID)
@@ -70,18 +85,27 @@ func (uc *ChangeTestUseCase) LoadForEdit(ctx context.Context, input ChangeTestLo
 	}
 
 	return ChangeTestLoadOutput{
-		Test:      test,
-		Questions: questions,
+		Test:       test,
+		Questions:  questions,
+		TimeLimits: questionsDoc.TimeLimits,
+		Shuffle:    questionsDoc.Shuffle,
 	}, nil
 }
 
 // ChangeTestUpdateInput - входные данные для обновления теста
 type ChangeTestUpdateInput struct {
-	TestID      string
-	TestName    string
-	AuthorsName []string
-	Description string
-	Questions   []QuestionInput
+	TestID       string
+	TestName     string
+	AuthorsName  []string
+	Description  string
+	Category     string
+	Tags         []string
+	Questions    []QuestionInput
+	ResultsLogic ResultsLogicInput
+	IsTyping     bool
+	TimeLimits   TimeLimitsInput
+	Retake       RetakePolicyInput
+	Shuffle      ShuffleInput
 }
 
 // ChangeTestUpdateOutput - выходные данные обновления теста
@@ -89,8 +113,14 @@ type ChangeTestUpdateOutput struct {
 	Test entity.Test
 }
 
-// Update обновляет тест и его вопросы
+// Update обновляет тест и его вопросы. Изменять тест могут владелец и соавторы-редакторы,
+// и только пока тест - черновик: изменения публикуются заново через проверку
 func (uc *ChangeTestUseCase) Update(ctx context.Context, input ChangeTestUpdateInput) (ChangeTestUpdateOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return ChangeTestUpdateOutput{}, err
+	}
+
 	// Валидация и нормализация базовых данных
 	testIDStr := strings.TrimSpace(input.TestID)
 	if testIDStr == "" {
@@ -115,9 +145,28 @@ func (uc *ChangeTestUseCase) Update(ctx context.Context, input ChangeTestUpdateI
 	}
 
 	// Нормализация вопросов перед сохранением
-	normalizedQuestions, errMessage := normalizeQuestionInputs(input.Questions)
-	if errMessage != "" {
-		return ChangeTestUpdateOutput{}, errors.New(errMessage)
+	normalizedQuestions, problems := normalizeQuestionInputs(input.Questions)
+	if len(problems) > 0 {
+		return ChangeTestUpdateOutput{}, questionsError(problems)
+	}
+
+	// Проверка шкал и весов вариантов
+	resultsLogic, err := normalizeResultsLogic(input.ResultsLogic, normalizedQuestions)
+	if err != nil {
+		return ChangeTestUpdateOutput{}, err
+	}
+	if err := validateTyping(input.IsTyping, resultsLogic); err != nil {
+		return ChangeTestUpdateOutput{}, err
+	}
+	settings, settingProblems := normalizeTestSettings(testSettingsInput{
+		TimeLimits: input.TimeLimits,
+		Retake:     input.Retake,
+		Shuffle:    input.Shuffle,
+		Category:   input.Category,
+		Tags:       input.Tags,
+	}, normalizedQuestions)
+	if len(settingProblems) > 0 {
+		return ChangeTestUpdateOutput{}, settingsError(settingProblems)
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -131,31 +180,71 @@ func (uc *ChangeTestUseCase) Update(ctx context.Context, input ChangeTestUpdateI
 		}
 		return ChangeTestUpdateOutput{}, domainErrors.ErrDatabase
 	}
+	if existingTest.IsDeleted() {
+		return ChangeTestUpdateOutput{}, domainErrors.ErrNotFound
+	}
+	if err := requireTestAccess(caller, existingTest, entity.TestAccessEdit); err != nil {
+		return ChangeTestUpdateOutput{}, err
+	}
+	if !existingTest.IsDraft() {
+		return ChangeTestUpdateOutput{}, domainErrors.NewValidationError(domainErrors.ErrTestNotEditable,
+			"Изменять можно только черновик: отзовите тест с проверки или перенесите опубликованный тест в архив и восстановите")
+	}
+
+	// Каждое изменение создает новую версию вопросов: прежние версии остаются
+	// неизменными, и по ним показываются уже пройденные попытки. Сначала
+	// сохраняются вопросы новой версии, и только потом тест переключается на нее,
+	// поэтому сбой посередине не оставляет тест без вопросов
+	lastVersion, err := uc.testRepo.LastQuestionsVersion(ctx, testID)
+	if err != nil && !errors.Is(err, domainErrors.ErrNotFound) {
+		return ChangeTestUpdateOutput{}, domainErrors.ErrDatabase
+	}
+	version := max(existingTest.Version, lastVersion) + 1
+
+	questionsDoc := entity.QuestionsDocument{
+		TestingID:    testID,
+		Questions:    normalizedQuestions,
+		ResultsLogic: resultsLogic,
+		Version:      version,
+		CreatedAt:    time.Now(),
+		TimeLimits:   settings.TimeLimits,
+		Shuffle:      settings.Shuffle,
+	}
+
+	if err := uc.testRepo.InsertQuestions(ctx, questionsDoc); err != nil {
+		if errors.Is(err, domainErrors.ErrVersionExists) {
+			return ChangeTestUpdateOutput{}, testChangedError()
+		}
+		return ChangeTestUpdateOutput{}, domainErrors.ErrDatabase
+	}
 
 	// Обновляем поля теста
 	updatedTest := existingTest
 	updatedTest.TestName = testName
 	updatedTest.Description = description
 	updatedTest.AuthorsName = authors
+	updatedTest.Category = settings.Category
+	updatedTest.Tags = settings.Tags
 	updatedTest.QuestionCount = len(normalizedQuestions)
+	updatedTest.IsTyping = input.IsTyping
+	updatedTest.Retake = settings.Retake
 	updatedTest.Date = time.Now().Format("02.01.2006")
+	updatedTest.Version = version
 
 	// Сохраняем обновленные данные теста
-	if err := uc.testRepo.UpdateTest(ctx, updatedTest); err != nil {
-		return ChangeTestUpdateOutput{}, domainErrors.ErrDatabase
-	}
-
-	// Обновляем вопросы теста (upsert)
-	questionsDoc := entity.QuestionsDocument{
-		ID:           testID,
-		TestingID:    testID,
-		Questions:    normalizedQuestions,
-		ResultsLogic: "",
-	}
-
-	if err := uc.testRepo.UpsertQuestions(ctx, questionsDoc); err != nil {
+	if err := uc.testRepo.UpdateTest(ctx, updatedTest, existingTest.Version); err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) {
+			return ChangeTestUpdateOutput{}, testChangedError()
+		}
 		return ChangeTestUpdateOutput{}, domainErrors.ErrDatabase
 	}
 
 	return ChangeTestUpdateOutput{Test: updatedTest}, nil
 }
+
+// testChangedError - ошибка изменения теста, который параллельно изменили или
+// отправили на проверку
+func testChangedError() error {
+	return domainErrors.NewValidationError(domainErrors.ErrTestNotEditable,
+		"Тест изменился, обновите страницу")
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// ClaimAttemptUseCase - Use Case для привязки попытки, пройденной без входа в аккаунт,
+// к аккаунту пользователя
+type ClaimAttemptUseCase struct {
+	anonymousRepo repository.AnonymousAttemptRepository
+	attempts      *AttemptTestUseCase
+}
+
+// NewClaimAttemptUseCase создает новый экземпляр ClaimAttemptUseCase.
+// Результат сохраняется так же, как при прохождении теста через attempts
+func NewClaimAttemptUseCase(
+	anonymousRepo repository.AnonymousAttemptRepository,
+	attempts *AttemptTestUseCase,
+) *ClaimAttemptUseCase {
+	return &ClaimAttemptUseCase{
+		anonymousRepo: anonymousRepo,
+		attempts:      attempts,
+	}
+}
+
+// ClaimAttemptInput - входные данные для ClaimAttemptUseCase
+type ClaimAttemptInput struct {
+	ClaimToken string
+}
+
+// ClaimAttemptOutput - выходные данные ClaimAttemptUseCase
+type ClaimAttemptOutput struct {
+	TestingAnswerID entity.UserAnswerID
+	TestID          entity.TestID
+	TestVersion     int
+	PsychoType      string // назначенный психотип; пусто, если тест его не определяет
+}
+
+// Execute переносит попытку в историю вызывающего пользователя: результат сохраняется
+// с исходными датой и временем прохождения, а для теста определения психотипа
+// пользователю назначается психотип. Попытка привязывается только один раз и только
+// если правило повторного прохождения теста позволяет пользователю пройти его сейчас
+func (uc *ClaimAttemptUseCase) Execute(ctx context.Context, input ClaimAttemptInput) (ClaimAttemptOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return ClaimAttemptOutput{}, err
+	}
+
+	token := strings.TrimSpace(input.ClaimToken)
+	if token == "" {
+		return ClaimAttemptOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Не передан токен попытки",
+			[]domainErrors.FieldError{{Field: "claimToken", Message: "Обязательное поле"}})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	attempt, err := uc.anonymousRepo.TakeByTokenHash(ctx, hashClaimToken(token))
+	if err != nil {
+		return ClaimAttemptOutput{}, err
+	}
+	if attempt.IsExpired(now) {
+		return ClaimAttemptOutput{}, domainErrors.ErrInvalidToken
+	}
+
+	// Без проверки тест с ограничением повторного прохождения можно было бы пройти
+	// без входа сколько угодно раз и привязать все результаты
+	test, err := uc.attempts.testRepo.FindByID(ctx, attempt.Answer.TestID)
+	if err != nil {
+		uc.restore(ctx, attempt)
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return ClaimAttemptOutput{}, err
+		}
+		return ClaimAttemptOutput{}, domainErrors.ErrDatabase
+	}
+	if err := checkRetake(ctx, uc.attempts.userAnswerRepo, caller.User.ID, test, now); err != nil {
+		uc.restore(ctx, attempt)
+		return ClaimAttemptOutput{}, err
+	}
+
+	// Результат сохраняется под ID попытки: если сохранение прервется после записи
+	// результата, повторная привязка дополнит его, а не создаст второй
+	userAnswer := attempt.Answer
+	userAnswer.ID = entity.UserAnswerID(attempt.ID)
+	userAnswer.UserID = caller.User.ID
+
+	at := userAnswer.CompletedAt
+	if err := reserveRetake(ctx, uc.attempts.userAnswerRepo, caller.User.ID, test, at, now); err != nil {
+		uc.restore(ctx, attempt)
+		return ClaimAttemptOutput{}, err
+	}
+
+	insertedID, err := uc.attempts.save(ctx, userAnswer, attempt.Answers, attempt.PsychoType)
+	if err != nil {
+		releaseRetake(ctx, uc.attempts.userAnswerRepo, caller.User.ID, test, at)
+		uc.restore(ctx, attempt)
+		return ClaimAttemptOutput{}, err
+	}
+
+	return ClaimAttemptOutput{
+		TestingAnswerID: insertedID,
+		TestID:          userAnswer.TestID,
+		TestVersion:     userAnswer.TestVersion,
+		PsychoType:      attempt.PsychoType,
+	}, nil
+}
+
+// restore возвращает попытку в хранилище, чтобы привязку можно было повторить
+// тем же токеном до истечения срока хранения
+func (uc *ClaimAttemptUseCase) restore(ctx context.Context, attempt entity.AnonymousAttempt) {
+	if _, err := uc.anonymousRepo.Insert(ctx, attempt); err != nil {
+		log.Printf("claim attempt: не удалось вернуть попытку %s в хранилище: %v", attempt.ID, err)
+	}
+}
+
+// newClaimToken генерирует случайный токен привязки попытки
+func newClaimToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashClaimToken возвращает хеш токена привязки, под которым он хранится в базе
+func hashClaimToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// cloneNameSuffix - окончание названия копии, если новое название не задано
+const cloneNameSuffix = " (копия)"
+
+// CloneTestUseCase - Use Case для создания нового теста копией существующего
+type CloneTestUseCase struct {
+	testRepo repository.TestRepository
+}
+
+// NewCloneTestUseCase создает новый экземпляр CloneTestUseCase
+func NewCloneTestUseCase(testRepo repository.TestRepository) *CloneTestUseCase {
+	return &CloneTestUseCase{
+		testRepo: testRepo,
+	}
+}
+
+// CloneTestInput - входные данные для CloneTestUseCase. Version - версия вопросов
+// исходного теста (по умолчанию текущая); TestName - название копии
+// (по умолчанию название исходного теста с пометкой «копия»)
+type CloneTestInput struct {
+	TestID   string
+	Version  int
+	TestName string
+}
+
+// CloneTestOutput - выходные данные CloneTestUseCase
+type CloneTestOutput struct {
+	Test entity.Test
+}
+
+// Execute создает тест-черновик вызывающего пользователя с данными, вопросами,
+// правилами подсчета и настройками прохождения исходного теста. Копировать можно
+// опубликованный тест или тест, который пользователь может просматривать.
+// Соавторы, период показа и результаты проверки не копируются
+func (uc *CloneTestUseCase) Execute(ctx context.Context, input CloneTestInput) (CloneTestOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return CloneTestOutput{}, err
+	}
+
+	testID := entity.TestID(strings.TrimSpace(input.TestID))
+	if testID.IsEmpty() {
+		return CloneTestOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Не передан идентификатор теста",
+			[]domainErrors.FieldError{{Field: "testId", Message: "Обязательное поле"}})
+	}
+	if input.Version < 0 {
+		return CloneTestOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Некорректная версия теста",
+			[]domainErrors.FieldError{{Field: "version", Message: "Номер версии не может быть отрицательным"}})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	source, err := uc.testRepo.FindByID(ctx, testID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return CloneTestOutput{}, err
+		}
+		return CloneTestOutput{}, domainErrors.ErrDatabase
+	}
+	if source.IsDeleted() {
+		return CloneTestOutput{}, domainErrors.ErrNotFound
+	}
+	if !source.IsPublished() && !canManageTest(caller, source) {
+		return CloneTestOutput{}, domainErrors.ErrForbidden
+	}
+
+	var questionsDoc entity.QuestionsDocument
+	if input.Version > 0 {
+		questionsDoc, err = uc.testRepo.FindQuestionsVersion(ctx, testID, input.Version)
+	} else {
+		questionsDoc, err = uc.testRepo.FindQuestionsByTestID(ctx, testID)
+	}
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) {
+			return CloneTestOutput{}, domainErrors.ErrNotFound
+		}
+		return CloneTestOutput{}, domainErrors.ErrDatabase
+	}
+
+	testName := strings.TrimSpace(input.TestName)
+	if testName == "" {
+		testName = source.TestName + cloneNameSuffix
+	}
+
+	// Копия сохраняется отдельными документами теста и вопросов с первой версией,
+	// поэтому дальнейшие изменения копии и исходного теста друг от друга не зависят
+	clone, err := insertDraftTest(ctx, uc.testRepo, entity.Test{
+		TestName:    testName,
+		AuthorsName: source.AuthorsName,
+		Description: source.Description,
+		Category:    source.Category,
+		Tags:        source.Tags,
+		UserID:      caller.User.ID,
+		IsTyping:    source.IsTyping,
+		Retake:      source.Retake,
+		ClonedFrom: &entity.TestOrigin{
+			TestID:  source.ID,
+			Version: questionsDoc.Version,
+		},
+	}, entity.QuestionsDocument{
+		Questions:    questionsDoc.Questions,
+		ResultsLogic: questionsDoc.ResultsLogic,
+		TimeLimits:   questionsDoc.TimeLimits,
+		Shuffle:      questionsDoc.Shuffle,
+	})
+	if err != nil {
+		return CloneTestOutput{}, err
+	}
+
+	return CloneTestOutput{Test: clone}, nil
+}
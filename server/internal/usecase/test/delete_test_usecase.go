@@ -7,6 +7,7 @@ import (
 
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
@@ -32,8 +33,14 @@ type DeleteTestOutput struct {
 	TestID entity.TestID
 }
 
-// Execute выполняет Use Case удаления теста (пометка как удаленного)
+// Execute выполняет Use Case удаления теста (пометка как удаленного).
+// Удалить тест может только его владелец
 func (uc *DeleteTestUseCase) Execute(ctx context.Context, input DeleteTestInput) (DeleteTestOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return DeleteTestOutput{}, err
+	}
+
 	// Валидация входных данных
 	testIDStr := strings.TrimSpace(input.TestID)
 	if testIDStr == "" {
@@ -48,14 +55,17 @@ func (uc *DeleteTestUseCase) Execute(ctx context.Context, input DeleteTestInput)
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Проверяем существование теста
-	_, err := uc.testRepo.FindByID(ctx, testID)
+	// Проверяем существование теста и права на него
+	test, err := uc.testRepo.FindByID(ctx, testID)
 	if err != nil {
 		if err == domainErrors.ErrNotFound {
 			return DeleteTestOutput{}, domainErrors.ErrNotFound
 		}
 		return DeleteTestOutput{}, domainErrors.ErrDatabase
 	}
+	if err := requireTestAccess(caller, test, entity.TestAccessOwner); err != nil {
+		return DeleteTestOutput{}, err
+	}
 
 	// Помечаем тест как удаленный
 	if err := uc.testRepo.UpdateStatus(ctx, testID, entity.TestStatusDeleted); err != nil {
@@ -1,6 +1,10 @@
 package test
 
-import "server/internal/domain/entity"
+import (
+	"time"
+
+	"server/internal/domain/entity"
+)
 
 // QuestionInput описывает входной формат вопроса для нормализации
 type QuestionInput struct {
@@ -9,16 +13,117 @@ type QuestionInput struct {
 	Body       string
 	Options    []AnswerOptionInput
 	SelectType string
+	Likert     *LikertInput
+	Rows       []MatrixRowInput
+	MaxLength  int
+	Weights    map[string]float64
+	Branches   []BranchRuleInput
+}
+
+// BranchRuleInput описывает входной формат правила перехода: при выборе одного из вариантов
+// OptionIDs (пустой список - при любом ответе) следующим показывается вопрос GoTo,
+// а при Finish тест завершается
+type BranchRuleInput struct {
+	OptionIDs []int
+	GoTo      int
+	Finish    bool
+}
+
+// LikertInput описывает входной формат настроек шкалы Лайкерта
+type LikertInput struct {
+	Min      int
+	Max      int
+	MinLabel string
+	MaxLabel string
+}
+
+// MatrixRowInput описывает входной формат строки матричного вопроса
+type MatrixRowInput struct {
+	ID   int
+	Body string
 }
 
 // AnswerOptionInput описывает входной формат варианта ответа
 type AnswerOptionInput struct {
-	ID   int    `json:"id"`
-	Body string `json:"body"`
+	ID      int                `json:"id"`
+	Body    string             `json:"body"`
+	Weights map[string]float64 `json:"weights"`
+}
+
+// ResultsLogicInput описывает входной формат правил подсчета и интерпретации результатов
+type ResultsLogicInput struct {
+	Scales   []ScaleInput
+	Rules    []ResultRuleInput
+	RuleMode string
+	TieBreak string
+}
+
+// ScaleInput описывает входной формат шкалы теста
+type ScaleInput struct {
+	ID          string
+	Name        string
+	Description string
+	Bands       []ScoreBandInput
+}
+
+// ScoreBandInput описывает входной формат диапазона баллов шкалы
+type ScoreBandInput struct {
+	Min   float64
+	Max   float64
+	Label string
+	Text  string
 }
 
-// TestWithCompletionDTO - DTO для теста с флагом завершения
+// ResultRuleInput описывает входной формат правила интерпретации
+type ResultRuleInput struct {
+	ID         string
+	Conditions []RuleConditionInput
+	Text       string
+	Priority   int
+}
+
+// RuleConditionInput описывает входной формат условия правила интерпретации
+type RuleConditionInput struct {
+	Scale    string
+	Band     string
+	Min      *float64
+	Max      *float64
+	Above    string
+	Dominant bool
+}
+
+// TimeLimitsInput описывает входной формат ограничений времени в секундах; 0 - без ограничения
+type TimeLimitsInput struct {
+	TotalSeconds    int
+	QuestionSeconds int
+}
+
+// ShuffleInput описывает входной формат перемешивания вопросов и вариантов ответа
+type ShuffleInput struct {
+	Questions bool
+	Options   bool
+}
+
+// RetakePolicyInput описывает входной формат правила повторного прохождения.
+// Mode: unlimited (по умолчанию), once или cooldown; CooldownDays - только для cooldown
+type RetakePolicyInput struct {
+	Mode         string
+	CooldownDays int
+}
+
+// TestWithCompletionDTO - DTO для теста с флагом завершения. CanAttempt сообщает,
+// можно ли пройти тест сейчас по правилу повторного прохождения; NextAttemptAt -
+// когда станет можно, если повторное прохождение ограничено сроком
 type TestWithCompletionDTO struct {
-	Test        entity.Test
-	IsCompleted bool
+	Test          entity.Test
+	IsCompleted   bool
+	CanAttempt    bool
+	NextAttemptAt *time.Time
+}
+
+// ManagedTestDTO - тест в списке управляемых тестов вместе с уровнем доступа
+// к нему вызывающего пользователя
+type ManagedTestDTO struct {
+	Test   entity.Test
+	Access entity.TestAccess
 }
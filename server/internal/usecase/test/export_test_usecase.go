@@ -0,0 +1,106 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+const (
+	// BundleFormat - идентификатор формата пакета теста для импорта и экспорта
+	BundleFormat = "psytest-bundle"
+	// BundleFormatVersion - версия формата пакета; импорт принимает пакеты этой и более ранних версий
+	BundleFormatVersion = bundleVersionCatalog
+)
+
+// Версии формата пакета. Каждое изменение схемы пакета получает новую версию,
+// а поле пакета допускается начиная с версии, в которой оно появилось
+const (
+	bundleVersionInitial    = 1 // данные теста, вопросы и правила подсчета
+	bundleVersionTimeLimits = 2 // timeLimits
+	bundleVersionRetake     = 3 // retake
+	bundleVersionShuffle    = 4 // shuffle
+	bundleVersionBranches   = 5 // questions[].branches
+	bundleVersionCatalog    = 6 // category, tags
+)
+
+// ExportTestUseCase - Use Case для выгрузки теста в переносимый пакет
+type ExportTestUseCase struct {
+	testRepo repository.TestRepository
+}
+
+// NewExportTestUseCase создает новый экземпляр ExportTestUseCase
+func NewExportTestUseCase(testRepo repository.TestRepository) *ExportTestUseCase {
+	return &ExportTestUseCase{
+		testRepo: testRepo,
+	}
+}
+
+// ExportTestInput - входные данные для ExportTestUseCase.
+// Если Version не задана, выгружается текущая версия теста
+type ExportTestInput struct {
+	TestID  string
+	Version int
+}
+
+// ExportTestOutput - выходные данные ExportTestUseCase
+type ExportTestOutput struct {
+	Test      entity.Test
+	Questions entity.QuestionsDocument
+}
+
+// Execute возвращает данные теста, вопросы и правила подсчета для выгрузки.
+// Выгрузить тест могут владелец, соавторы и проверяющие
+func (uc *ExportTestUseCase) Execute(ctx context.Context, input ExportTestInput) (ExportTestOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return ExportTestOutput{}, err
+	}
+
+	testIDStr := strings.TrimSpace(input.TestID)
+	if testIDStr == "" || input.Version < 0 {
+		return ExportTestOutput{}, domainErrors.ErrInvalidInput
+	}
+	testID := entity.TestID(testIDStr)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	test, err := uc.testRepo.FindByID(ctx, testID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return ExportTestOutput{}, err
+		}
+		return ExportTestOutput{}, domainErrors.ErrDatabase
+	}
+	if test.IsDeleted() {
+		return ExportTestOutput{}, domainErrors.ErrNotFound
+	}
+	if !canManageTest(caller, test) {
+		return ExportTestOutput{}, domainErrors.ErrForbidden
+	}
+
+	var questionsDoc entity.QuestionsDocument
+	if input.Version > 0 {
+		questionsDoc, err = uc.testRepo.FindQuestionsVersion(ctx, testID, input.Version)
+	} else {
+		questionsDoc, err = uc.testRepo.FindQuestionsByTestID(ctx, testID)
+	}
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) {
+			return ExportTestOutput{}, domainErrors.ErrNotFound
+		}
+		return ExportTestOutput{}, domainErrors.ErrDatabase
+	}
+
+	return ExportTestOutput{
+		Test:      test,
+		Questions: questionsDoc,
+	}, nil
+}
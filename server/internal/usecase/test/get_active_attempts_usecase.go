@@ -0,0 +1,46 @@
+package test
+
+import (
+	"context"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// GetActiveAttemptsUseCase - Use Case для получения незавершенных попыток пользователя
+type GetActiveAttemptsUseCase struct {
+	sessionRepo repository.AttemptSessionRepository
+}
+
+// NewGetActiveAttemptsUseCase создает новый экземпляр GetActiveAttemptsUseCase
+func NewGetActiveAttemptsUseCase(sessionRepo repository.AttemptSessionRepository) *GetActiveAttemptsUseCase {
+	return &GetActiveAttemptsUseCase{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// GetActiveAttemptsOutput - выходные данные GetActiveAttemptsUseCase
+type GetActiveAttemptsOutput struct {
+	Sessions []entity.AttemptSession
+}
+
+// Execute возвращает неотправленные и неистекшие попытки, последние измененные - первыми
+func (uc *GetActiveAttemptsUseCase) Execute(ctx context.Context) (GetActiveAttemptsOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return GetActiveAttemptsOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	sessions, err := uc.sessionRepo.FindActiveByUser(ctx, caller.User.ID, time.Now())
+	if err != nil {
+		return GetActiveAttemptsOutput{}, domainErrors.ErrDatabase
+	}
+
+	return GetActiveAttemptsOutput{Sessions: sessions}, nil
+}
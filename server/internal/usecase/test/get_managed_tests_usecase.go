@@ -0,0 +1,76 @@
+package test
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// GetManagedTestsUseCase - Use Case для получения тестов, которыми управляет пользователь:
+// своих тестов и тестов, где он соавтор, во всех статусах или очереди тестов на проверке
+type GetManagedTestsUseCase struct {
+	testRepo repository.TestRepository
+}
+
+// NewGetManagedTestsUseCase создает новый экземпляр GetManagedTestsUseCase
+func NewGetManagedTestsUseCase(testRepo repository.TestRepository) *GetManagedTestsUseCase {
+	return &GetManagedTestsUseCase{
+		testRepo: testRepo,
+	}
+}
+
+// GetManagedTestsInput - входные данные для GetManagedTestsUseCase.
+// ReviewQueue - вернуть тесты всех авторов, ожидающие проверки (право tests:review)
+type GetManagedTestsInput struct {
+	ReviewQueue bool
+}
+
+// GetManagedTestsOutput - выходные данные GetManagedTestsUseCase
+type GetManagedTestsOutput struct {
+	Tests []ManagedTestDTO
+}
+
+// Execute возвращает тесты пользователя и тесты, где он соавтор (кроме удаленных),
+// или очередь проверки
+func (uc *GetManagedTestsUseCase) Execute(ctx context.Context, input GetManagedTestsInput) (GetManagedTestsOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return GetManagedTestsOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var tests []entity.Test
+	if input.ReviewQueue {
+		if !caller.User.HasPermission(entity.PermissionTestsReview) {
+			return GetManagedTestsOutput{}, domainErrors.ErrForbidden
+		}
+		tests, err = uc.testRepo.FindByStatus(ctx, entity.TestStatusReview)
+	} else {
+		tests, err = uc.testRepo.FindByUserID(ctx, caller.User.ID)
+		if err == nil {
+			var shared []entity.Test
+			shared, err = uc.testRepo.FindByCollaborator(ctx, caller.User.ID)
+			tests = append(tests, shared...)
+		}
+	}
+	if err != nil {
+		return GetManagedTestsOutput{}, domainErrors.ErrDatabase
+	}
+
+	sort.SliceStable(tests, func(i, j int) bool {
+		return tests[i].TestName < tests[j].TestName
+	})
+
+	managed := make([]ManagedTestDTO, 0, len(tests))
+	for _, test := range tests {
+		managed = append(managed, ManagedTestDTO{Test: test, Access: test.AccessFor(caller.User)})
+	}
+	return GetManagedTestsOutput{Tests: managed}, nil
+}
@@ -8,36 +8,53 @@ import (
 
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
 // GetQuestionsUseCase - Use Case для получения вопросов теста
 type GetQuestionsUseCase struct {
-	testRepo repository.TestRepository
+	testRepo    repository.TestRepository
+	sessionRepo repository.AttemptSessionRepository
 }
 
 // NewGetQuestionsUseCase создает новый экземпляр GetQuestionsUseCase
-func NewGetQuestionsUseCase(testRepo repository.TestRepository) *GetQuestionsUseCase {
+func NewGetQuestionsUseCase(
+	testRepo repository.TestRepository,
+	sessionRepo repository.AttemptSessionRepository,
+) *GetQuestionsUseCase {
 	return &GetQuestionsUseCase{
-		testRepo: testRepo,
+		testRepo:    testRepo,
+		sessionRepo: sessionRepo,
 	}
 }
 
-// GetQuestionsInput - входные данные для GetQuestionsUseCase
+// GetQuestionsInput - входные данные для GetQuestionsUseCase.
+// Если Version не задана, возвращается текущая версия теста. Если задан AttemptID,
+// возвращаются вопросы незавершенной попытки в ее версии и порядке показа
 type GetQuestionsInput struct {
-	TestID string
+	TestID    string
+	Version   int
+	AttemptID string
 }
 
 // GetQuestionsOutput - выходные данные GetQuestionsUseCase
 type GetQuestionsOutput struct {
 	TestID       entity.TestID
 	TestName     string
+	Version      int
 	Questions    []entity.Question
-	ResultsLogic string
+	ResultsLogic entity.ResultsLogic
+	TimeLimits   entity.TimeLimits
+	Shuffle      entity.ShuffleSettings
 }
 
 // Execute выполняет Use Case получения вопросов теста
 func (uc *GetQuestionsUseCase) Execute(ctx context.Context, input GetQuestionsInput) (GetQuestionsOutput, error) {
+	if strings.TrimSpace(input.AttemptID) != "" {
+		return uc.attemptQuestions(ctx, input)
+	}
+
 	// Валидация входных данных
 	testIDStr := strings.TrimSpace(input.TestID)
 	if testIDStr == "" {
@@ -52,8 +69,32 @@ func (uc *GetQuestionsUseCase) Execute(ctx context.Context, input GetQuestionsIn
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	if input.Version < 0 {
+		return GetQuestionsOutput{}, domainErrors.ErrInvalidInput
+	}
+
+	// Неопубликованный тест виден только создателю и проверяющим
+	test, err := uc.testRepo.FindByID(ctx, testID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return GetQuestionsOutput{}, err
+		}
+		return GetQuestionsOutput{}, domainErrors.ErrDatabase
+	}
+	if !test.IsAvailable(time.Now()) {
+		caller, ok := identity.CallerFromContext(ctx)
+		if !ok || !canManageTest(caller, test) {
+			return GetQuestionsOutput{}, domainErrors.ErrNotFound
+		}
+	}
+
 	// Получаем вопросы теста
-	questionsDoc, err := uc.testRepo.FindQuestionsByTestID(ctx, testID)
+	var questionsDoc entity.QuestionsDocument
+	if input.Version > 0 {
+		questionsDoc, err = uc.testRepo.FindQuestionsVersion(ctx, testID, input.Version)
+	} else {
+		questionsDoc, err = uc.testRepo.FindQuestionsByTestID(ctx, testID)
+	}
 	if err != nil {
 		if errors.Is(err, domainErrors.ErrNotFound) {
 			return GetQuestionsOutput{}, domainErrors.ErrNotFound
@@ -61,17 +102,61 @@ func (uc *GetQuestionsUseCase) Execute(ctx context.Context, input GetQuestionsIn
 		return GetQuestionsOutput{}, domainErrors.ErrDatabase
 	}
 
-	// Получаем информацию о тесте (имя)
-	test, err := uc.testRepo.FindByID(ctx, testID)
-	testName := ""
-	if err == nil {
-		testName = strings.TrimSpace(test.TestName)
-	}
-
 	return GetQuestionsOutput{
 		TestID:       testID,
-		TestName:     testName,
+		TestName:     strings.TrimSpace(test.TestName),
+		Version:      questionsDoc.Version,
 		Questions:    questionsDoc.Questions,
 		ResultsLogic: questionsDoc.ResultsLogic,
+		TimeLimits:   questionsDoc.TimeLimits,
+		Shuffle:      questionsDoc.Shuffle,
+	}, nil
+}
+
+// attemptQuestions возвращает вопросы незавершенной попытки вызывающего пользователя:
+// в версии, с которой начата попытка, и в выбранном для нее порядке показа
+func (uc *GetQuestionsUseCase) attemptQuestions(ctx context.Context, input GetQuestionsInput) (GetQuestionsOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return GetQuestionsOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	session, err := findActiveSession(ctx, uc.sessionRepo, caller, input.AttemptID, time.Now())
+	if err != nil {
+		return GetQuestionsOutput{}, err
+	}
+	if testID := strings.TrimSpace(input.TestID); testID != "" && entity.TestID(testID) != session.TestID {
+		return GetQuestionsOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Попытка начата по другому тесту",
+			[]domainErrors.FieldError{{Field: "testId", Message: "Не совпадает с тестом попытки"}})
+	}
+
+	test, err := uc.testRepo.FindByID(ctx, session.TestID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return GetQuestionsOutput{}, err
+		}
+		return GetQuestionsOutput{}, domainErrors.ErrDatabase
+	}
+
+	questionsDoc, err := uc.testRepo.FindQuestionsVersion(ctx, session.TestID, session.TestVersion)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return GetQuestionsOutput{}, err
+		}
+		return GetQuestionsOutput{}, domainErrors.ErrDatabase
+	}
+
+	return GetQuestionsOutput{
+		TestID:       session.TestID,
+		TestName:     strings.TrimSpace(test.TestName),
+		Version:      questionsDoc.Version,
+		Questions:    session.Layout.Arrange(questionsDoc.Questions),
+		ResultsLogic: questionsDoc.ResultsLogic,
+		TimeLimits:   questionsDoc.TimeLimits,
+		Shuffle:      questionsDoc.Shuffle,
 	}, nil
 }
@@ -2,11 +2,11 @@ package test
 
 import (
 	"context"
-	"strings"
 	"time"
 
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
 	"server/internal/domain/repository"
 )
 
@@ -27,54 +27,54 @@ func NewGetTestsUseCase(
 	}
 }
 
-// GetTestsInput - входные данные для GetTestsUseCase
-type GetTestsInput struct {
-	UserID string // может быть пустым для неавторизованных пользователей
-}
-
 // GetTestsOutput - выходные данные GetTestsUseCase
 type GetTestsOutput struct {
 	Tests []TestWithCompletionDTO
 }
 
 // Execute выполняет Use Case получения списка тестов
-func (uc *GetTestsUseCase) Execute(ctx context.Context, input GetTestsInput) (GetTestsOutput, error) {
+func (uc *GetTestsUseCase) Execute(ctx context.Context) (GetTestsOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Получаем опубликованные тесты
-	tests, err := uc.testRepo.FindByStatus(ctx, entity.TestStatusPublished)
+	// Получаем опубликованные тесты; тесты вне периода показа не отображаются
+	published, err := uc.testRepo.FindByStatus(ctx, entity.TestStatusPublished)
 	if err != nil {
 		return GetTestsOutput{}, domainErrors.ErrDatabase
 	}
 
-	// Создаем map для быстрой проверки завершенных тестов
-	completedMap := make(map[entity.TestID]bool)
-
-	// Если передан userID, получаем список завершенных тестов пользователя
-	if strings.TrimSpace(input.UserID) != "" {
-		userID := entity.UserID(strings.TrimSpace(input.UserID))
-		if userID.IsEmpty() {
-			return GetTestsOutput{}, domainErrors.ErrInvalidID
+	now := time.Now()
+	tests := make([]entity.Test, 0, len(published))
+	for _, test := range published {
+		if test.IsAvailable(now) {
+			tests = append(tests, test)
 		}
+	}
+
+	// Время последней попытки по каждому тесту: по нему определяются завершенность
+	// теста и возможность пройти его повторно
+	lastCompleted := make(map[entity.TestID]time.Time)
 
-		answers, err := uc.userAnswerRepo.FindByUserID(ctx, userID)
+	// Для авторизованного пользователя получаем список завершенных тестов
+	if caller, ok := identity.CallerFromContext(ctx); ok {
+		answers, err := uc.userAnswerRepo.FindByUserID(ctx, caller.User.ID)
 		if err != nil {
 			return GetTestsOutput{}, domainErrors.ErrDatabase
 		}
 
-		// Заполняем map завершенных тестов
-		for _, answer := range answers {
-			completedMap[answer.TestID] = true
-		}
+		lastCompleted = lastCompletions(answers)
 	}
 
 	// Формируем результат с флагами завершенности
 	result := make([]TestWithCompletionDTO, 0, len(tests))
 	for _, test := range tests {
+		lastAt, isCompleted := lastCompleted[test.ID]
+		canAttempt, nextAttemptAt := retakeAvailability(test, lastAt, now)
 		result = append(result, TestWithCompletionDTO{
-			Test:        test,
-			IsCompleted: completedMap[test.ID],
+			Test:          test,
+			IsCompleted:   isCompleted,
+			CanAttempt:    canAttempt,
+			NextAttemptAt: nextAttemptAt,
 		})
 	}
 
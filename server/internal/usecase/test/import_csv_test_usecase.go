@@ -0,0 +1,454 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// Столбцы CSV-таблицы вопросов. Обязателен только question
+const (
+	csvColumnQuestion  = "question"
+	csvColumnType      = "type"
+	csvColumnOptions   = "options"
+	csvColumnWeights   = "weights"
+	csvColumnRows      = "rows"
+	csvColumnMin       = "min"
+	csvColumnMax       = "max"
+	csvColumnMaxLength = "maxlength"
+)
+
+// csvColumns - известные столбцы CSV-таблицы
+var csvColumns = map[string]bool{
+	csvColumnQuestion:  true,
+	csvColumnType:      true,
+	csvColumnOptions:   true,
+	csvColumnWeights:   true,
+	csvColumnRows:      true,
+	csvColumnMin:       true,
+	csvColumnMax:       true,
+	csvColumnMaxLength: true,
+}
+
+// csvColumnByField - столбец, к которому относится ошибка поля вопроса. Ошибки
+// остальных полей относятся ко всей строке таблицы
+var csvColumnByField = map[string]string{
+	"questionBody":  csvColumnQuestion,
+	"selectType":    csvColumnType,
+	"answerOptions": csvColumnOptions,
+	"likert":        csvColumnMin,
+	"maxLength":     csvColumnMaxLength,
+	"rows":          csvColumnRows,
+	"weights":       csvColumnWeights,
+}
+
+const (
+	// csvListSeparator разделяет варианты, строки матрицы и группы весов в ячейке
+	csvListSeparator = "|"
+	// csvWeightSeparator разделяет пары шкала:балл внутри группы весов
+	csvWeightSeparator = ","
+	// maxCSVQuestions ограничивает число вопросов в одной таблице
+	maxCSVQuestions = 500
+	// defaultCSVLikertMin и defaultCSVLikertMax - шкала Лайкерта, если min и max не заданы
+	defaultCSVLikertMin = 1
+	defaultCSVLikertMax = 5
+)
+
+// ImportCSVTestUseCase - Use Case для создания теста-черновика из CSV-таблицы вопросов
+type ImportCSVTestUseCase struct {
+	testRepo repository.TestRepository
+}
+
+// NewImportCSVTestUseCase создает новый экземпляр ImportCSVTestUseCase
+func NewImportCSVTestUseCase(testRepo repository.TestRepository) *ImportCSVTestUseCase {
+	return &ImportCSVTestUseCase{
+		testRepo: testRepo,
+	}
+}
+
+// ImportCSVTestInput - входные данные для ImportCSVTestUseCase. CSV - таблица
+// с заголовком; каждая следующая строка описывает один вопрос. Остальные поля
+// задаются так же, как при создании теста
+type ImportCSVTestInput struct {
+	TestName    string
+	AuthorsName []string
+	Description string
+	Category    string
+	Tags        []string
+	IsTyping    bool
+	TimeLimits  TimeLimitsInput
+	Retake      RetakePolicyInput
+	Shuffle     ShuffleInput
+	CSV         io.Reader
+	DryRun      bool
+}
+
+// csvQuestion - вопрос, прочитанный из строки таблицы
+type csvQuestion struct {
+	Line  int
+	Input QuestionInput
+}
+
+// Execute разбирает таблицу и проверяет вопросы и настройки теста по тем же правилам,
+// что и создание теста. Ошибки возвращаются по строкам таблицы с путями вида rows[5].options, где
+// 5 - номер строки файла (заголовок - строка 1). Шкалы объявляются по весам
+func (uc *ImportCSVTestUseCase) Execute(ctx context.Context, input ImportCSVTestInput) (ImportTestOutput, error) {
+	// Автором импортированного теста становится вызывающий пользователь
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return ImportTestOutput{}, err
+	}
+
+	draft, problems := importedDraft(caller, input.TestName, input.Description, input.AuthorsName)
+	draft.IsTyping = input.IsTyping
+	output := ImportTestOutput{DryRun: input.DryRun}
+
+	rows, parseProblems := parseQuestionsCSV(input.CSV)
+	problems = append(problems, parseProblems...)
+	if len(rows) == 0 && len(parseProblems) == 0 {
+		problems = append(problems, domainErrors.FieldError{Field: "file", Message: "Таблица не содержит вопросов"})
+	}
+
+	inputs := make([]QuestionInput, 0, len(rows))
+	for _, row := range rows {
+		inputs = append(inputs, row.Input)
+	}
+
+	var questions []entity.Question
+	var resultsLogic entity.ResultsLogic
+	if len(rows) > 0 && len(parseProblems) == 0 {
+		normalized, questionProblems := normalizeQuestionInputs(inputs)
+		for _, problem := range questionProblems {
+			problems = append(problems, csvQuestionFieldError(rows[problem.Index].Line, problem.Field, problem.Message))
+		}
+
+		if len(questionProblems) == 0 {
+			questions = normalized
+			output.QuestionCount = len(questions)
+
+			logic, err := normalizeResultsLogic(ResultsLogicInput{Scales: csvScales(inputs)}, questions)
+			if err == nil {
+				err = validateTyping(input.IsTyping, logic)
+			}
+			if err != nil {
+				problems = append(problems, domainErrors.FieldError{Field: "weights", Message: problemMessage(err)})
+			} else {
+				resultsLogic = logic
+				output.ScaleCount = len(logic.Scales)
+			}
+		}
+	}
+
+	settings, settingProblems := normalizeTestSettings(testSettingsInput{
+		TimeLimits: input.TimeLimits,
+		Retake:     input.Retake,
+		Shuffle:    input.Shuffle,
+		Category:   input.Category,
+		Tags:       input.Tags,
+	}, questions)
+	problems = append(problems, settingProblems...)
+	draft.Retake = settings.Retake
+	draft.Category, draft.Tags = settings.Category, settings.Tags
+
+	return completeImport(ctx, uc.testRepo, output, problems, draft, entity.QuestionsDocument{
+		Questions:    questions,
+		ResultsLogic: resultsLogic,
+		TimeLimits:   settings.TimeLimits,
+		Shuffle:      settings.Shuffle,
+	})
+}
+
+// parseQuestionsCSV читает таблицу вопросов. Разделитель (запятая, точка с запятой
+// или табуляция) определяется по строке заголовка
+func parseQuestionsCSV(r io.Reader) ([]csvQuestion, []domainErrors.FieldError) {
+	if r == nil {
+		return nil, []domainErrors.FieldError{{Field: "file", Message: "Файл не передан"}}
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, []domainErrors.FieldError{{Field: "file", Message: "Не удалось прочитать файл"}}
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectCSVDelimiter(data)
+	reader.FieldsPerRecord = -1
+	// Для табуляции пробелы в начале поля не отбрасываются: иначе пропали бы пустые ячейки
+	reader.TrimLeadingSpace = reader.Comma != '\t'
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, []domainErrors.FieldError{{Field: "file", Message: "Файл пуст"}}
+	}
+	if err != nil {
+		return nil, []domainErrors.FieldError{csvParseError(err)}
+	}
+
+	var problems []domainErrors.FieldError
+	columns := make(map[string]int, len(header))
+	for index, name := range header {
+		column := strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case !csvColumns[column]:
+			problems = append(problems, csvFieldError(1, name, "Неизвестный столбец"))
+		case columns[column] > 0:
+			problems = append(problems, csvFieldError(1, name, "Столбец указан дважды"))
+		default:
+			columns[column] = index + 1
+		}
+	}
+	if columns[csvColumnQuestion] == 0 {
+		problems = append(problems, csvFieldError(1, csvColumnQuestion, "Нет обязательного столбца"))
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+
+	var questions []csvQuestion
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, append(problems, csvParseError(err))
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(column string) string {
+			index := columns[column] - 1
+			if index < 0 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		if len(questions) == maxCSVQuestions {
+			problems = append(problems, csvFieldError(line, csvColumnQuestion,
+				fmt.Sprintf("В таблице не может быть больше %d вопросов", maxCSVQuestions)))
+			break
+		}
+
+		question, rowProblems := parseCSVQuestion(line, cell)
+		problems = append(problems, rowProblems...)
+		questions = append(questions, csvQuestion{Line: line, Input: question})
+	}
+
+	return questions, problems
+}
+
+// parseCSVQuestion переводит строку таблицы во входной формат вопроса
+func parseCSVQuestion(line int, cell func(column string) string) (QuestionInput, []domainErrors.FieldError) {
+	question := QuestionInput{
+		Body:       cell(csvColumnQuestion),
+		SelectType: cell(csvColumnType),
+	}
+	var problems []domainErrors.FieldError
+
+	for _, body := range splitCSVList(cell(csvColumnOptions)) {
+		question.Options = append(question.Options, AnswerOptionInput{Body: body})
+	}
+	for _, body := range splitCSVList(cell(csvColumnRows)) {
+		question.Rows = append(question.Rows, MatrixRowInput{Body: body})
+	}
+
+	// Тип проверяется при нормализации; здесь он нужен только для разбора весов и шкалы
+	questionType, known := entity.LookupQuestionType(question.SelectType)
+
+	if raw := cell(csvColumnMaxLength); raw != "" {
+		maxLength, err := strconv.Atoi(raw)
+		if err != nil {
+			problems = append(problems, csvFieldError(line, csvColumnMaxLength, "Ожидается целое число"))
+		}
+		question.MaxLength = maxLength
+	}
+
+	if known && questionType == entity.QuestionTypeLikert {
+		likert := &LikertInput{Min: defaultCSVLikertMin, Max: defaultCSVLikertMax}
+		for _, bound := range []struct {
+			column string
+			target *int
+		}{{csvColumnMin, &likert.Min}, {csvColumnMax, &likert.Max}} {
+			raw := cell(bound.column)
+			if raw == "" {
+				continue
+			}
+			value, err := strconv.Atoi(raw)
+			if err != nil {
+				problems = append(problems, csvFieldError(line, bound.column, "Ожидается целое число"))
+				continue
+			}
+			*bound.target = value
+		}
+		question.Likert = likert
+	}
+
+	groups := splitCSVList(cell(csvColumnWeights))
+	if len(groups) == 0 || !known {
+		return question, problems
+	}
+
+	switch {
+	case questionType == entity.QuestionTypeLikert:
+		if len(groups) > 1 {
+			problems = append(problems, csvFieldError(line, csvColumnWeights, "У вопроса likert одна группа весов"))
+			break
+		}
+		weights, err := parseCSVWeights(groups[0])
+		if err != nil {
+			problems = append(problems, csvFieldError(line, csvColumnWeights, err.Error()))
+		}
+		question.Weights = weights
+
+	case questionType.HasOptions():
+		if len(groups) > len(question.Options) {
+			problems = append(problems, csvFieldError(line, csvColumnWeights, "Групп весов больше, чем вариантов"))
+			break
+		}
+		for index, group := range groups {
+			weights, err := parseCSVWeights(group)
+			if err != nil {
+				problems = append(problems, csvFieldError(line, csvColumnWeights,
+					fmt.Sprintf("Вариант %d: %s", index+1, err.Error())))
+				continue
+			}
+			question.Options[index].Weights = weights
+		}
+
+	default:
+		problems = append(problems, csvFieldError(line, csvColumnWeights, "У вопроса этого типа не может быть весов"))
+	}
+
+	return question, problems
+}
+
+// parseCSVWeights разбирает группу весов вида "шкала:балл, шкала:балл"; "-" - без весов
+func parseCSVWeights(group string) (map[string]float64, error) {
+	if group == "-" {
+		return nil, nil
+	}
+
+	weights := make(map[string]float64)
+	for _, pair := range strings.Split(group, csvWeightSeparator) {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		scaleID, rawWeight, ok := strings.Cut(pair, ":")
+		scaleID = strings.TrimSpace(scaleID)
+		if !ok || scaleID == "" {
+			return nil, fmt.Errorf("ожидается шкала:балл, получено %q", pair)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(rawWeight), 64)
+		if err != nil || !isFinite(weight) {
+			return nil, fmt.Errorf("некорректный балл %q", strings.TrimSpace(rawWeight))
+		}
+		weights[scaleID] += weight
+	}
+	return weights, nil
+}
+
+// csvScales объявляет шкалы, на которые ссылаются веса, в порядке первого упоминания
+func csvScales(questions []QuestionInput) []ScaleInput {
+	var scales []ScaleInput
+	seen := make(map[string]bool)
+	declare := func(weights map[string]float64) {
+		ids := make([]string, 0, len(weights))
+		for id := range weights {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				scales = append(scales, ScaleInput{ID: id, Name: id})
+			}
+		}
+	}
+
+	for _, question := range questions {
+		declare(question.Weights)
+		for _, option := range question.Options {
+			declare(option.Weights)
+		}
+	}
+	return scales
+}
+
+// splitCSVList разбивает ячейку со списком значений, отбрасывая пустые
+func splitCSVList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, csvListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// detectCSVDelimiter выбирает самый частый разделитель в строке заголовка
+func detectCSVDelimiter(data []byte) rune {
+	header := data
+	if end := bytes.IndexByte(data, '\n'); end >= 0 {
+		header = data[:end]
+	}
+
+	delimiter, best := ',', bytes.Count(header, []byte{','})
+	for _, candidate := range []rune{';', '\t'} {
+		if count := bytes.Count(header, []byte(string(candidate))); count > best {
+			delimiter, best = candidate, count
+		}
+	}
+	return delimiter
+}
+
+// csvFieldError создает ошибку ячейки таблицы
+func csvFieldError(line int, column, message string) domainErrors.FieldError {
+	return domainErrors.FieldError{
+		Field:   fmt.Sprintf("rows[%d].%s", line, column),
+		Message: message,
+	}
+}
+
+// csvQuestionFieldError переводит ошибку поля вопроса в ошибку ячейки таблицы.
+// Если поле не соответствует столбцу, ошибка относится ко всей строке
+func csvQuestionFieldError(line int, field, message string) domainErrors.FieldError {
+	name, _, _ := strings.Cut(field, ".")
+	name, _, _ = strings.Cut(name, "[")
+	if column, ok := csvColumnByField[name]; ok {
+		return csvFieldError(line, column, message)
+	}
+	return domainErrors.FieldError{
+		Field:   fmt.Sprintf("rows[%d]", line),
+		Message: message,
+	}
+}
+
+// csvParseError переводит ошибку разбора CSV в ошибку строки таблицы
+func csvParseError(err error) domainErrors.FieldError {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return domainErrors.FieldError{
+			Field:   fmt.Sprintf("rows[%d]", parseErr.Line),
+			Message: "Ошибка формата CSV: " + parseErr.Err.Error(),
+		}
+	}
+	return domainErrors.FieldError{Field: "file", Message: "Ошибка формата CSV"}
+}
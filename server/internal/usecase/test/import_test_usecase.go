@@ -0,0 +1,233 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// ImportTestUseCase - Use Case для создания теста из переносимого пакета
+type ImportTestUseCase struct {
+	testRepo repository.TestRepository
+}
+
+// NewImportTestUseCase создает новый экземпляр ImportTestUseCase
+func NewImportTestUseCase(testRepo repository.TestRepository) *ImportTestUseCase {
+	return &ImportTestUseCase{
+		testRepo: testRepo,
+	}
+}
+
+// TestBundleInput - содержимое пакета теста. Вопросы и правила подсчета
+// задаются в том же формате, что и при создании теста
+type TestBundleInput struct {
+	Format        string
+	FormatVersion int
+	TestName      string
+	AuthorsName   []string
+	Description   string
+	Category      string
+	Tags          []string
+	IsTyping      bool
+	Questions     []QuestionInput
+	ResultsLogic  ResultsLogicInput
+	TimeLimits    TimeLimitsInput
+	Retake        RetakePolicyInput
+	Shuffle       ShuffleInput
+}
+
+// ImportTestInput - входные данные для ImportTestUseCase.
+// При DryRun пакет только проверяется, тест не создается
+type ImportTestInput struct {
+	Bundle TestBundleInput
+	DryRun bool
+}
+
+// ImportTestOutput - отчет об импорте. Test заполнен, только если тест создан
+type ImportTestOutput struct {
+	Test          entity.Test
+	DryRun        bool
+	Valid         bool
+	QuestionCount int
+	ScaleCount    int
+	RuleCount     int
+	Problems      []domainErrors.FieldError
+}
+
+// Execute проверяет пакет по тем же правилам, что и создание теста, и создает
+// тест-черновик. Проверка собирает все найденные ошибки; при пробном импорте
+// они возвращаются в отчете, иначе - ошибкой валидации
+func (uc *ImportTestUseCase) Execute(ctx context.Context, input ImportTestInput) (ImportTestOutput, error) {
+	// Автором импортированного теста становится вызывающий пользователь
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return ImportTestOutput{}, err
+	}
+
+	bundle := input.Bundle
+	problems := validateBundleFormat(bundle)
+
+	draft, metaProblems := importedDraft(caller, bundle.TestName, bundle.Description, bundle.AuthorsName)
+	draft.IsTyping = bundle.IsTyping
+	problems = append(problems, metaProblems...)
+
+	output := ImportTestOutput{DryRun: input.DryRun}
+
+	// Правила подсчета проверяются только для корректных вопросов: веса ссылаются на их варианты
+	var questions []entity.Question
+	var resultsLogic entity.ResultsLogic
+	if len(bundle.Questions) == 0 {
+		problems = append(problems, domainErrors.FieldError{Field: "questions", Message: "Тест должен содержать вопросы"})
+	} else if normalized, questionProblems := normalizeQuestionInputs(bundle.Questions); len(questionProblems) > 0 {
+		problems = append(problems, questionFieldErrors(questionProblems)...)
+	} else {
+		questions = normalized
+		output.QuestionCount = len(questions)
+
+		logic, err := normalizeResultsLogic(bundle.ResultsLogic, questions)
+		if err == nil {
+			err = validateTyping(bundle.IsTyping, logic)
+		}
+		if err != nil {
+			problems = append(problems, domainErrors.FieldError{Field: "resultLogic", Message: problemMessage(err)})
+		} else {
+			resultsLogic = logic
+			output.ScaleCount = len(logic.Scales)
+			output.RuleCount = len(logic.Rules)
+		}
+	}
+	settings, settingProblems := normalizeTestSettings(testSettingsInput{
+		TimeLimits: bundle.TimeLimits,
+		Retake:     bundle.Retake,
+		Shuffle:    bundle.Shuffle,
+		Category:   bundle.Category,
+		Tags:       bundle.Tags,
+	}, questions)
+	problems = append(problems, settingProblems...)
+	draft.Retake = settings.Retake
+	draft.Category, draft.Tags = settings.Category, settings.Tags
+
+	return completeImport(ctx, uc.testRepo, output, problems, draft, entity.QuestionsDocument{
+		Questions:    questions,
+		ResultsLogic: resultsLogic,
+		TimeLimits:   settings.TimeLimits,
+		Shuffle:      settings.Shuffle,
+	})
+}
+
+// importedDraft проверяет данные импортируемого теста и возвращает заготовку черновика
+func importedDraft(caller identity.Caller, testName, description string, authors []string) (entity.Test, []domainErrors.FieldError) {
+	draft := entity.Test{
+		TestName:    strings.TrimSpace(testName),
+		AuthorsName: normalizeAuthors(authors),
+		Description: strings.TrimSpace(description),
+		UserID:      caller.User.ID,
+	}
+
+	var problems []domainErrors.FieldError
+	if draft.TestName == "" {
+		problems = append(problems, domainErrors.FieldError{Field: "testName", Message: "Обязательное поле"})
+	}
+	if draft.Description == "" {
+		problems = append(problems, domainErrors.FieldError{Field: "description", Message: "Обязательное поле"})
+	}
+	if len(draft.AuthorsName) == 0 {
+		problems = append(problems, domainErrors.FieldError{Field: "authorsName", Message: "Укажите хотя бы одного автора"})
+	}
+	return draft, problems
+}
+
+// completeImport завершает импорт: при пробном импорте возвращает отчет, при ошибках -
+// ошибку валидации со всеми найденными проблемами, иначе сохраняет тест-черновик
+func completeImport(
+	ctx context.Context,
+	testRepo repository.TestRepository,
+	output ImportTestOutput,
+	problems []domainErrors.FieldError,
+	draft entity.Test,
+	questionsDoc entity.QuestionsDocument,
+) (ImportTestOutput, error) {
+	output.Problems = problems
+	output.Valid = len(problems) == 0
+	if output.DryRun {
+		return output, nil
+	}
+	if !output.Valid {
+		return ImportTestOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Импортируемый тест содержит ошибки", problems)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	newTest, err := insertDraftTest(ctx, testRepo, draft, questionsDoc)
+	if err != nil {
+		return ImportTestOutput{}, err
+	}
+
+	output.Test = newTest
+	return output, nil
+}
+
+// validateBundleFormat проверяет идентификатор и версию формата пакета
+func validateBundleFormat(bundle TestBundleInput) []domainErrors.FieldError {
+	var problems []domainErrors.FieldError
+	if strings.TrimSpace(bundle.Format) != BundleFormat {
+		problems = append(problems, domainErrors.FieldError{
+			Field:   "format",
+			Message: fmt.Sprintf("Ожидается %q", BundleFormat),
+		})
+	}
+	switch {
+	case bundle.FormatVersion <= 0:
+		problems = append(problems, domainErrors.FieldError{Field: "formatVersion", Message: "Обязательное поле"})
+	case bundle.FormatVersion > BundleFormatVersion:
+		problems = append(problems, domainErrors.FieldError{
+			Field:   "formatVersion",
+			Message: fmt.Sprintf("Версия %d не поддерживается, максимальная - %d", bundle.FormatVersion, BundleFormatVersion),
+		})
+	default:
+		problems = append(problems, bundleFieldProblems(bundle)...)
+	}
+	return problems
+}
+
+// bundleFieldProblems проверяет, что пакет не использует поля, появившиеся в более
+// поздних версиях формата, чем указанная в пакете
+func bundleFieldProblems(bundle TestBundleInput) []domainErrors.FieldError {
+	var problems []domainErrors.FieldError
+	require := func(field string, used bool, since int) {
+		if used && bundle.FormatVersion < since {
+			problems = append(problems, domainErrors.FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("Поле поддерживается с версии формата %d, в пакете указана версия %d", since, bundle.FormatVersion),
+			})
+		}
+	}
+
+	require("timeLimits", bundle.TimeLimits != TimeLimitsInput{}, bundleVersionTimeLimits)
+	require("retake", bundle.Retake != RetakePolicyInput{}, bundleVersionRetake)
+	require("shuffle", bundle.Shuffle != ShuffleInput{}, bundleVersionShuffle)
+	for index, question := range bundle.Questions {
+		require(fmt.Sprintf("questions[%d].branches", index), len(question.Branches) > 0, bundleVersionBranches)
+	}
+	require("category", strings.TrimSpace(bundle.Category) != "", bundleVersionCatalog)
+	require("tags", len(bundle.Tags) > 0, bundleVersionCatalog)
+	return problems
+}
+
+// problemMessage возвращает пояснение ошибки валидации для отчета
+func problemMessage(err error) string {
+	var validationErr *domainErrors.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Message
+	}
+	return err.Error()
+}
@@ -1,9 +1,13 @@
 package test
 
 import (
+	"fmt"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
 )
 
 // normalizeAuthors очищает список авторов от пустых значений и пробелов
@@ -18,64 +22,426 @@ func normalizeAuthors(authors []string) []string {
 	return normalized
 }
 
-// normalizeQuestionInputs нормализует вопросы и проверяет обязательные поля
-func normalizeQuestionInputs(raw []QuestionInput) ([]entity.Question, string) {
+// questionProblem - ошибка в отдельном вопросе. Index - номер вопроса во входных
+// данных, Field - поле вопроса в формате запроса на создание теста
+type questionProblem struct {
+	Index   int
+	Field   string
+	Message string
+}
+
+// normalizeQuestionInputs нормализует вопросы и проверяет обязательные поля для каждого типа вопроса.
+// Вопросам без ID назначаются номера после наибольшего заданного ID; повторяющиеся ID отклоняются.
+// Возвращает все найденные ошибки, по одной на вопрос
+func normalizeQuestionInputs(raw []QuestionInput) ([]entity.Question, []questionProblem) {
 	normalized := make([]entity.Question, 0, len(raw))
+	var problems []questionProblem
 
+	ids := make([]int, len(raw))
+	nextID := 1
 	for index, question := range raw {
-		qBody := strings.TrimSpace(question.Body)
-		if qBody == "" {
-			return nil, "Укажите формулировку для каждого вопроса"
+		ids[index] = question.ID
+		if ids[index] == 0 {
+			ids[index] = question.FallbackID
 		}
+		if ids[index] >= nextID {
+			nextID = ids[index] + 1
+		}
+	}
 
-		// Нормализация вариантов ответов: очищаем текст и выравниваем идентификаторы
-		normalizedOptions := make([]entity.AnswerOption, 0, len(question.Options))
-		nextOptionID := 1
+	seen := make(map[int]int, len(raw))
+	for index, question := range raw {
+		id := ids[index]
+		if id == 0 {
+			id = nextID
+			nextID++
+		}
+		if first, duplicate := seen[id]; duplicate {
+			problems = append(problems, questionProblem{
+				Index:   index,
+				Field:   "id",
+				Message: fmt.Sprintf("ID %d уже занят вопросом questions[%d]", id, first),
+			})
+			continue
+		}
+		seen[id] = index
 
-		for _, option := range question.Options {
-			body := strings.TrimSpace(option.Body)
-			if body == "" {
-				continue
-			}
+		normalizedQuestion, problem := normalizeQuestionInput(index, id, question)
+		if problem != nil {
+			problems = append(problems, *problem)
+			continue
+		}
+		normalized = append(normalized, normalizedQuestion)
+	}
 
-			optionID := option.ID
-			if optionID <= 0 {
-				optionID = nextOptionID
-				nextOptionID++
-			} else if optionID >= nextOptionID {
-				nextOptionID = optionID + 1
-			}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	if problems := validateQuestionFlow(normalized); len(problems) > 0 {
+		return nil, problems
+	}
+	return normalized, nil
+}
 
-			normalizedOptions = append(normalizedOptions, entity.AnswerOption{
-				ID:   optionID,
-				Body: body,
-			})
+// normalizeQuestionInput нормализует один вопрос; id - ID вопроса с учетом назначенного
+// по умолчанию
+func normalizeQuestionInput(index, id int, question QuestionInput) (entity.Question, *questionProblem) {
+	fail := func(field, message string) (entity.Question, *questionProblem) {
+		return entity.Question{}, &questionProblem{Index: index, Field: field, Message: message}
+	}
+
+	qBody := strings.TrimSpace(question.Body)
+	if qBody == "" {
+		return fail("questionBody", "Укажите формулировку для каждого вопроса")
+	}
+
+	questionType, ok := entity.LookupQuestionType(question.SelectType)
+	if !ok {
+		return fail("selectType", fmt.Sprintf("Неизвестный тип вопроса %q", question.SelectType))
+	}
+
+	normalizedQuestion := entity.Question{
+		ID:            id,
+		QuestionBody:  qBody,
+		AnswerOptions: []entity.AnswerOption{},
+		SelectType:    questionType,
+		Rows:          []entity.MatrixRow{},
+	}
+
+	if questionType.HasOptions() {
+		options, problem := normalizeOptions(question.Options)
+		if problem != nil {
+			return fail(problem.Field, problem.Message)
 		}
+		normalizedQuestion.AnswerOptions = options
+	}
 
-		if len(normalizedOptions) == 0 {
-			return nil, "У каждого вопроса должны быть варианты ответов"
+	switch questionType {
+	case entity.QuestionTypeOne, entity.QuestionTypeMultiple:
+		if len(normalizedQuestion.AnswerOptions) == 0 {
+			return fail("answerOptions", "У каждого вопроса должны быть варианты ответов")
 		}
 
-		id := question.ID
-		if id == 0 {
-			id = question.FallbackID
+	case entity.QuestionTypeLikert:
+		if question.Likert == nil || question.Likert.Min >= question.Likert.Max {
+			return fail("likert", fmt.Sprintf("У вопроса %d шкала должна иметь минимум меньше максимума", id))
 		}
-		if id == 0 {
-			id = index + 1
+		if question.Likert.Max-question.Likert.Min > maxLikertPoints {
+			return fail("likert", fmt.Sprintf("У вопроса %d шкала не может содержать больше %d делений", id, maxLikertPoints+1))
+		}
+		normalizedQuestion.Likert = &entity.LikertScale{
+			Min:      question.Likert.Min,
+			Max:      question.Likert.Max,
+			MinLabel: strings.TrimSpace(question.Likert.MinLabel),
+			MaxLabel: strings.TrimSpace(question.Likert.MaxLabel),
+		}
+		normalizedQuestion.Weights = normalizeWeights(question.Weights)
+
+	case entity.QuestionTypeText:
+		if question.MaxLength < 0 {
+			return fail("maxLength", fmt.Sprintf("У вопроса %d некорректная максимальная длина ответа", id))
+		}
+		normalizedQuestion.MaxLength = question.MaxLength
+
+	case entity.QuestionTypeRanking:
+		if len(normalizedQuestion.AnswerOptions) < 2 {
+			return fail("answerOptions", fmt.Sprintf("В вопросе %d для ранжирования нужно хотя бы два варианта", id))
+		}
+
+	case entity.QuestionTypeMatrix:
+		rows, problem := normalizeRows(question.Rows)
+		if problem != nil {
+			return fail(problem.Field, problem.Message)
+		}
+		normalizedQuestion.Rows = rows
+		if len(normalizedQuestion.Rows) == 0 || len(normalizedQuestion.AnswerOptions) == 0 {
+			return fail("rows", fmt.Sprintf("У матричного вопроса %d должны быть строки и столбцы", id))
+		}
+	}
+
+	if len(question.Branches) > 0 {
+		branches, problem := normalizeBranches(normalizedQuestion, question.Branches)
+		if problem != nil {
+			return fail(problem.Field, problem.Message)
+		}
+		normalizedQuestion.Branches = branches
+	}
+
+	return normalizedQuestion, nil
+}
+
+// questionFieldErrors переводит ошибки вопросов в ошибки полей с путями вида questions[2].selectType
+func questionFieldErrors(problems []questionProblem) []domainErrors.FieldError {
+	fields := make([]domainErrors.FieldError, 0, len(problems))
+	for _, problem := range problems {
+		fields = append(fields, domainErrors.FieldError{
+			Field:   fmt.Sprintf("questions[%d].%s", problem.Index, problem.Field),
+			Message: problem.Message,
+		})
+	}
+	return fields
+}
+
+// questionsError собирает ошибки вопросов в ошибку валидации
+func questionsError(problems []questionProblem) error {
+	return domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+		problems[0].Message, questionFieldErrors(problems))
+}
+
+// maxLikertPoints ограничивает разброс значений шкалы Лайкерта
+const maxLikertPoints = 100
+
+// normalizeOptions очищает текст вариантов ответов и выравнивает идентификаторы: вариантам
+// без ID назначаются номера после наибольшего заданного ID, повторяющиеся ID отклоняются
+func normalizeOptions(raw []AnswerOptionInput) ([]entity.AnswerOption, *questionProblem) {
+	requested := make([]int, len(raw))
+	for index, option := range raw {
+		requested[index] = option.ID
+	}
+	ids, problem := assignIDs("answerOptions", "вариантом", requested)
+	if problem != nil {
+		return nil, problem
+	}
+
+	options := make([]entity.AnswerOption, 0, len(raw))
+	for index, option := range raw {
+		body := strings.TrimSpace(option.Body)
+		if body == "" {
+			continue
+		}
+		options = append(options, entity.AnswerOption{
+			ID:      ids[index],
+			Body:    body,
+			Weights: normalizeWeights(option.Weights),
+		})
+	}
+
+	return options, nil
+}
+
+// normalizeRows очищает текст строк матричного вопроса и выравнивает идентификаторы
+// так же, как normalizeOptions
+func normalizeRows(raw []MatrixRowInput) ([]entity.MatrixRow, *questionProblem) {
+	requested := make([]int, len(raw))
+	for index, row := range raw {
+		requested[index] = row.ID
+	}
+	ids, problem := assignIDs("rows", "строкой", requested)
+	if problem != nil {
+		return nil, problem
+	}
+
+	rows := make([]entity.MatrixRow, 0, len(raw))
+	for index, row := range raw {
+		body := strings.TrimSpace(row.Body)
+		if body == "" {
+			continue
+		}
+		rows = append(rows, entity.MatrixRow{ID: ids[index], Body: body})
+	}
+
+	return rows, nil
+}
+
+// assignIDs назначает ID элементам списка field: элементы без ID (0 и меньше) получают
+// номера после наибольшего заданного ID. Повторяющийся ID возвращается как ошибка
+// элемента; owner - кем занят ID, для сообщения
+func assignIDs(field, owner string, requested []int) ([]int, *questionProblem) {
+	nextID := 1
+	for _, id := range requested {
+		if id >= nextID {
+			nextID = id + 1
+		}
+	}
+
+	ids := make([]int, len(requested))
+	seen := make(map[int]int, len(requested))
+	for index, id := range requested {
+		if id <= 0 {
+			id = nextID
+			nextID++
+		}
+		if first, duplicate := seen[id]; duplicate {
+			return nil, &questionProblem{
+				Field:   fmt.Sprintf("%s[%d].id", field, index),
+				Message: fmt.Sprintf("ID %d уже занят %s %s[%d]", id, owner, field, first),
+			}
+		}
+		seen[id] = index
+		ids[index] = id
+	}
+	return ids, nil
+}
+
+// normalizeWeights очищает идентификаторы шкал и отбрасывает нулевые веса
+func normalizeWeights(raw map[string]float64) map[entity.ScaleID]float64 {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	weights := make(map[entity.ScaleID]float64, len(raw))
+	for scaleID, weight := range raw {
+		scaleID = strings.TrimSpace(scaleID)
+		if scaleID == "" || weight == 0 {
+			continue
 		}
+		weights[entity.ScaleID(scaleID)] += weight
+	}
 
-		selectType := strings.TrimSpace(question.SelectType)
-		if selectType == "" {
-			selectType = "one"
+	if len(weights) == 0 {
+		return nil
+	}
+	return weights
+}
+
+// maxTimeLimit ограничивает время прохождения теста и ответа на вопрос
+const maxTimeLimit = 24 * time.Hour
+
+// normalizeTimeLimits проверяет ограничения времени: значения не отрицательные,
+// не больше суток, и время на вопрос не превышает время на весь тест
+func normalizeTimeLimits(input TimeLimitsInput) (entity.TimeLimits, []domainErrors.FieldError) {
+	limits := entity.TimeLimits{
+		Total:       time.Duration(input.TotalSeconds) * time.Second,
+		PerQuestion: time.Duration(input.QuestionSeconds) * time.Second,
+	}
+
+	var problems []domainErrors.FieldError
+	check := func(field string, value time.Duration) {
+		if value < 0 || value > maxTimeLimit {
+			problems = append(problems, domainErrors.FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("Допустимо от 0 до %d секунд", int(maxTimeLimit/time.Second)),
+			})
 		}
+	}
+	check("timeLimits.totalSeconds", limits.Total)
+	check("timeLimits.questionSeconds", limits.PerQuestion)
 
-		normalized = append(normalized, entity.Question{
-			ID:            id,
-			QuestionBody:  qBody,
-			AnswerOptions: normalizedOptions,
-			SelectType:    selectType,
+	if len(problems) == 0 && limits.Total > 0 && limits.PerQuestion > limits.Total {
+		problems = append(problems, domainErrors.FieldError{
+			Field:   "timeLimits.questionSeconds",
+			Message: "Время на вопрос не может превышать время на весь тест",
 		})
 	}
+	return limits, problems
+}
 
-	return normalized, ""
+// settingsError возвращает ошибку валидации настроек прохождения теста:
+// ограничений времени и правила повторного прохождения
+func settingsError(problems []domainErrors.FieldError) error {
+	return domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput, problems[0].Message, problems)
+}
+
+// testSettingsInput - настройки прохождения и каталога теста во входном формате
+type testSettingsInput struct {
+	TimeLimits TimeLimitsInput
+	Retake     RetakePolicyInput
+	Shuffle    ShuffleInput
+	Category   string
+	Tags       []string
+}
+
+// testSettings - проверенные настройки прохождения и каталога теста
+type testSettings struct {
+	TimeLimits entity.TimeLimits
+	Retake     entity.RetakePolicy
+	Shuffle    entity.ShuffleSettings
+	Category   string
+	Tags       []string
+}
+
+// normalizeTestSettings проверяет настройки теста по правилам, общим для создания,
+// изменения и импорта. Перемешивание проверяется по уже нормализованным вопросам
+func normalizeTestSettings(input testSettingsInput, questions []entity.Question) (testSettings, []domainErrors.FieldError) {
+	timeLimits, problems := normalizeTimeLimits(input.TimeLimits)
+	retake, retakeProblems := normalizeRetakePolicy(input.Retake)
+	problems = append(problems, retakeProblems...)
+	problems = append(problems, validateShuffle(input.Shuffle, questions)...)
+	category, tags, catalogProblems := normalizeCatalog(input.Category, input.Tags)
+	problems = append(problems, catalogProblems...)
+
+	return testSettings{
+		TimeLimits: timeLimits,
+		Retake:     retake,
+		Shuffle:    entity.ShuffleSettings(input.Shuffle),
+		Category:   category,
+		Tags:       tags,
+	}, problems
+}
+
+// maxRetakeCooldownDays - наибольший срок до повторного прохождения
+const maxRetakeCooldownDays = 3650
+
+// normalizeRetakePolicy проверяет правило повторного прохождения. Пустой режим -
+// без ограничений; срок в днях задается только для режима cooldown
+func normalizeRetakePolicy(input RetakePolicyInput) (entity.RetakePolicy, []domainErrors.FieldError) {
+	mode := entity.RetakeMode(strings.ToLower(strings.TrimSpace(input.Mode)))
+	if mode == "" {
+		mode = entity.RetakeUnlimited
+	}
+	if !mode.IsValid() {
+		return entity.RetakePolicy{}, []domainErrors.FieldError{{
+			Field:   "retake.mode",
+			Message: "Допустимые значения: unlimited, once, cooldown",
+		}}
+	}
+
+	if mode != entity.RetakeCooldown {
+		return entity.RetakePolicy{Mode: mode}, nil
+	}
+	if input.CooldownDays < 1 || input.CooldownDays > maxRetakeCooldownDays {
+		return entity.RetakePolicy{}, []domainErrors.FieldError{{
+			Field:   "retake.cooldownDays",
+			Message: fmt.Sprintf("Допустимо от 1 до %d дней", maxRetakeCooldownDays),
+		}}
+	}
+	return entity.RetakePolicy{Mode: mode, CooldownDays: input.CooldownDays}, nil
+}
+
+const (
+	// maxCategoryLength - наибольшая длина названия раздела каталога
+	maxCategoryLength = 64
+	// maxTags - наибольшее число меток теста
+	maxTags = 10
+	// maxTagLength - наибольшая длина метки
+	maxTagLength = 32
+)
+
+// normalizeCatalog проверяет раздел и метки теста. Метки приводятся к нижнему
+// регистру, внутренние пробелы схлопываются, пустые и повторяющиеся метки отбрасываются
+func normalizeCatalog(category string, tags []string) (string, []string, []domainErrors.FieldError) {
+	var problems []domainErrors.FieldError
+
+	category = strings.Join(strings.Fields(category), " ")
+	if utf8.RuneCountInString(category) > maxCategoryLength {
+		problems = append(problems, domainErrors.FieldError{
+			Field:   "category",
+			Message: fmt.Sprintf("Не длиннее %d символов", maxCategoryLength),
+		})
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for index, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			problems = append(problems, domainErrors.FieldError{
+				Field:   fmt.Sprintf("tags[%d]", index),
+				Message: fmt.Sprintf("Метка не длиннее %d символов", maxTagLength),
+			})
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTags {
+		problems = append(problems, domainErrors.FieldError{
+			Field:   "tags",
+			Message: fmt.Sprintf("Не больше %d меток", maxTags),
+		})
+	}
+	return category, normalized, problems
 }
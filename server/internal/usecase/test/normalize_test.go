@@ -0,0 +1,108 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeQuestionIDs(t *testing.T) {
+	question := func(id int) QuestionInput {
+		return QuestionInput{
+			ID:         id,
+			Body:       "Вопрос",
+			SelectType: "one",
+			Options:    []AnswerOptionInput{{Body: "Да"}, {Body: "Нет"}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		ids       []int
+		want      []int
+		wantField string
+	}{
+		{name: "без ID", ids: []int{0, 0, 0}, want: []int{1, 2, 3}},
+		{name: "заданные ID", ids: []int{5, 2, 9}, want: []int{5, 2, 9}},
+		{name: "ID по умолчанию после наибольшего", ids: []int{0, 1, 0}, want: []int{2, 1, 3}},
+		{name: "повтор ID", ids: []int{1, 2, 1}, wantField: "questions[2].id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := make([]QuestionInput, 0, len(tt.ids))
+			for _, id := range tt.ids {
+				raw = append(raw, question(id))
+			}
+
+			questions, problems := normalizeQuestionInputs(raw)
+			if tt.wantField != "" {
+				fields := questionFieldErrors(problems)
+				if len(fields) != 1 || fields[0].Field != tt.wantField {
+					t.Fatalf("problems = %v; want one problem in %s", fields, tt.wantField)
+				}
+				return
+			}
+			if len(problems) > 0 {
+				t.Fatalf("unexpected problems: %v", problems)
+			}
+			got := make([]int, 0, len(questions))
+			for _, q := range questions {
+				got = append(got, q.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IDs = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeOptionIDs(t *testing.T) {
+	tests := []struct {
+		name      string
+		ids       []int
+		want      []int
+		wantField string
+	}{
+		{name: "без ID", ids: []int{0, 0}, want: []int{1, 2}},
+		{name: "ID по умолчанию после наибольшего", ids: []int{0, 1, 0}, want: []int{2, 1, 3}},
+		{name: "отрицательный ID", ids: []int{3, -1}, want: []int{3, 4}},
+		{name: "повтор ID", ids: []int{2, 2}, wantField: "answerOptions[1].id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := make([]AnswerOptionInput, 0, len(tt.ids))
+			rows := make([]MatrixRowInput, 0, len(tt.ids))
+			for _, id := range tt.ids {
+				raw = append(raw, AnswerOptionInput{ID: id, Body: "Вариант"})
+				rows = append(rows, MatrixRowInput{ID: id, Body: "Строка"})
+			}
+
+			options, problem := normalizeOptions(raw)
+			matrixRows, rowProblem := normalizeRows(rows)
+			if tt.wantField != "" {
+				if problem == nil || problem.Field != tt.wantField {
+					t.Fatalf("options problem = %v; want %s", problem, tt.wantField)
+				}
+				if rowProblem == nil {
+					t.Fatalf("rows problem = nil; want duplicate row")
+				}
+				return
+			}
+			if problem != nil || rowProblem != nil {
+				t.Fatalf("unexpected problems: %v, %v", problem, rowProblem)
+			}
+			got := make([]int, 0, len(options))
+			for _, option := range options {
+				got = append(got, option.ID)
+			}
+			gotRows := make([]int, 0, len(matrixRows))
+			for _, row := range matrixRows {
+				gotRows = append(gotRows, row.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) || !reflect.DeepEqual(gotRows, tt.want) {
+				t.Errorf("option IDs = %v, row IDs = %v; want %v", got, gotRows, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// OpenQuestionUseCase - Use Case для отметки о показе вопроса в незавершенной попытке.
+// С этого момента отсчитывается время ответа на вопрос
+type OpenQuestionUseCase struct {
+	testRepo    repository.TestRepository
+	sessionRepo repository.AttemptSessionRepository
+	sessionTTL  time.Duration
+}
+
+// NewOpenQuestionUseCase создает новый экземпляр OpenQuestionUseCase
+func NewOpenQuestionUseCase(
+	testRepo repository.TestRepository,
+	sessionRepo repository.AttemptSessionRepository,
+	sessionTTL time.Duration,
+) *OpenQuestionUseCase {
+	return &OpenQuestionUseCase{
+		testRepo:    testRepo,
+		sessionRepo: sessionRepo,
+		sessionTTL:  sessionTTLOrDefault(sessionTTL),
+	}
+}
+
+// OpenQuestionInput - входные данные для OpenQuestionUseCase
+type OpenQuestionInput struct {
+	AttemptID  string
+	QuestionID int
+}
+
+// OpenQuestionOutput - выходные данные OpenQuestionUseCase.
+// QuestionDeadline - срок ответа на открытый вопрос; nil - без ограничения
+type OpenQuestionOutput struct {
+	Session          entity.AttemptSession
+	QuestionDeadline *time.Time
+}
+
+// Execute запоминает открытый вопрос. Повторное открытие текущего вопроса (например,
+// после перезагрузки страницы) не сбрасывает отсчет
+func (uc *OpenQuestionUseCase) Execute(ctx context.Context, input OpenQuestionInput) (OpenQuestionOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return OpenQuestionOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	session, err := findActiveSession(ctx, uc.sessionRepo, caller, input.AttemptID, now)
+	if err != nil {
+		return OpenQuestionOutput{}, err
+	}
+	if session.IsOverdue(now) {
+		return OpenQuestionOutput{}, domainErrors.NewValidationError(domainErrors.ErrTimeLimit,
+			"Время на прохождение теста истекло")
+	}
+
+	questionsDoc, err := uc.testRepo.FindQuestionsVersion(ctx, session.TestID, session.TestVersion)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return OpenQuestionOutput{}, err
+		}
+		return OpenQuestionOutput{}, domainErrors.ErrDatabase
+	}
+	if _, ok := questionsDoc.Question(input.QuestionID); !ok {
+		return OpenQuestionOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			fmt.Sprintf("Вопрос %d не найден в тесте", input.QuestionID),
+			[]domainErrors.FieldError{{Field: "questionId", Message: "Вопрос не найден"}})
+	}
+
+	if session.CurrentQuestionID != input.QuestionID || session.CurrentQuestionAt == nil {
+		openedAt := now
+		expiresAt := now.Add(uc.sessionTTL)
+		if err := uc.sessionRepo.OpenQuestion(ctx, session.ID, input.QuestionID, now, expiresAt); err != nil {
+			if errors.Is(err, domainErrors.ErrNotFound) {
+				return OpenQuestionOutput{}, domainErrors.ErrAttemptClosed
+			}
+			return OpenQuestionOutput{}, domainErrors.ErrDatabase
+		}
+
+		session.CurrentQuestionID = input.QuestionID
+		session.CurrentQuestionAt = &openedAt
+		if _, ok := session.QuestionOpenedAt[input.QuestionID]; !ok {
+			if session.QuestionOpenedAt == nil {
+				session.QuestionOpenedAt = make(map[int]time.Time, 1)
+			}
+			session.QuestionOpenedAt[input.QuestionID] = now
+		}
+		session.UpdatedAt = now
+		session.ExpiresAt = expiresAt
+	}
+
+	output := OpenQuestionOutput{Session: session}
+	if session.QuestionTimeLimit > 0 {
+		// Срок отсчитывается от первого открытия вопроса, как и при сохранении ответа
+		openedAt, _ := session.QuestionOpened(input.QuestionID)
+		deadline := openedAt.Add(session.QuestionTimeLimit)
+		// Срок ответа на вопрос не выходит за срок всей попытки
+		if session.Deadline != nil && session.Deadline.Before(deadline) {
+			deadline = *session.Deadline
+		}
+		output.QuestionDeadline = &deadline
+	}
+
+	return output, nil
+}
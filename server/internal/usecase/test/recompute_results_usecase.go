@@ -0,0 +1,106 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+	"server/internal/domain/scoring"
+)
+
+// RecomputeResultsUseCase - Use Case для пересчета результатов всех попыток теста
+// (например, после изменения алгоритма подсчета). Каждая попытка пересчитывается
+// по той версии теста, по которой она пройдена
+type RecomputeResultsUseCase struct {
+	testRepo       repository.TestRepository
+	userAnswerRepo repository.UserAnswerRepository
+}
+
+// NewRecomputeResultsUseCase создает новый экземпляр RecomputeResultsUseCase
+func NewRecomputeResultsUseCase(
+	testRepo repository.TestRepository,
+	userAnswerRepo repository.UserAnswerRepository,
+) *RecomputeResultsUseCase {
+	return &RecomputeResultsUseCase{
+		testRepo:       testRepo,
+		userAnswerRepo: userAnswerRepo,
+	}
+}
+
+// RecomputeResultsInput - входные данные для RecomputeResultsUseCase
+type RecomputeResultsInput struct {
+	TestID string
+}
+
+// RecomputeResultsOutput - выходные данные RecomputeResultsUseCase
+type RecomputeResultsOutput struct {
+	Recomputed int
+	Skipped    int
+}
+
+// Execute пересчитывает результаты попыток по сохраненным ответам.
+// Попытки без сохраненных ответов или без своей версии вопросов пропускаются.
+// Пересчет доступен владельцу теста и соавторам-редакторам
+func (uc *RecomputeResultsUseCase) Execute(ctx context.Context, input RecomputeResultsInput) (RecomputeResultsOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return RecomputeResultsOutput{}, err
+	}
+
+	testIDStr := strings.TrimSpace(input.TestID)
+	if testIDStr == "" {
+		return RecomputeResultsOutput{}, domainErrors.ErrInvalidInput
+	}
+	testID := entity.TestID(testIDStr)
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	if _, err := findManagedTest(ctx, uc.testRepo, caller, testID, entity.TestAccessEdit); err != nil {
+		return RecomputeResultsOutput{}, err
+	}
+
+	answers, err := uc.userAnswerRepo.FindByTestID(ctx, testID)
+	if err != nil {
+		return RecomputeResultsOutput{}, err
+	}
+
+	versions := make(map[int]entity.QuestionsDocument)
+	output := RecomputeResultsOutput{}
+	for _, answer := range answers {
+		questionsDoc, ok := versions[answer.TestVersion]
+		if !ok {
+			questionsDoc, err = uc.testRepo.FindQuestionsVersion(ctx, testID, answer.TestVersion)
+			if err != nil {
+				if errors.Is(err, domainErrors.ErrNotFound) {
+					output.Skipped++
+					continue
+				}
+				return output, domainErrors.ErrDatabase
+			}
+			versions[answer.TestVersion] = questionsDoc
+		}
+
+		details, err := uc.userAnswerRepo.FindDetailsByAnswerID(ctx, answer.ID)
+		if err != nil {
+			if errors.Is(err, domainErrors.ErrNotFound) {
+				output.Skipped++
+				continue
+			}
+			return output, err
+		}
+
+		applyResult(&answer, scoring.Evaluate(questionsDoc, details.Answers))
+		if err := uc.userAnswerRepo.UpdateResult(ctx, answer); err != nil {
+			return output, err
+		}
+		output.Recomputed++
+	}
+
+	return output, nil
+}
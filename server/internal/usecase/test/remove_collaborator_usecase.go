@@ -0,0 +1,86 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// RemoveCollaboratorUseCase - Use Case для исключения соавтора теста
+type RemoveCollaboratorUseCase struct {
+	testRepo repository.TestRepository
+}
+
+// NewRemoveCollaboratorUseCase создает новый экземпляр RemoveCollaboratorUseCase
+func NewRemoveCollaboratorUseCase(testRepo repository.TestRepository) *RemoveCollaboratorUseCase {
+	return &RemoveCollaboratorUseCase{
+		testRepo: testRepo,
+	}
+}
+
+// RemoveCollaboratorInput - входные данные для RemoveCollaboratorUseCase.
+// Пустой UserID - вызывающий пользователь отказывается от соавторства
+type RemoveCollaboratorInput struct {
+	TestID string
+	UserID string
+}
+
+// RemoveCollaboratorOutput - выходные данные RemoveCollaboratorUseCase
+type RemoveCollaboratorOutput struct {
+	Test entity.Test
+}
+
+// Execute исключает соавтора теста. Исключить любого соавтора может владелец,
+// соавтор может исключить только себя
+func (uc *RemoveCollaboratorUseCase) Execute(ctx context.Context, input RemoveCollaboratorInput) (RemoveCollaboratorOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return RemoveCollaboratorOutput{}, err
+	}
+
+	testID := entity.TestID(strings.TrimSpace(input.TestID))
+	if testID.IsEmpty() {
+		return RemoveCollaboratorOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Не передан идентификатор теста",
+			[]domainErrors.FieldError{{Field: "testId", Message: "Обязательное поле"}})
+	}
+	userID := entity.UserID(strings.TrimSpace(input.UserID))
+	if userID.IsEmpty() {
+		userID = caller.User.ID
+	}
+
+	// Соавтору для отказа от соавторства достаточно доступа на просмотр
+	level := entity.TestAccessOwner
+	if userID == caller.User.ID {
+		level = entity.TestAccessView
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	test, err := findManagedTest(ctx, uc.testRepo, caller, testID, level)
+	if err != nil {
+		return RemoveCollaboratorOutput{}, err
+	}
+	if _, ok := test.Collaborator(userID); !ok {
+		return RemoveCollaboratorOutput{}, domainErrors.ErrNotFound
+	}
+
+	updated := test
+	updated.Collaborators = make([]entity.Collaborator, 0, len(test.Collaborators))
+	for _, collaborator := range test.Collaborators {
+		if collaborator.UserID != userID {
+			updated.Collaborators = append(updated.Collaborators, collaborator)
+		}
+	}
+
+	if err := saveTestAccess(ctx, uc.testRepo, updated, test.UserID); err != nil {
+		return RemoveCollaboratorOutput{}, err
+	}
+	return RemoveCollaboratorOutput{Test: updated}, nil
+}
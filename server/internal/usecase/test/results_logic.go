@@ -0,0 +1,228 @@
+package test
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+// normalizeResultsLogic нормализует шкалы, диапазоны и правила интерпретации и проверяет
+// их согласованность с весами вариантов ответов
+func normalizeResultsLogic(input ResultsLogicInput, questions []entity.Question) (entity.ResultsLogic, error) {
+	logic := entity.ResultsLogic{
+		Scales: make([]entity.Scale, 0, len(input.Scales)),
+		Rules:  make([]entity.ResultRule, 0, len(input.Rules)),
+	}
+	seen := make(map[entity.ScaleID]struct{}, len(input.Scales))
+
+	for index, scale := range input.Scales {
+		id := entity.ScaleID(strings.TrimSpace(scale.ID))
+		name := strings.TrimSpace(scale.Name)
+		if id == "" {
+			return entity.ResultsLogic{}, scoringError("У шкалы №%d не указан идентификатор", index+1)
+		}
+		if _, duplicate := seen[id]; duplicate {
+			return entity.ResultsLogic{}, scoringError("Шкала %q указана несколько раз", id)
+		}
+		seen[id] = struct{}{}
+
+		if name == "" {
+			name = string(id)
+		}
+
+		bands, err := normalizeBands(id, scale.Bands)
+		if err != nil {
+			return entity.ResultsLogic{}, err
+		}
+
+		logic.Scales = append(logic.Scales, entity.Scale{
+			ID:          id,
+			Name:        name,
+			Description: strings.TrimSpace(scale.Description),
+			Bands:       bands,
+		})
+	}
+
+	for _, question := range questions {
+		for scaleID, weight := range question.Weights {
+			if _, ok := seen[scaleID]; !ok {
+				return entity.ResultsLogic{}, scoringError(
+					"Вопрос %d ссылается на неизвестную шкалу %q", question.ID, scaleID)
+			}
+			if !isFinite(weight) {
+				return entity.ResultsLogic{}, scoringError("Вопрос %d имеет некорректный вес", question.ID)
+			}
+		}
+
+		for _, option := range question.AnswerOptions {
+			for scaleID, weight := range option.Weights {
+				if _, ok := seen[scaleID]; !ok {
+					return entity.ResultsLogic{}, scoringError(
+						"Вариант %d вопроса %d ссылается на неизвестную шкалу %q", option.ID, question.ID, scaleID)
+				}
+				if !isFinite(weight) {
+					return entity.ResultsLogic{}, scoringError(
+						"Вариант %d вопроса %d имеет некорректный вес", option.ID, question.ID)
+				}
+			}
+		}
+	}
+
+	switch mode := entity.RuleMode(strings.TrimSpace(input.RuleMode)); mode {
+	case "", entity.RuleModeFirst, entity.RuleModeAll:
+		logic.RuleMode = mode
+	default:
+		return entity.ResultsLogic{}, scoringError("Неизвестный режим применения правил %q", mode)
+	}
+
+	switch tieBreak := entity.TieBreak(strings.TrimSpace(input.TieBreak)); tieBreak {
+	case "", entity.TieBreakOrder, entity.TieBreakAll, entity.TieBreakNone:
+		logic.TieBreak = tieBreak
+	default:
+		return entity.ResultsLogic{}, scoringError("Неизвестный способ разрешения равенства баллов %q", tieBreak)
+	}
+
+	ruleIDs := make(map[string]struct{}, len(input.Rules))
+	for index, raw := range input.Rules {
+		rule, err := normalizeRule(index, raw, logic)
+		if err != nil {
+			return entity.ResultsLogic{}, err
+		}
+		if _, duplicate := ruleIDs[rule.ID]; duplicate {
+			return entity.ResultsLogic{}, scoringError("Правило %q указано несколько раз", rule.ID)
+		}
+		ruleIDs[rule.ID] = struct{}{}
+		logic.Rules = append(logic.Rules, rule)
+	}
+
+	return logic, nil
+}
+
+// validateTyping проверяет, что тест определения психотипа содержит шкалы:
+// психотипом становится ведущая шкала результата
+func validateTyping(isTyping bool, logic entity.ResultsLogic) error {
+	if isTyping && logic.IsEmpty() {
+		return scoringError("Тест определения психотипа должен содержать хотя бы одну шкалу")
+	}
+	return nil
+}
+
+// normalizeBands проверяет диапазоны шкалы: границы заданы, метки уникальны, диапазоны не пересекаются
+func normalizeBands(scaleID entity.ScaleID, raw []ScoreBandInput) ([]entity.ScoreBand, error) {
+	bands := make([]entity.ScoreBand, 0, len(raw))
+	labels := make(map[string]struct{}, len(raw))
+
+	for index, band := range raw {
+		label := strings.TrimSpace(band.Label)
+		if label == "" {
+			return nil, scoringError("У диапазона №%d шкалы %q не указана метка", index+1, scaleID)
+		}
+		if _, duplicate := labels[label]; duplicate {
+			return nil, scoringError("Диапазон %q шкалы %q указан несколько раз", label, scaleID)
+		}
+		labels[label] = struct{}{}
+
+		if !isFinite(band.Min) || !isFinite(band.Max) || band.Min > band.Max {
+			return nil, scoringError("Диапазон %q шкалы %q имеет некорректные границы", label, scaleID)
+		}
+
+		bands = append(bands, entity.ScoreBand{
+			Min:   band.Min,
+			Max:   band.Max,
+			Label: label,
+			Text:  strings.TrimSpace(band.Text),
+		})
+	}
+
+	// Соседние диапазоны могут делить общую границу: она относится к диапазону,
+	// который с нее начинается. Два диапазона с одним началом пересекаются
+	sorted := make([]entity.ScoreBand, len(bands))
+	copy(sorted, bands)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Min < sorted[j].Min })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Min < sorted[i-1].Max || sorted[i].Min == sorted[i-1].Min {
+			return nil, scoringError("Диапазоны %q и %q шкалы %q пересекаются",
+				sorted[i-1].Label, sorted[i].Label, scaleID)
+		}
+	}
+
+	return bands, nil
+}
+
+// normalizeRule проверяет правило интерпретации: условия ссылаются на существующие шкалы и диапазоны
+func normalizeRule(index int, raw ResultRuleInput, logic entity.ResultsLogic) (entity.ResultRule, error) {
+	id := strings.TrimSpace(raw.ID)
+	if id == "" {
+		id = fmt.Sprintf("rule-%d", index+1)
+	}
+
+	text := strings.TrimSpace(raw.Text)
+	if text == "" {
+		return entity.ResultRule{}, scoringError("У правила %q не указан текст", id)
+	}
+	if len(raw.Conditions) == 0 {
+		return entity.ResultRule{}, scoringError("У правила %q нет условий", id)
+	}
+
+	conditions := make([]entity.RuleCondition, 0, len(raw.Conditions))
+	for _, condition := range raw.Conditions {
+		scaleID := entity.ScaleID(strings.TrimSpace(condition.Scale))
+		scale, ok := logic.Scale(scaleID)
+		if !ok {
+			return entity.ResultRule{}, scoringError("Правило %q ссылается на неизвестную шкалу %q", id, scaleID)
+		}
+
+		band := strings.TrimSpace(condition.Band)
+		if band != "" && !scale.HasBand(band) {
+			return entity.ResultRule{}, scoringError("Правило %q ссылается на неизвестный диапазон %q шкалы %q", id, band, scaleID)
+		}
+
+		if (condition.Min != nil && !isFinite(*condition.Min)) || (condition.Max != nil && !isFinite(*condition.Max)) ||
+			(condition.Min != nil && condition.Max != nil && *condition.Min > *condition.Max) {
+			return entity.ResultRule{}, scoringError("Правило %q задает некорректные границы для шкалы %q", id, scaleID)
+		}
+
+		above := entity.ScaleID(strings.TrimSpace(condition.Above))
+		if above != "" {
+			if above == scaleID {
+				return entity.ResultRule{}, scoringError("Правило %q сравнивает шкалу %q саму с собой", id, scaleID)
+			}
+			if _, ok := logic.Scale(above); !ok {
+				return entity.ResultRule{}, scoringError("Правило %q ссылается на неизвестную шкалу %q", id, above)
+			}
+		}
+
+		if band == "" && condition.Min == nil && condition.Max == nil && above == "" && !condition.Dominant {
+			return entity.ResultRule{}, scoringError("Правило %q содержит пустое условие для шкалы %q", id, scaleID)
+		}
+
+		conditions = append(conditions, entity.RuleCondition{
+			ScaleID:  scaleID,
+			Band:     band,
+			Min:      condition.Min,
+			Max:      condition.Max,
+			Above:    above,
+			Dominant: condition.Dominant,
+		})
+	}
+
+	return entity.ResultRule{
+		ID:         id,
+		Conditions: conditions,
+		Text:       text,
+		Priority:   raw.Priority,
+	}, nil
+}
+
+// scoringError создает ошибку валидации правил подсчета с текстом для пользователя
+func scoringError(format string, args ...any) error {
+	return domainErrors.NewValidationError(domainErrors.ErrInvalidScoring, fmt.Sprintf(format, args...))
+}
+
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
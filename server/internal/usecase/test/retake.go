@@ -0,0 +1,117 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/repository"
+)
+
+// lastCompletions возвращает время последней попытки пользователя по каждому тесту
+func lastCompletions(answers []entity.UserAnswer) map[entity.TestID]time.Time {
+	last := make(map[entity.TestID]time.Time)
+	for _, answer := range answers {
+		if answer.CompletedAt.After(last[answer.TestID]) {
+			last[answer.TestID] = answer.CompletedAt
+		}
+	}
+	return last
+}
+
+// retakeAvailability определяет по времени последней попытки lastAt, можно ли пройти
+// тест в момент now, и когда это станет возможно. Если тест еще не пройден, lastAt пустое
+func retakeAvailability(test entity.Test, lastAt, now time.Time) (bool, *time.Time) {
+	if lastAt.IsZero() || test.Retake.IsUnlimited() {
+		return true, nil
+	}
+	next, ok := test.Retake.NextAttemptAt(lastAt)
+	if !ok {
+		return false, nil
+	}
+	if now.Before(next) {
+		return false, &next
+	}
+	return true, nil
+}
+
+// checkRetake проверяет, что правило повторного прохождения теста позволяет
+// пользователю пройти его в момент now
+func checkRetake(
+	ctx context.Context,
+	userAnswerRepo repository.UserAnswerRepository,
+	userID entity.UserID,
+	test entity.Test,
+	now time.Time,
+) error {
+	if test.Retake.IsUnlimited() {
+		return nil
+	}
+
+	answers, err := userAnswerRepo.FindByUserAndTest(ctx, userID, test.ID)
+	if err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	allowed, next := retakeAvailability(test, lastCompletions(answers)[test.ID], now)
+	if allowed {
+		return nil
+	}
+	if next == nil {
+		return domainErrors.NewValidationError(domainErrors.ErrRetakeDenied,
+			"Тест можно пройти только один раз")
+	}
+	return domainErrors.NewValidationError(domainErrors.ErrRetakeDenied,
+		"Повторно пройти тест можно будет с "+next.Format("02.01.2006 15:04"))
+}
+
+// reserveRetake отмечает прохождение теста пользователем в момент at перед
+// сохранением результата. checkRetake читает прежние результаты и не мешает
+// двум попыткам, отправленным одновременно, сохраниться обе: отметка атомарна,
+// и из одновременных попыток сохраняется только одна
+func reserveRetake(
+	ctx context.Context,
+	userAnswerRepo repository.UserAnswerRepository,
+	userID entity.UserID,
+	test entity.Test,
+	at, now time.Time,
+) error {
+	if test.Retake.IsUnlimited() {
+		return nil
+	}
+
+	// Тест, который проходится только один раз, не допускает никакой прежней отметки
+	var lastBefore time.Time
+	if test.Retake.Mode == entity.RetakeCooldown {
+		lastBefore = now.AddDate(0, 0, -test.Retake.CooldownDays)
+	}
+
+	err := userAnswerRepo.ReserveCompletion(ctx, userID, test.ID, at, lastBefore)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrRetakeDenied) {
+			return domainErrors.NewValidationError(domainErrors.ErrRetakeDenied,
+				"Тест уже пройден в другой попытке")
+		}
+		return domainErrors.ErrDatabase
+	}
+	return nil
+}
+
+// releaseRetake снимает отметку прохождения, если результат попытки не сохранен
+func releaseRetake(
+	ctx context.Context,
+	userAnswerRepo repository.UserAnswerRepository,
+	userID entity.UserID,
+	test entity.Test,
+	at time.Time,
+) {
+	if test.Retake.IsUnlimited() {
+		return
+	}
+	if err := userAnswerRepo.ReleaseCompletion(ctx, userID, test.ID, at); err != nil {
+		log.Printf("retake: не удалось снять отметку прохождения теста %s: %v", test.ID, err)
+	}
+}
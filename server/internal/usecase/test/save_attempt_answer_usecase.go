@@ -0,0 +1,143 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// SaveAttemptAnswerUseCase - Use Case для сохранения ответа на вопрос в незавершенной попытке
+type SaveAttemptAnswerUseCase struct {
+	testRepo    repository.TestRepository
+	sessionRepo repository.AttemptSessionRepository
+	sessionTTL  time.Duration
+}
+
+// NewSaveAttemptAnswerUseCase создает новый экземпляр SaveAttemptAnswerUseCase.
+// Каждый сохраненный ответ продлевает попытку на sessionTTL
+func NewSaveAttemptAnswerUseCase(
+	testRepo repository.TestRepository,
+	sessionRepo repository.AttemptSessionRepository,
+	sessionTTL time.Duration,
+) *SaveAttemptAnswerUseCase {
+	return &SaveAttemptAnswerUseCase{
+		testRepo:    testRepo,
+		sessionRepo: sessionRepo,
+		sessionTTL:  sessionTTLOrDefault(sessionTTL),
+	}
+}
+
+// SaveAttemptAnswerInput - входные данные для SaveAttemptAnswerUseCase.
+// Повторный ответ на тот же вопрос заменяет предыдущий
+type SaveAttemptAnswerInput struct {
+	AttemptID string
+	Answer    entity.QuestionAnswer
+}
+
+// SaveAttemptAnswerOutput - выходные данные SaveAttemptAnswerUseCase
+type SaveAttemptAnswerOutput struct {
+	Session entity.AttemptSession
+}
+
+// Execute проверяет ответ по вопросу той версии теста, с которой начата попытка, и сохраняет его
+// вместе со временем ответа (см. answerTimeSpent). Ответы после истечения времени на тест
+// или на вопрос отклоняются
+func (uc *SaveAttemptAnswerUseCase) Execute(ctx context.Context, input SaveAttemptAnswerInput) (SaveAttemptAnswerOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return SaveAttemptAnswerOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	session, err := findActiveSession(ctx, uc.sessionRepo, caller, input.AttemptID, now)
+	if err != nil {
+		return SaveAttemptAnswerOutput{}, err
+	}
+
+	questionsDoc, err := uc.testRepo.FindQuestionsVersion(ctx, session.TestID, session.TestVersion)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return SaveAttemptAnswerOutput{}, err
+		}
+		return SaveAttemptAnswerOutput{}, domainErrors.ErrDatabase
+	}
+
+	// В попытке с перемешанными вариантами ответ содержит номера показа вариантов
+	answer, ok := session.Layout.Canonical(input.Answer)
+	if !ok {
+		return SaveAttemptAnswerOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidAnswers,
+			"Ответ не соответствует вопросу теста",
+			[]domainErrors.FieldError{{
+				Field:   "answer.optionIds",
+				Message: fmt.Sprintf("Вариант не найден в вопросе %d", answer.QuestionID),
+			}})
+	}
+	if err := validateAnswer(questionsDoc, answer); err != nil {
+		return SaveAttemptAnswerOutput{}, err
+	}
+
+	if session.IsOverdue(now) {
+		return SaveAttemptAnswerOutput{}, domainErrors.NewValidationError(domainErrors.ErrTimeLimit,
+			"Время на прохождение теста истекло")
+	}
+
+	answer.TimeSpent, err = answerTimeSpent(session, answer.QuestionID, now)
+	if err != nil {
+		return SaveAttemptAnswerOutput{}, err
+	}
+
+	expiresAt := now.Add(uc.sessionTTL)
+	if err := uc.sessionRepo.SaveAnswer(ctx, session.ID, answer, now, expiresAt); err != nil {
+		// Попытку успели отправить с другого устройства или она истекла
+		if errors.Is(err, domainErrors.ErrNotFound) {
+			return SaveAttemptAnswerOutput{}, domainErrors.ErrAttemptClosed
+		}
+		return SaveAttemptAnswerOutput{}, domainErrors.ErrDatabase
+	}
+
+	session.SetAnswer(answer)
+	session.UpdatedAt = now
+	session.ExpiresAt = expiresAt
+	session.CurrentQuestionID = 0
+	session.CurrentQuestionAt = nil
+
+	return SaveAttemptAnswerOutput{Session: session}, nil
+}
+
+// answerTimeSpent возвращает время ответа на вопрос. Для открытого вопроса время отсчитывается
+// от его первого открытия (OpenQuestionUseCase), поэтому переход к другим вопросам и изменение
+// ответа его не сбрасывают. При ограничении времени на вопрос отвечать можно только на открытый
+// вопрос и до истечения срока. Без ограничения время ответа на неоткрытый вопрос отсчитывается
+// от последнего действия в попытке и при изменении ответа суммируется
+func answerTimeSpent(session entity.AttemptSession, questionID int, now time.Time) (time.Duration, error) {
+	openedAt, opened := session.QuestionOpened(questionID)
+	if session.QuestionTimeLimit > 0 {
+		if !opened {
+			return 0, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidAnswers,
+				fmt.Sprintf("Вопрос %d не открыт", questionID),
+				[]domainErrors.FieldError{{Field: "answer.questionId", Message: "Сначала откройте вопрос"}})
+		}
+		if session.IsQuestionOverdue(questionID, now) {
+			return 0, domainErrors.NewValidationError(domainErrors.ErrTimeLimit,
+				"Время на ответ на вопрос истекло")
+		}
+	}
+	if opened {
+		return now.Sub(openedAt), nil
+	}
+
+	spent := now.Sub(session.UpdatedAt)
+	if previous, ok := session.Answer(questionID); ok {
+		spent += previous.TimeSpent
+	}
+	return spent, nil
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+)
+
+func TestAnswerTimeSpent(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	limit := time.Minute
+	at := func(offset time.Duration) time.Time { return start.Add(offset) }
+	timePtr := func(v time.Time) *time.Time { return &v }
+
+	tests := []struct {
+		name       string
+		session    entity.AttemptSession
+		questionID int
+		now        time.Time
+		want       time.Duration
+		wantErr    error
+	}{
+		{
+			name: "ответ на первый вопрос после открытия второго и истечения срока",
+			session: entity.AttemptSession{
+				QuestionTimeLimit: limit,
+				UpdatedAt:         at(50 * time.Second),
+				CurrentQuestionID: 2,
+				CurrentQuestionAt: timePtr(at(50 * time.Second)),
+				QuestionOpenedAt:  map[int]time.Time{1: at(0), 2: at(50 * time.Second)},
+			},
+			questionID: 1,
+			now:        at(limit + entity.TimeLimitGrace + time.Second),
+			wantErr:    domainErrors.ErrTimeLimit,
+		},
+		{
+			name: "возврат к первому вопросу в пределах срока",
+			session: entity.AttemptSession{
+				QuestionTimeLimit: limit,
+				UpdatedAt:         at(30 * time.Second),
+				CurrentQuestionID: 2,
+				CurrentQuestionAt: timePtr(at(30 * time.Second)),
+				QuestionOpenedAt:  map[int]time.Time{1: at(0), 2: at(30 * time.Second)},
+			},
+			questionID: 1,
+			now:        at(40 * time.Second),
+			want:       40 * time.Second,
+		},
+		{
+			name: "вопрос не открывался при ограничении времени",
+			session: entity.AttemptSession{
+				QuestionTimeLimit: limit,
+				UpdatedAt:         at(2 * time.Minute),
+				QuestionOpenedAt:  map[int]time.Time{1: at(0)},
+			},
+			questionID: 3,
+			now:        at(2*time.Minute + time.Second),
+			wantErr:    domainErrors.ErrInvalidAnswers,
+		},
+		{
+			name: "текущий вопрос попытки без моментов первого открытия",
+			session: entity.AttemptSession{
+				QuestionTimeLimit: limit,
+				UpdatedAt:         at(10 * time.Second),
+				CurrentQuestionID: 1,
+				CurrentQuestionAt: timePtr(at(10 * time.Second)),
+			},
+			questionID: 1,
+			now:        at(25 * time.Second),
+			want:       15 * time.Second,
+		},
+		{
+			name: "без ограничения время суммируется с прежним ответом",
+			session: entity.AttemptSession{
+				UpdatedAt: at(time.Minute),
+				Answers:   []entity.QuestionAnswer{{QuestionID: 1, TimeSpent: 20 * time.Second}},
+			},
+			questionID: 1,
+			now:        at(time.Minute + 5*time.Second),
+			want:       25 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := answerTimeSpent(tt.session, tt.questionID, tt.now)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v; want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("answerTimeSpent = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
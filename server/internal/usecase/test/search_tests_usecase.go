@@ -0,0 +1,261 @@
+package test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+const (
+	// defaultSearchLimit - размер страницы поиска по умолчанию
+	defaultSearchLimit = 20
+	// maxSearchLimit - наибольший размер страницы поиска
+	maxSearchLimit = 100
+	// maxSearchQueryLength - наибольшая длина текстового запроса
+	maxSearchQueryLength = 200
+)
+
+// CompletionFilter - отбор тестов по тому, проходил ли их пользователь
+type CompletionFilter string
+
+const (
+	CompletionAll          CompletionFilter = "all"
+	CompletionCompleted    CompletionFilter = "completed"
+	CompletionNotCompleted CompletionFilter = "not-completed"
+)
+
+// SearchTestsUseCase - Use Case для поиска опубликованных тестов в каталоге
+type SearchTestsUseCase struct {
+	testRepo       repository.TestRepository
+	userAnswerRepo repository.UserAnswerRepository
+}
+
+// NewSearchTestsUseCase создает новый экземпляр SearchTestsUseCase
+func NewSearchTestsUseCase(
+	testRepo repository.TestRepository,
+	userAnswerRepo repository.UserAnswerRepository,
+) *SearchTestsUseCase {
+	return &SearchTestsUseCase{
+		testRepo:       testRepo,
+		userAnswerRepo: userAnswerRepo,
+	}
+}
+
+// SearchTestsInput - входные данные для SearchTestsUseCase. Все условия необязательны.
+// Sort по умолчанию - relevance при заданном Query, иначе newest. Cursor - значение
+// NextCursor предыдущей страницы; продолжать выдачу нужно с теми же условиями
+type SearchTestsInput struct {
+	Query      string
+	Category   string
+	Tags       []string
+	Completion string
+	Sort       string
+	Limit      int
+	Cursor     string
+}
+
+// SearchTestsOutput - страница результатов поиска. NextCursor пуст на последней странице
+type SearchTestsOutput struct {
+	Tests      []TestWithCompletionDTO
+	NextCursor string
+}
+
+// searchCursor - содержимое курсора страницы поиска. Порядок сортировки хранится
+// в курсоре, чтобы курсор нельзя было применить к выдаче в другом порядке
+type searchCursor struct {
+	Sort  entity.TestSort `json:"s"`
+	ID    string          `json:"i"`
+	Name  string          `json:"n,omitempty"`
+	Score float64         `json:"r,omitempty"`
+}
+
+// Execute выполняет поиск тестов. Отбор по завершенности учитывает попытки
+// авторизованного пользователя; анонимный пользователь не проходил ни одного теста
+func (uc *SearchTestsUseCase) Execute(ctx context.Context, input SearchTestsInput) (SearchTestsOutput, error) {
+	search, completion, problems := normalizeSearch(input)
+	if len(problems) > 0 {
+		return SearchTestsOutput{}, domainErrors.NewFieldValidationError(
+			domainErrors.ErrInvalidInput, problems[0].Message, problems)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Время последней попытки по каждому тесту: по нему определяются завершенность
+	// теста и возможность пройти его повторно
+	lastCompleted := make(map[entity.TestID]time.Time)
+	if caller, ok := identity.CallerFromContext(ctx); ok {
+		answers, err := uc.userAnswerRepo.FindByUserID(ctx, caller.User.ID)
+		if err != nil {
+			return SearchTestsOutput{}, domainErrors.ErrDatabase
+		}
+		lastCompleted = lastCompletions(answers)
+	}
+
+	completedIDs := make([]entity.TestID, 0, len(lastCompleted))
+	for testID := range lastCompleted {
+		completedIDs = append(completedIDs, testID)
+	}
+	switch completion {
+	case CompletionCompleted:
+		if len(completedIDs) == 0 {
+			return SearchTestsOutput{Tests: []TestWithCompletionDTO{}}, nil
+		}
+		search.IncludeIDs = completedIDs
+	case CompletionNotCompleted:
+		search.ExcludeIDs = completedIDs
+	}
+
+	page, err := uc.testRepo.Search(ctx, search)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrInvalidID) {
+			return SearchTestsOutput{}, cursorError()
+		}
+		return SearchTestsOutput{}, domainErrors.ErrDatabase
+	}
+
+	result := make([]TestWithCompletionDTO, 0, len(page.Tests))
+	for _, test := range page.Tests {
+		lastAt, isCompleted := lastCompleted[test.ID]
+		canAttempt, nextAttemptAt := retakeAvailability(test, lastAt, search.Now)
+		result = append(result, TestWithCompletionDTO{
+			Test:          test,
+			IsCompleted:   isCompleted,
+			CanAttempt:    canAttempt,
+			NextAttemptAt: nextAttemptAt,
+		})
+	}
+
+	output := SearchTestsOutput{Tests: result}
+	if page.Next != nil {
+		output.NextCursor = encodeSearchCursor(search.Sort, *page.Next)
+	}
+	return output, nil
+}
+
+// normalizeSearch проверяет условия поиска и переводит их в запрос к репозиторию
+func normalizeSearch(input SearchTestsInput) (entity.TestSearch, CompletionFilter, []domainErrors.FieldError) {
+	var problems []domainErrors.FieldError
+
+	search := entity.TestSearch{
+		Query:    strings.Join(strings.Fields(input.Query), " "),
+		Category: strings.Join(strings.Fields(input.Category), " "),
+		Limit:    input.Limit,
+		Now:      time.Now(),
+	}
+	if len([]rune(search.Query)) > maxSearchQueryLength {
+		problems = append(problems, domainErrors.FieldError{
+			Field:   "query",
+			Message: fmt.Sprintf("Запрос не длиннее %d символов", maxSearchQueryLength),
+		})
+	}
+
+	// Метки сравниваются в том же виде, в котором сохраняются у теста
+	_, tags, _ := normalizeCatalog("", input.Tags)
+	search.Tags = tags
+
+	completion := CompletionFilter(strings.ToLower(strings.TrimSpace(input.Completion)))
+	switch completion {
+	case "":
+		completion = CompletionAll
+	case CompletionAll, CompletionCompleted, CompletionNotCompleted:
+	default:
+		problems = append(problems, domainErrors.FieldError{
+			Field:   "completion",
+			Message: "Допустимые значения: all, completed, not-completed",
+		})
+	}
+
+	search.Sort = entity.TestSort(strings.ToLower(strings.TrimSpace(input.Sort)))
+	switch {
+	case search.Sort == "" && search.Query != "":
+		search.Sort = entity.TestSortRelevance
+	case search.Sort == "":
+		search.Sort = entity.TestSortNewest
+	case !search.Sort.IsValid():
+		problems = append(problems, domainErrors.FieldError{
+			Field:   "sort",
+			Message: "Допустимые значения: relevance, newest, name",
+		})
+	case search.Sort == entity.TestSortRelevance && search.Query == "":
+		problems = append(problems, domainErrors.FieldError{
+			Field:   "sort",
+			Message: "Сортировка по релевантности доступна только с текстовым запросом",
+		})
+	}
+
+	switch {
+	case search.Limit == 0:
+		search.Limit = defaultSearchLimit
+	case search.Limit < 0 || search.Limit > maxSearchLimit:
+		problems = append(problems, domainErrors.FieldError{
+			Field:   "limit",
+			Message: fmt.Sprintf("Допустимо от 1 до %d", maxSearchLimit),
+		})
+	}
+
+	if cursor := strings.TrimSpace(input.Cursor); cursor != "" {
+		after, ok := decodeSearchCursor(cursor, search.Sort)
+		if !ok {
+			problems = append(problems, cursorProblem())
+		}
+		search.After = after
+	}
+
+	return search, completion, problems
+}
+
+// encodeSearchCursor упаковывает положение последнего теста страницы в непрозрачную строку
+func encodeSearchCursor(sort entity.TestSort, next entity.TestCursor) string {
+	raw, _ := json.Marshal(searchCursor{
+		Sort:  sort,
+		ID:    next.ID.String(),
+		Name:  next.Name,
+		Score: next.Score,
+	})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeSearchCursor распаковывает курсор; курсор другого порядка сортировки некорректен
+func decodeSearchCursor(value string, sort entity.TestSort) (*entity.TestCursor, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, false
+	}
+	var cursor searchCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, false
+	}
+	if cursor.Sort != sort || cursor.ID == "" {
+		return nil, false
+	}
+	return &entity.TestCursor{
+		ID:    entity.TestID(cursor.ID),
+		Name:  cursor.Name,
+		Score: cursor.Score,
+	}, true
+}
+
+// cursorProblem - ошибка некорректного курсора страницы
+func cursorProblem() domainErrors.FieldError {
+	return domainErrors.FieldError{
+		Field:   "cursor",
+		Message: "Некорректный курсор; начните поиск с первой страницы",
+	}
+}
+
+// cursorError возвращает ошибку валидации некорректного курсора
+func cursorError() error {
+	problem := cursorProblem()
+	return domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput, problem.Message,
+		[]domainErrors.FieldError{problem})
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// StartAttemptUseCase - Use Case для начала или продолжения попытки прохождения теста
+type StartAttemptUseCase struct {
+	testRepo       repository.TestRepository
+	sessionRepo    repository.AttemptSessionRepository
+	userAnswerRepo repository.UserAnswerRepository
+	sessionTTL     time.Duration
+}
+
+// NewStartAttemptUseCase создает новый экземпляр StartAttemptUseCase.
+// sessionTTL - срок, после которого попытка без изменений истекает
+func NewStartAttemptUseCase(
+	testRepo repository.TestRepository,
+	sessionRepo repository.AttemptSessionRepository,
+	userAnswerRepo repository.UserAnswerRepository,
+	sessionTTL time.Duration,
+) *StartAttemptUseCase {
+	return &StartAttemptUseCase{
+		testRepo:       testRepo,
+		sessionRepo:    sessionRepo,
+		userAnswerRepo: userAnswerRepo,
+		sessionTTL:     sessionTTLOrDefault(sessionTTL),
+	}
+}
+
+// StartAttemptInput - входные данные для StartAttemptUseCase.
+// Version - версия теста из ответа на получение вопросов; новая попытка начинается
+// только по текущей версии. Если не задана, берется текущая
+type StartAttemptInput struct {
+	TestID  string
+	Version int
+}
+
+// StartAttemptOutput - выходные данные StartAttemptUseCase.
+// Resumed - найдена уже начатая попытка; ее ответы возвращаются в Session
+type StartAttemptOutput struct {
+	Session entity.AttemptSession
+	Resumed bool
+}
+
+// Execute возвращает незавершенную попытку пользователя по тесту или начинает новую,
+// если это позволяет правило повторного прохождения теста
+func (uc *StartAttemptUseCase) Execute(ctx context.Context, input StartAttemptInput) (StartAttemptOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return StartAttemptOutput{}, err
+	}
+
+	testIDStr := strings.TrimSpace(input.TestID)
+	if testIDStr == "" {
+		return StartAttemptOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			"Не передан идентификатор теста",
+			[]domainErrors.FieldError{{Field: "testId", Message: "Обязательное поле"}})
+	}
+	testID := entity.TestID(testIDStr)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	test, err := uc.testRepo.FindByID(ctx, testID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return StartAttemptOutput{}, err
+		}
+		return StartAttemptOutput{}, domainErrors.ErrDatabase
+	}
+	if !test.IsAvailable(now) {
+		return StartAttemptOutput{}, domainErrors.ErrTestUnavailable
+	}
+
+	// Начатая попытка продолжается с любого устройства пользователя
+	sessions, err := uc.sessionRepo.FindActiveByUser(ctx, caller.User.ID, now)
+	if err != nil {
+		return StartAttemptOutput{}, domainErrors.ErrDatabase
+	}
+	for _, session := range sessions {
+		if session.TestID == testID {
+			return StartAttemptOutput{Session: session, Resumed: true}, nil
+		}
+	}
+
+	if err := checkRetake(ctx, uc.userAnswerRepo, caller.User.ID, test, now); err != nil {
+		return StartAttemptOutput{}, err
+	}
+
+	// Начатая попытка закрепляет версию, поэтому продолжается по ней и после
+	// изменения теста; новая начинается только по текущей версии
+	questionsDoc, err := currentAttemptQuestions(ctx, uc.testRepo, testID, input.Version)
+	if err != nil {
+		return StartAttemptOutput{}, err
+	}
+
+	session := entity.AttemptSession{
+		UserID:            caller.User.ID,
+		TestID:            testID,
+		TestVersion:       questionsDoc.Version,
+		Answers:           []entity.QuestionAnswer{},
+		StartedAt:         now,
+		UpdatedAt:         now,
+		ExpiresAt:         now.Add(uc.sessionTTL),
+		QuestionTimeLimit: questionsDoc.TimeLimits.PerQuestion,
+		Layout:            newAttemptLayout(questionsDoc),
+	}
+	// Время на весь тест отсчитывается от начала попытки
+	if questionsDoc.TimeLimits.Total > 0 {
+		deadline := now.Add(questionsDoc.TimeLimits.Total)
+		session.Deadline = &deadline
+	}
+
+	session.ID, err = uc.sessionRepo.Insert(ctx, session)
+	if err != nil {
+		return StartAttemptOutput{}, domainErrors.ErrDatabase
+	}
+
+	return StartAttemptOutput{Session: session}, nil
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// SubmitAttemptUseCase - Use Case для отправки незавершенной попытки. Результат
+// подсчитывается и сохраняется так же, как при прохождении теста одним запросом
+type SubmitAttemptUseCase struct {
+	testRepo    repository.TestRepository
+	sessionRepo repository.AttemptSessionRepository
+	attempts    *AttemptTestUseCase
+}
+
+// NewSubmitAttemptUseCase создает новый экземпляр SubmitAttemptUseCase
+func NewSubmitAttemptUseCase(
+	testRepo repository.TestRepository,
+	sessionRepo repository.AttemptSessionRepository,
+	attempts *AttemptTestUseCase,
+) *SubmitAttemptUseCase {
+	return &SubmitAttemptUseCase{
+		testRepo:    testRepo,
+		sessionRepo: sessionRepo,
+		attempts:    attempts,
+	}
+}
+
+// SubmitAttemptInput - входные данные для SubmitAttemptUseCase
+type SubmitAttemptInput struct {
+	AttemptID string
+	Date      string
+}
+
+// Execute проверяет, что в попытке есть ответы на все вопросы, и сохраняет результат.
+// Не ответив на все вопросы, попытку можно отправить только после истечения времени
+// на всю попытку или на каждый вопрос без ответа. Попытка отправляется только один раз
+func (uc *SubmitAttemptUseCase) Execute(ctx context.Context, input SubmitAttemptInput) (AttemptTestOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return AttemptTestOutput{}, err
+	}
+
+	answerDate := strings.TrimSpace(input.Date)
+	if answerDate == "" {
+		answerDate = time.Now().Format("02.01.2006")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	session, err := findActiveSession(ctx, uc.sessionRepo, caller, input.AttemptID, now)
+	if err != nil {
+		return AttemptTestOutput{}, err
+	}
+
+	test, err := uc.testRepo.FindByID(ctx, session.TestID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return AttemptTestOutput{}, err
+		}
+		return AttemptTestOutput{}, domainErrors.ErrDatabase
+	}
+	if !test.IsAvailable(now) {
+		return AttemptTestOutput{}, domainErrors.ErrTestUnavailable
+	}
+	// Пока попытка была открыта, тест могли пройти одним запросом
+	if err := checkRetake(ctx, uc.attempts.userAnswerRepo, caller.User.ID, test, now); err != nil {
+		return AttemptTestOutput{}, err
+	}
+
+	questionsDoc, err := uc.testRepo.FindQuestionsVersion(ctx, session.TestID, session.TestVersion)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return AttemptTestOutput{}, err
+		}
+		return AttemptTestOutput{}, domainErrors.ErrDatabase
+	}
+
+	// Вопросы, на которые не успели ответить, пропускаются
+	answers := routeAnswers(questionsDoc, session.Answers)
+	if isTimeUp(questionsDoc, session, answers, now) {
+		err = validatePartialAnswers(questionsDoc, answers)
+	} else {
+		err = validateAnswers(questionsDoc, answers)
+	}
+	if err != nil {
+		return AttemptTestOutput{}, err
+	}
+
+	// Попытка помечается отправленной до сохранения результата, чтобы одновременная
+	// отправка с двух устройств не создала два результата
+	if err := uc.sessionRepo.MarkSubmitted(ctx, session.ID, now); err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) {
+			return AttemptTestOutput{}, domainErrors.ErrAttemptClosed
+		}
+		return AttemptTestOutput{}, domainErrors.ErrDatabase
+	}
+
+	output, err := uc.attempts.record(ctx, caller, test, questionsDoc, answers, answerDate)
+	if err != nil {
+		// Результат не сохранен: попытку можно отправить повторно
+		_ = uc.sessionRepo.Reopen(ctx, session.ID)
+		return AttemptTestOutput{}, err
+	}
+
+	return output, nil
+}
+
+// isTimeUp проверяет, истекло ли время на всю попытку или на каждый показанный вопрос без ответа
+func isTimeUp(doc entity.QuestionsDocument, session entity.AttemptSession, answers []entity.QuestionAnswer, now time.Time) bool {
+	if session.IsOverdue(now) {
+		return true
+	}
+	if session.QuestionTimeLimit <= 0 {
+		return false
+	}
+	shown := shownQuestions(doc, answers)
+	answered := make(map[int]struct{}, len(answers))
+	for _, answer := range answers {
+		answered[answer.QuestionID] = struct{}{}
+	}
+	for _, question := range doc.Questions {
+		if _, ok := answered[question.ID]; ok || !isShown(shown, question.ID) {
+			continue
+		}
+		if !session.IsQuestionOverdue(question.ID, now) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// TransferOwnershipUseCase - Use Case для передачи владения тестом
+type TransferOwnershipUseCase struct {
+	testRepo repository.TestRepository
+	userRepo repository.UserRepository
+}
+
+// NewTransferOwnershipUseCase создает новый экземпляр TransferOwnershipUseCase
+func NewTransferOwnershipUseCase(testRepo repository.TestRepository, userRepo repository.UserRepository) *TransferOwnershipUseCase {
+	return &TransferOwnershipUseCase{
+		testRepo: testRepo,
+		userRepo: userRepo,
+	}
+}
+
+// TransferOwnershipInput - входные данные для TransferOwnershipUseCase
+type TransferOwnershipInput struct {
+	TestID string
+	UserID string
+}
+
+// TransferOwnershipOutput - выходные данные TransferOwnershipUseCase
+type TransferOwnershipOutput struct {
+	Test entity.Test
+}
+
+// Execute передает владение тестом другому пользователю. Прежний владелец
+// остается соавтором-редактором и может отказаться от соавторства
+func (uc *TransferOwnershipUseCase) Execute(ctx context.Context, input TransferOwnershipInput) (TransferOwnershipOutput, error) {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return TransferOwnershipOutput{}, err
+	}
+
+	testID := entity.TestID(strings.TrimSpace(input.TestID))
+	userID := entity.UserID(strings.TrimSpace(input.UserID))
+	var problems []domainErrors.FieldError
+	if testID.IsEmpty() {
+		problems = append(problems, domainErrors.FieldError{Field: "testId", Message: "Обязательное поле"})
+	}
+	if userID.IsEmpty() {
+		problems = append(problems, domainErrors.FieldError{Field: "userId", Message: "Обязательное поле"})
+	}
+	if len(problems) > 0 {
+		return TransferOwnershipOutput{}, domainErrors.NewFieldValidationError(domainErrors.ErrInvalidInput,
+			problems[0].Message, problems)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	test, err := findManagedTest(ctx, uc.testRepo, caller, testID, entity.TestAccessOwner)
+	if err != nil {
+		return TransferOwnershipOutput{}, err
+	}
+	if test.IsOwnedBy(userID) {
+		return TransferOwnershipOutput{}, userFieldError("Пользователь уже владелец теста")
+	}
+	if _, err := findTestAuthor(ctx, uc.userRepo, userID); err != nil {
+		return TransferOwnershipOutput{}, err
+	}
+
+	updated := test
+	updated.UserID = userID
+	updated.Collaborators = make([]entity.Collaborator, 0, len(test.Collaborators)+1)
+	for _, collaborator := range test.Collaborators {
+		if collaborator.UserID != userID {
+			updated.Collaborators = append(updated.Collaborators, collaborator)
+		}
+	}
+	if !test.UserID.IsEmpty() {
+		updated.Collaborators = append(updated.Collaborators, entity.Collaborator{
+			UserID:  test.UserID,
+			Role:    entity.CollaboratorEditor,
+			AddedAt: time.Now(),
+		})
+	}
+
+	if err := saveTestAccess(ctx, uc.testRepo, updated, test.UserID); err != nil {
+		return TransferOwnershipOutput{}, err
+	}
+	return TransferOwnershipOutput{Test: updated}, nil
+}
@@ -0,0 +1,80 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/repository"
+	"server/internal/domain/service"
+)
+
+// AuthenticateUseCase реализует use case проверки токена доступа
+type AuthenticateUseCase struct {
+	userRepo     repository.UserRepository
+	tokenManager service.TokenManager
+	timeout      time.Duration
+}
+
+// NewAuthenticateUseCase создает новый экземпляр AuthenticateUseCase
+func NewAuthenticateUseCase(
+	userRepo repository.UserRepository,
+	tokenManager service.TokenManager,
+) *AuthenticateUseCase {
+	return &AuthenticateUseCase{
+		userRepo:     userRepo,
+		tokenManager: tokenManager,
+		timeout:      5 * time.Second,
+	}
+}
+
+// Execute проверяет подпись токена, наличие сессии и статус пользователя
+func (uc *AuthenticateUseCase) Execute(ctx context.Context, input AuthenticateInput) (AuthenticateOutput, error) {
+	token := strings.TrimSpace(input.Token)
+	if token == "" {
+		return AuthenticateOutput{}, domainErrors.ErrUnauthorized
+	}
+
+	// Проверка подписи и срока действия токена
+	claims, err := uc.tokenManager.Parse(token)
+	if err != nil {
+		return AuthenticateOutput{}, domainErrors.ErrUnauthorized
+	}
+
+	// Создание контекста с таймаутом
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	// Поиск владельца токена
+	user, err := uc.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrUserNotFound) || errors.Is(err, domainErrors.ErrInvalidID) {
+			return AuthenticateOutput{}, domainErrors.ErrUnauthorized
+		}
+		return AuthenticateOutput{}, domainErrors.ErrDatabase
+	}
+
+	// Заблокированные и удаленные пользователи теряют доступ сразу
+	if !user.IsActive() {
+		return AuthenticateOutput{}, domainErrors.ErrUnauthorized
+	}
+
+	// Сессия должна существовать: так работает отзыв токенов при выходе
+	now := time.Now()
+	if !user.HasActiveSession(claims.SessionID, now) {
+		return AuthenticateOutput{}, domainErrors.ErrUnauthorized
+	}
+
+	// Истекшие сессии удаляются, когда пользователь уже прочитан, чтобы массив
+	// не рос бесконечно; запись выполняется, только если такие сессии есть
+	if user.HasExpiredSessions(now) {
+		if err := uc.userRepo.RemoveExpiredSessions(ctx, user.ID, now); err != nil {
+			log.Printf("authenticate: не удалось удалить истекшие сессии пользователя %s: %v", user.ID, err)
+		}
+	}
+
+	return AuthenticateOutput{User: user, SessionID: claims.SessionID}, nil
+}
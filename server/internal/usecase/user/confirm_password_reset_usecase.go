@@ -0,0 +1,93 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/repository"
+	"server/internal/domain/service"
+)
+
+// ConfirmPasswordResetUseCase реализует use case для установки нового пароля по токену сброса
+type ConfirmPasswordResetUseCase struct {
+	userRepo       repository.UserRepository
+	resetRepo      repository.PasswordResetRepository
+	passwordHasher service.PasswordHasher
+	timeout        time.Duration
+}
+
+// NewConfirmPasswordResetUseCase создает новый экземпляр ConfirmPasswordResetUseCase
+func NewConfirmPasswordResetUseCase(
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetRepository,
+	passwordHasher service.PasswordHasher,
+) *ConfirmPasswordResetUseCase {
+	return &ConfirmPasswordResetUseCase{
+		userRepo:       userRepo,
+		resetRepo:      resetRepo,
+		passwordHasher: passwordHasher,
+		timeout:        5 * time.Second,
+	}
+}
+
+// Execute проверяет токен сброса, устанавливает новый пароль и завершает все сессии пользователя
+func (uc *ConfirmPasswordResetUseCase) Execute(ctx context.Context, input ConfirmPasswordResetInput) error {
+	// Нормализация входных данных
+	token := strings.TrimSpace(input.Token)
+	password := strings.TrimSpace(input.Password)
+	passwordRepeat := strings.TrimSpace(input.PasswordRepeat)
+
+	// Валидация входных данных
+	if token == "" || password == "" || passwordRepeat == "" {
+		return domainErrors.ErrInvalidInput
+	}
+	if password != passwordRepeat {
+		return domainErrors.ErrPasswordsMatch
+	}
+
+	// Создание контекста с таймаутом
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	reset, err := uc.resetRepo.FindByTokenHash(ctx, hashResetToken(token))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !reset.IsValid(now) {
+		return domainErrors.ErrInvalidToken
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, reset.UserID)
+	if err != nil {
+		return err
+	}
+	if err := user.CanLogin(); err != nil {
+		return err
+	}
+
+	hash, err := uc.passwordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	// Пометка использования выполняется атомарно: параллельный запрос с тем же токеном получит ошибку
+	if err := uc.resetRepo.MarkUsed(ctx, reset.ID, now); err != nil {
+		return err
+	}
+
+	if err := uc.userRepo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return err
+	}
+
+	// Все выданные ранее токены доступа перестают действовать
+	if err := uc.userRepo.RemoveAllSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// Остальные ссылки на сброс пароля также аннулируются
+	return uc.resetRepo.DeleteByUser(ctx, user.ID)
+}
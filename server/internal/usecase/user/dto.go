@@ -1,6 +1,16 @@
 package user
 
-import "server/internal/domain/entity"
+import (
+	"time"
+
+	"server/internal/domain/entity"
+)
+
+// AccessToken описывает выданный пользователю токен доступа
+type AccessToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
 
 // LoginInput описывает входные данные для входа в систему
 type LoginInput struct {
@@ -10,7 +20,8 @@ type LoginInput struct {
 
 // LoginOutput описывает результат входа в систему
 type LoginOutput struct {
-	User entity.User
+	User        entity.User
+	AccessToken AccessToken
 }
 
 // RegisterInput описывает входные данные для регистрации пользователя
@@ -25,3 +36,54 @@ type RegisterInput struct {
 type RegisterOutput struct {
 	Success bool
 }
+
+// AuthenticateInput описывает входные данные для проверки токена доступа
+type AuthenticateInput struct {
+	Token string
+}
+
+// AuthenticateOutput описывает пользователя, которому принадлежит токен
+type AuthenticateOutput struct {
+	User      entity.User
+	SessionID entity.SessionID
+}
+
+// RequestPasswordResetInput описывает входные данные для запроса сброса пароля
+type RequestPasswordResetInput struct {
+	Email string
+}
+
+// ConfirmPasswordResetInput описывает входные данные для установки нового пароля
+type ConfirmPasswordResetInput struct {
+	Token          string
+	Password       string
+	PasswordRepeat string
+}
+
+// StartOAuthInput описывает входные данные для начала входа через провайдера
+type StartOAuthInput struct {
+	Provider string
+}
+
+// StartOAuthOutput описывает адрес авторизации провайдера и подписанный state.
+// Nonce до ExpiresAt хранится у браузера, начавшего вход, и предъявляется при его завершении
+type StartOAuthOutput struct {
+	AuthorizationURL string
+	State            string
+	Nonce            string
+	ExpiresAt        time.Time
+}
+
+// OAuthLoginInput описывает код авторизации, возвращенный провайдером,
+// и nonce, сохраненный браузером при начале входа
+type OAuthLoginInput struct {
+	Provider string
+	Code     string
+	State    string
+	Nonce    string
+}
+
+// UnlinkOAuthInput описывает провайдера, которого нужно отвязать от аккаунта
+type UnlinkOAuthInput struct {
+	Provider string
+}
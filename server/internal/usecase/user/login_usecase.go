@@ -8,25 +8,39 @@ import (
 
 	domainErrors "server/internal/domain/errors"
 	"server/internal/domain/repository"
+	"server/internal/domain/service"
 
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // LoginUseCase реализует use case для входа пользователя в систему
 type LoginUseCase struct {
-	userRepo repository.UserRepository
-	timeout  time.Duration
+	userRepo       repository.UserRepository
+	passwordHasher service.PasswordHasher
+	sessions       sessionIssuer
+	timeout        time.Duration
 }
 
 // NewLoginUseCase создает новый экземпляр LoginUseCase
-func NewLoginUseCase(userRepo repository.UserRepository) *LoginUseCase {
+func NewLoginUseCase(
+	userRepo repository.UserRepository,
+	passwordHasher service.PasswordHasher,
+	tokenManager service.TokenManager,
+	tokenTTL time.Duration,
+) *LoginUseCase {
 	return &LoginUseCase{
-		userRepo: userRepo,
-		timeout:  5 * time.Second,
+		userRepo:       userRepo,
+		passwordHasher: passwordHasher,
+		sessions: sessionIssuer{
+			userRepo:     userRepo,
+			tokenManager: tokenManager,
+			tokenTTL:     tokenTTL,
+		},
+		timeout: 5 * time.Second,
 	}
 }
 
-// Execute выполняет вход пользователя с проверкой email и пароля
+// Execute выполняет вход пользователя с проверкой email и пароля и выдает токен доступа
 func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (LoginOutput, error) {
 	// Нормализация входных данных
 	email := strings.TrimSpace(strings.ToLower(input.Email))
@@ -53,15 +67,36 @@ func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (LoginOut
 		return LoginOutput{}, domainErrors.ErrDatabase
 	}
 
-	// Проверка возможности входа (статус и пароль)
-	if err := user.CanLogin(password); err != nil {
+	// Проверка возможности входа по статусу
+	if err := user.CanLogin(); err != nil {
 		return LoginOutput{}, err
 	}
 
+	// Проверка пароля (поддерживаются и устаревшие пароли в открытом виде)
+	if !uc.passwordHasher.Verify(user.Password, password) {
+		return LoginOutput{}, domainErrors.ErrWrongPassword
+	}
+
+	// Перехеширование устаревшего пароля: ошибка не мешает входу,
+	// попытка повторится при следующей авторизации
+	if uc.passwordHasher.NeedsRehash(user.Password) {
+		if hash, err := uc.passwordHasher.Hash(password); err == nil {
+			if err := uc.userRepo.UpdatePassword(ctx, user.ID, hash); err == nil {
+				user.Password = hash
+			}
+		}
+	}
+
 	// Дополнительная проверка активности пользователя
 	if !user.IsActive() {
 		return LoginOutput{}, domainErrors.ErrUserNotFound
 	}
 
-	return LoginOutput{User: user}, nil
+	// Создание сессии и выпуск токена доступа
+	accessToken, err := uc.sessions.issue(ctx, user.ID)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+
+	return LoginOutput{User: user, AccessToken: accessToken}, nil
 }
@@ -0,0 +1,41 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// LogoutUseCase реализует use case выхода из системы (отзыв текущей сессии)
+type LogoutUseCase struct {
+	userRepo repository.UserRepository
+	timeout  time.Duration
+}
+
+// NewLogoutUseCase создает новый экземпляр LogoutUseCase
+func NewLogoutUseCase(userRepo repository.UserRepository) *LogoutUseCase {
+	return &LogoutUseCase{
+		userRepo: userRepo,
+		timeout:  5 * time.Second,
+	}
+}
+
+// Execute удаляет сессию, от имени которой выполнен запрос
+func (uc *LogoutUseCase) Execute(ctx context.Context) error {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	if err := uc.userRepo.RemoveSession(ctx, caller.User.ID, caller.SessionID); err != nil {
+		return domainErrors.ErrDatabase
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package user
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+	"server/internal/domain/service"
+)
+
+// OAuthLoginUseCase реализует use case для входа и привязки аккаунта через внешнего провайдера
+type OAuthLoginUseCase struct {
+	userRepo     repository.UserRepository
+	providers    map[entity.OAuthProvider]service.OAuthClient
+	stateManager service.OAuthStateManager
+	sessions     sessionIssuer
+	timeout      time.Duration
+}
+
+// NewOAuthLoginUseCase создает новый экземпляр OAuthLoginUseCase
+func NewOAuthLoginUseCase(
+	userRepo repository.UserRepository,
+	providers map[entity.OAuthProvider]service.OAuthClient,
+	stateManager service.OAuthStateManager,
+	tokenManager service.TokenManager,
+	tokenTTL time.Duration,
+) *OAuthLoginUseCase {
+	return &OAuthLoginUseCase{
+		userRepo:     userRepo,
+		providers:    providers,
+		stateManager: stateManager,
+		sessions: sessionIssuer{
+			userRepo:     userRepo,
+			tokenManager: tokenManager,
+			tokenTTL:     tokenTTL,
+		},
+		timeout: 15 * time.Second,
+	}
+}
+
+// Execute обменивает код авторизации на профиль провайдера и выполняет вход:
+// по ранее привязанной учетной записи, по совпадающему подтвержденному email
+// или с созданием нового пользователя. Привязка к аккаунту выполняется только
+// для того же авторизованного пользователя, который начал вход
+func (uc *OAuthLoginUseCase) Execute(ctx context.Context, input OAuthLoginInput) (LoginOutput, error) {
+	provider := entity.OAuthProvider(strings.TrimSpace(strings.ToLower(input.Provider)))
+	code := strings.TrimSpace(input.Code)
+	if !provider.IsValid() || code == "" || strings.TrimSpace(input.State) == "" {
+		return LoginOutput{}, domainErrors.ErrInvalidInput
+	}
+
+	// Проверка state защищает от подмены ответа провайдера (CSRF): state должен быть
+	// подписан сервером и выдан тому же браузеру, что предъявил nonce
+	state, err := uc.stateManager.Parse(input.State)
+	if err != nil || state.Provider != provider {
+		return LoginOutput{}, domainErrors.ErrInvalidToken
+	}
+	if input.Nonce == "" || subtle.ConstantTimeCompare([]byte(state.Nonce), []byte(input.Nonce)) != 1 {
+		return LoginOutput{}, domainErrors.ErrInvalidToken
+	}
+
+	// Привязать чужую учетную запись провайдера можно только из своего аккаунта
+	if !state.LinkTo.IsEmpty() {
+		caller, ok := identity.CallerFromContext(ctx)
+		if !ok || caller.User.ID != state.LinkTo {
+			return LoginOutput{}, domainErrors.ErrUnauthorized
+		}
+	}
+
+	client, ok := uc.providers[provider]
+	if !ok {
+		return LoginOutput{}, domainErrors.ErrProviderNotConfigured
+	}
+
+	// Создание контекста с таймаутом
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	profile, err := client.Exchange(ctx, code)
+	if err != nil {
+		return LoginOutput{}, domainErrors.ErrProviderExchange
+	}
+
+	var user entity.User
+	if !state.LinkTo.IsEmpty() {
+		user, err = uc.linkToUser(ctx, state.LinkTo, profile)
+	} else {
+		user, err = uc.resolveUser(ctx, profile)
+	}
+	if err != nil {
+		return LoginOutput{}, err
+	}
+
+	// Создание сессии и выпуск токена доступа
+	accessToken, err := uc.sessions.issue(ctx, user.ID)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+
+	return LoginOutput{User: user, AccessToken: accessToken}, nil
+}
+
+// linkToUser привязывает учетную запись провайдера к пользователю, начавшему вход из своего аккаунта
+func (uc *OAuthLoginUseCase) linkToUser(ctx context.Context, userID entity.UserID, profile entity.ExternalProfile) (entity.User, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return entity.User{}, err
+	}
+	if err := user.CanLogin(); err != nil {
+		return entity.User{}, err
+	}
+
+	owner, err := uc.userRepo.FindByExternalAccount(ctx, profile.Provider, profile.Subject)
+	switch {
+	case err == nil && owner.ID != user.ID:
+		return entity.User{}, domainErrors.ErrAccountLinked
+	case err != nil && !errors.Is(err, domainErrors.ErrUserNotFound):
+		return entity.User{}, err
+	}
+
+	return uc.link(ctx, user.ID, profile)
+}
+
+// resolveUser находит или создает пользователя по профилю провайдера
+func (uc *OAuthLoginUseCase) resolveUser(ctx context.Context, profile entity.ExternalProfile) (entity.User, error) {
+	// Учетная запись уже привязана
+	user, err := uc.userRepo.FindByExternalAccount(ctx, profile.Provider, profile.Subject)
+	if err == nil {
+		if err := user.CanLogin(); err != nil {
+			return entity.User{}, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, domainErrors.ErrUserNotFound) {
+		return entity.User{}, err
+	}
+
+	// Связывать по email можно только подтвержденный провайдером адрес
+	if profile.Email == "" || !profile.EmailVerified {
+		return entity.User{}, domainErrors.ErrEmailNotVerified
+	}
+
+	user, err = uc.userRepo.FindByEmail(ctx, profile.Email)
+	switch {
+	case err == nil:
+		if err := user.CanLogin(); err != nil {
+			return entity.User{}, err
+		}
+	case errors.Is(err, domainErrors.ErrUserNotFound):
+		if user, err = uc.createUser(ctx, profile); err != nil {
+			return entity.User{}, err
+		}
+	default:
+		return entity.User{}, err
+	}
+
+	return uc.link(ctx, user.ID, profile)
+}
+
+// createUser создает пользователя без пароля по профилю провайдера
+func (uc *OAuthLoginUseCase) createUser(ctx context.Context, profile entity.ExternalProfile) (entity.User, error) {
+	firstName := strings.TrimSpace(profile.FirstName)
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(profile.Email, "@")
+	}
+
+	newUser := entity.User{
+		FirstName:  firstName,
+		LastName:   strings.TrimSpace(profile.LastName),
+		Email:      profile.Email,
+		Status:     entity.UserStatusUser,
+		Role:       entity.RoleUser,
+		PsychoType: DefaultPsychoType,
+		Date:       time.Now().Format("02.01.2006"),
+		Sessions:   []entity.Session{},
+	}
+
+	if err := uc.userRepo.Insert(ctx, newUser); err != nil {
+		return entity.User{}, domainErrors.ErrDatabase
+	}
+
+	return uc.userRepo.FindByEmail(ctx, profile.Email)
+}
+
+// link сохраняет привязку и возвращает обновленного пользователя
+func (uc *OAuthLoginUseCase) link(ctx context.Context, userID entity.UserID, profile entity.ExternalProfile) (entity.User, error) {
+	err := uc.userRepo.LinkExternalAccount(ctx, userID, entity.ExternalAccount{
+		Provider: profile.Provider,
+		Subject:  profile.Subject,
+		Email:    profile.Email,
+		LinkedAt: time.Now(),
+	})
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return uc.userRepo.FindByID(ctx, userID)
+}
@@ -9,6 +9,7 @@ import (
 	"server/internal/domain/entity"
 	domainErrors "server/internal/domain/errors"
 	"server/internal/domain/repository"
+	"server/internal/domain/service"
 
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -20,15 +21,20 @@ const (
 
 // RegisterUseCase реализует use case для регистрации нового пользователя
 type RegisterUseCase struct {
-	userRepo repository.UserRepository
-	timeout  time.Duration
+	userRepo       repository.UserRepository
+	passwordHasher service.PasswordHasher
+	timeout        time.Duration
 }
 
 // NewRegisterUseCase создает новый экземпляр RegisterUseCase
-func NewRegisterUseCase(userRepo repository.UserRepository) *RegisterUseCase {
+func NewRegisterUseCase(
+	userRepo repository.UserRepository,
+	passwordHasher service.PasswordHasher,
+) *RegisterUseCase {
 	return &RegisterUseCase{
-		userRepo: userRepo,
-		timeout:  5 * time.Second,
+		userRepo:       userRepo,
+		passwordHasher: passwordHasher,
+		timeout:        5 * time.Second,
 	}
 }
 
@@ -75,17 +81,27 @@ func (uc *RegisterUseCase) Execute(ctx context.Context, input RegisterInput) (Re
 		}
 	}
 
+	// Хеширование пароля: в базе не хранится открытый текст
+	passwordHash, err := uc.passwordHasher.Hash(password)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrInvalidInput) {
+			return RegisterOutput{}, domainErrors.ErrInvalidInput
+		}
+		return RegisterOutput{}, err
+	}
+
 	// Создание нового пользователя
 	newUser := entity.User{
 		FirstName:     firstName,
 		Email:         email,
 		Status:        entity.UserStatusUser,
-		Password:      password,
+		Role:          entity.RoleUser,
+		Password:      passwordHash,
 		PsychoType:    DefaultPsychoType,
 		Date:          time.Now().Format("02.01.2006"),
 		IsGoogleAdded: false,
 		IsYandexAdded: false,
-		Sessions:      []interface{}{},
+		Sessions:      []entity.Session{},
 	}
 
 	// Сохранение пользователя в репозиторий
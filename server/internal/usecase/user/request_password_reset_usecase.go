@@ -0,0 +1,145 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/repository"
+	"server/internal/domain/service"
+)
+
+// RequestPasswordResetUseCase реализует use case для запроса сброса пароля
+type RequestPasswordResetUseCase struct {
+	userRepo  repository.UserRepository
+	resetRepo repository.PasswordResetRepository
+	mailer    service.Mailer
+	tokenTTL  time.Duration
+	resetURL  string
+	timeout   time.Duration
+}
+
+// NewRequestPasswordResetUseCase создает новый экземпляр RequestPasswordResetUseCase.
+// resetURL - адрес страницы клиента, к которому добавляется параметр token
+func NewRequestPasswordResetUseCase(
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetRepository,
+	mailer service.Mailer,
+	tokenTTL time.Duration,
+	resetURL string,
+) *RequestPasswordResetUseCase {
+	return &RequestPasswordResetUseCase{
+		userRepo:  userRepo,
+		resetRepo: resetRepo,
+		mailer:    mailer,
+		tokenTTL:  tokenTTL,
+		resetURL:  resetURL,
+		timeout:   10 * time.Second,
+	}
+}
+
+// Execute создает одноразовый токен сброса и отправляет ссылку на почту пользователя.
+// Для неизвестных, удаленных и заблокированных адресов ошибка не возвращается, а ошибка
+// отправки письма только записывается в журнал, чтобы по ответу нельзя было определить,
+// зарегистрирован ли email
+func (uc *RequestPasswordResetUseCase) Execute(ctx context.Context, input RequestPasswordResetInput) error {
+	// Нормализация и валидация входных данных
+	email := strings.TrimSpace(strings.ToLower(input.Email))
+	if email == "" {
+		return domainErrors.ErrInvalidInput
+	}
+	if !strings.Contains(email, "@") {
+		return domainErrors.ErrInvalidEmail
+	}
+
+	// Создание контекста с таймаутом
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	user, err := uc.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrUserNotFound) {
+			return nil
+		}
+		return domainErrors.ErrDatabase
+	}
+	if user.CanLogin() != nil {
+		return nil
+	}
+
+	token, err := newResetToken()
+	if err != nil {
+		return err
+	}
+
+	// Предыдущие ссылки пользователя перестают действовать
+	if err := uc.resetRepo.DeleteByUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	reset := entity.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hashResetToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(uc.tokenTTL),
+	}
+	if err := uc.resetRepo.Insert(ctx, reset); err != nil {
+		return err
+	}
+
+	err = uc.mailer.Send(ctx, service.MailMessage{
+		To:      user.Email,
+		Subject: "Восстановление пароля",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nДля установки нового пароля перейдите по ссылке:\n%s\n\n"+
+				"Ссылка действует до %s и может быть использована один раз.\n"+
+				"Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.",
+			user.FirstName,
+			uc.resetLink(token),
+			reset.ExpiresAt.Format("02.01.2006 15:04 MST"),
+		),
+	})
+	if err != nil {
+		log.Printf("password reset: не удалось отправить письмо пользователю %s: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// resetLink добавляет токен к адресу страницы сброса пароля
+func (uc *RequestPasswordResetUseCase) resetLink(token string) string {
+	link, err := url.Parse(uc.resetURL)
+	if err != nil {
+		return uc.resetURL + "?token=" + url.QueryEscape(token)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// newResetToken генерирует случайный токен сброса пароля
+func newResetToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashResetToken возвращает хеш токена, под которым он хранится в базе
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,63 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/repository"
+	"server/internal/domain/service"
+)
+
+// sessionIssuer создает сессию пользователя и выпускает привязанный к ней токен доступа
+type sessionIssuer struct {
+	userRepo     repository.UserRepository
+	tokenManager service.TokenManager
+	tokenTTL     time.Duration
+}
+
+// issue сохраняет новую сессию пользователя и возвращает токен доступа
+func (s sessionIssuer) issue(ctx context.Context, userID entity.UserID) (AccessToken, error) {
+	sessionID, err := newSessionID()
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	now := time.Now()
+	session := entity.Session{
+		ID:        sessionID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.tokenTTL),
+	}
+
+	if err := s.userRepo.AddSession(ctx, userID, session); err != nil {
+		return AccessToken{}, domainErrors.ErrDatabase
+	}
+
+	token, err := s.tokenManager.Issue(service.TokenClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		IssuedAt:  session.CreatedAt,
+		ExpiresAt: session.ExpiresAt,
+	})
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	return AccessToken{
+		Token:     token,
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// newSessionID генерирует случайный идентификатор сессии
+func newSessionID() (entity.SessionID, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return entity.SessionID(hex.EncodeToString(buf)), nil
+}
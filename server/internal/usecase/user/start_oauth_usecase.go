@@ -0,0 +1,74 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/service"
+)
+
+// StartOAuthUseCase реализует use case для получения адреса авторизации провайдера
+type StartOAuthUseCase struct {
+	providers    map[entity.OAuthProvider]service.OAuthClient
+	stateManager service.OAuthStateManager
+	stateTTL     time.Duration
+}
+
+// NewStartOAuthUseCase создает новый экземпляр StartOAuthUseCase
+func NewStartOAuthUseCase(
+	providers map[entity.OAuthProvider]service.OAuthClient,
+	stateManager service.OAuthStateManager,
+	stateTTL time.Duration,
+) *StartOAuthUseCase {
+	return &StartOAuthUseCase{
+		providers:    providers,
+		stateManager: stateManager,
+		stateTTL:     stateTTL,
+	}
+}
+
+// Execute формирует адрес авторизации. Если запрос выполнен авторизованным пользователем,
+// state привязывает результат входа к его аккаунту
+func (uc *StartOAuthUseCase) Execute(ctx context.Context, input StartOAuthInput) (StartOAuthOutput, error) {
+	provider := entity.OAuthProvider(strings.TrimSpace(strings.ToLower(input.Provider)))
+	if !provider.IsValid() {
+		return StartOAuthOutput{}, domainErrors.ErrInvalidInput
+	}
+
+	client, ok := uc.providers[provider]
+	if !ok {
+		return StartOAuthOutput{}, domainErrors.ErrProviderNotConfigured
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return StartOAuthOutput{}, err
+	}
+
+	state := service.OAuthState{
+		Provider:  provider,
+		Nonce:     hex.EncodeToString(nonce),
+		ExpiresAt: time.Now().Add(uc.stateTTL),
+	}
+	if caller, ok := identity.CallerFromContext(ctx); ok {
+		state.LinkTo = caller.User.ID
+	}
+
+	encodedState, err := uc.stateManager.Issue(state)
+	if err != nil {
+		return StartOAuthOutput{}, err
+	}
+
+	return StartOAuthOutput{
+		AuthorizationURL: client.AuthorizationURL(encodedState),
+		State:            encodedState,
+		Nonce:            state.Nonce,
+		ExpiresAt:        state.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"server/internal/domain/entity"
+	domainErrors "server/internal/domain/errors"
+	"server/internal/domain/identity"
+	"server/internal/domain/repository"
+)
+
+// UnlinkOAuthUseCase реализует use case для отвязки внешнего провайдера от аккаунта
+type UnlinkOAuthUseCase struct {
+	userRepo repository.UserRepository
+	timeout  time.Duration
+}
+
+// NewUnlinkOAuthUseCase создает новый экземпляр UnlinkOAuthUseCase
+func NewUnlinkOAuthUseCase(userRepo repository.UserRepository) *UnlinkOAuthUseCase {
+	return &UnlinkOAuthUseCase{
+		userRepo: userRepo,
+		timeout:  5 * time.Second,
+	}
+}
+
+// Execute отвязывает провайдера от аккаунта текущего пользователя.
+// Последний способ входа отвязать нельзя: сначала нужно задать пароль
+func (uc *UnlinkOAuthUseCase) Execute(ctx context.Context, input UnlinkOAuthInput) error {
+	caller, err := identity.RequireCaller(ctx)
+	if err != nil {
+		return err
+	}
+
+	provider := entity.OAuthProvider(strings.TrimSpace(strings.ToLower(input.Provider)))
+	if !provider.IsValid() {
+		return domainErrors.ErrInvalidInput
+	}
+
+	if !caller.User.IsProviderLinked(provider) {
+		return domainErrors.ErrNotFound
+	}
+	if !caller.User.CanUnlink(provider) {
+		return domainErrors.ErrLastLoginMethod
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	return uc.userRepo.UnlinkExternalAccount(ctx, caller.User.ID, provider)
+}